@@ -1,508 +1,233 @@
 package main
 
 import (
-	"encoding/binary"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"github.com/google/uuid"
-	"github.com/linkedin/goavro/v2"
+	"io"
 	"ktea/config"
 	"ktea/kadmin"
+	"ktea/serdes"
 	"ktea/sradmin"
-	"math/big"
-	"strconv"
-	"sync"
+	"os"
+	"os/signal"
+	"strings"
 	"time"
 )
 
-type eventGenFunc func(id string) interface{}
+const usage = `Seed the local cluster with dev topics and schemas, or generate load on a topic with templated or
+schema generated payloads.
 
-type generationData struct {
-	topic   string
-	subject string
-	schema  []string
-	eventGenFunc
-}
+Usage:
+  go run -tags prd ./cmd/generate
+  go run -tags prd ./cmd/generate -topic <topic> (-records <n> | -duration <d>) [options]
 
-func main() {
-	genData := []generationData{
-		{"dev.finance.invoice",
-			"dev.finance.invoice-io.jonasg.ktea.invoice.InvoiceCreated",
-			[]string{`
-			{
-				"type": "record",
-				"name": "InvoiceCreated",
-				"namespace": "io.jonasg.ktea.invoice",
-				"doc": "Schema for the InvoiceCreated event.",
-				"fields": [
-					{"name": "id", "type": "string", "doc": "Unique identifier for the invoice."},
-					{"name": "customerId", "type": "string", "doc": "Unique identifier for the customer."},
-					{"name": "amount", "type": "bytes", "logicalType": "decimal", "precision": 4, "scale": 2, "doc": "Total amount of the invoice."},
-					{"name": "currency", "type": "string", "doc": "Currency of the invoice amount."},
-					{"name": "issueDate", "type": "string", "doc": "Date when the invoice was issued, in ISO 8601 format."},
-					{"name": "dueDate", "type": "string", "doc": "Date when the invoice is due, in ISO 8601 format."},
-					{"name": "status", "type": "string", "doc": "Current status of the invoice (e.g., 'Paid', 'Pending')."},
-					{"name": "description", "type": "string", "doc": "Description or notes about the invoice."}
-				]
-			}
-		`},
-			func(id string) interface{} {
-				return map[string]interface{}{
-					"id":          id,
-					"customerId":  uuid.New().String(),
-					"amount":      big.NewRat(100, 100),
-					"currency":    "USD",
-					"issueDate":   time.Now().Format(time.RFC3339),
-					"dueDate":     time.Now().AddDate(0, 0, 30).Format(time.RFC3339),
-					"status":      "Pending",
-					"description": "Invoice for services rendered.",
-				}
-			},
-		},
+Templates are Go templates with the placeholders {{uuid}}, {{now}}, {{nowMillis}},
+{{int <min> <max>}}, {{pick "a" "b" ...}} and {{.Seq}}, the number of the record.
 
-		{
-			"dev.finance.payment",
-			"dev.finance.payment-io.jonasg.ktea.payment.PaymentProcessed",
-			[]string{`
-	{
-		"type": "record",
-		"name": "PaymentProcessed",
-		"namespace": "io.jonasg.ktea.payment",
-		"doc": "Schema for the PaymentProcessed event.",
-		"fields": [
-			{"name": "id", "type": "string", "doc": "Unique identifier for the payment."},
-			{"name": "invoiceId", "type": "string", "doc": "Unique identifier for the associated invoice."},
-			{"name": "amount", "type": "bytes", "logicalType": "decimal", "precision": 4, "scale": 2, "doc": "Amount of the payment."},
-			{"name": "currency", "type": "string", "doc": "Currency of the payment amount."},
-			{"name": "paymentDate", "type": "string", "doc": "Date when the payment was made, in ISO 8601 format."},
-			{"name": "status", "type": "string", "doc": "Current status of the payment (e.g., 'Completed', 'Failed')."},
-			{"name": "method", "type": "string", "doc": "Payment method used (e.g., 'Credit Card', 'Bank Transfer')."}
-		]
-	}
-	`},
-			func(id string) interface{} {
-				return map[string]interface{}{
-					"id":          id,
-					"invoiceId":   uuid.New().String(),
-					"amount":      big.NewRat(500, 100),
-					"currency":    "USD",
-					"paymentDate": time.Now().Format(time.RFC3339),
-					"status":      "Completed",
-					"method":      "Credit Card",
-				}
-			},
-		},
-
-		{
-			"dev.order.checkout",
-			"dev.order.checkout-io.jonasg.ktea.order.CheckoutInitiated",
-			[]string{`
-	{
-		"type": "record",
-		"name": "CheckoutInitiated",
-		"namespace": "io.jonasg.ktea.order",
-		"doc": "Schema for the CheckoutInitiated event.",
-		"fields": [
-			{"name": "id", "type": "string", "doc": "Unique identifier for the checkout."},
-			{"name": "cartId", "type": "string", "doc": "Unique identifier for the cart."},
-			{"name": "totalAmount", "type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2, "doc": "Total amount for the checkout."},
-			{"name": "currency", "type": "string", "doc": "Currency of the total amount."},
-			{"name": "checkoutDate", "type": "string", "doc": "Date when the checkout was initiated, in ISO 8601 format."}
-		]
-	}
-	`},
-			func(id string) interface{} {
-				return map[string]interface{}{
-					"id":           id,
-					"cartId":       uuid.New().String(),
-					"totalAmount":  big.NewRat(1500, 100),
-					"currency":     "USD",
-					"checkoutDate": time.Now().Format(time.RFC3339),
-				}
-			},
-		},
-		{
-			"dev.order.shipment",
-			"dev.order.shipment-io.jonasg.ktea.order.ShipmentCreated",
-			[]string{`
-	{
-		"type": "record",
-		"name": "ShipmentCreated",
-		"namespace": "io.jonasg.ktea.order",
-		"doc": "Schema for the ShipmentCreated event.",
-		"fields": [
-			{"name": "id", "type": "string", "doc": "Unique identifier for the shipment."},
-			{"name": "orderId", "type": "string", "doc": "Unique identifier for the order."},
-			{"name": "shipmentDate", "type": "string", "doc": "Date when the shipment was created, in ISO 8601 format."},
-			{"name": "carrier", "type": "string", "doc": "Carrier responsible for the shipment."},
-			{"name": "trackingNumber", "type": "string", "doc": "Tracking number for the shipment."}
-		]
-	}
-	`},
-			func(id string) interface{} {
-				return map[string]interface{}{
-					"id":             id,
-					"orderId":        uuid.New().String(),
-					"shipmentDate":   time.Now().Format(time.RFC3339),
-					"carrier":        "FedEx",
-					"trackingNumber": "123456789",
-				}
-			},
-		},
-		{
-			"dev.product.stock",
-			"dev.product.stock-io.jonasg.ktea.product.StockUpdated",
-			[]string{`
-	{
-		"type": "record",
-		"name": "StockUpdated",
-		"namespace": "io.jonasg.ktea.product",
-		"doc": "Schema for the StockUpdated event.",
-		"fields": [
-			{"name": "id", "type": "string", "doc": "Unique identifier for the stock update."},
-			{"name": "productId", "type": "string", "doc": "Unique identifier for the product."},
-			{"name": "quantity", "type": "int", "doc": "Quantity of the product in stock."},
-			{"name": "updateDate", "type": "string", "doc": "Date when the stock was updated, in ISO 8601 format."}
-		]
-	}
-	`},
-			func(id string) interface{} {
-				return map[string]interface{}{
-					"id":         id,
-					"productId":  uuid.New().String(),
-					"quantity":   100,
-					"updateDate": time.Now().Format(time.RFC3339),
-				}
-			},
-		},
-		{
-			"dev.product.category",
-			"dev.product.category-io.jonasg.ktea.product.CategoryAssigned",
-			[]string{`
-	{
-		"type": "record",
-		"name": "CategoryAssigned",
-		"namespace": "io.jonasg.ktea.product",
-		"doc": "Schema for the CategoryAssigned event.",
-		"fields": [
-			{"name": "id", "type": "string", "doc": "Unique identifier for the category assignment."},
-			{"name": "productId", "type": "string", "doc": "Unique identifier for the product."},
-			{"name": "category", "type": "string", "doc": "Category assigned to the product."},
-			{"name": "assignmentDate", "type": "string", "doc": "Date when the category was assigned, in ISO 8601 format."}
-		]
-	}
-	`},
-			func(id string) interface{} {
-				return map[string]interface{}{
-					"id":             id,
-					"productId":      uuid.New().String(),
-					"category":       "Electronics",
-					"assignmentDate": time.Now().Format(time.RFC3339),
-				}
-			},
-		},
-		{
-			"dev.product.price",
-			"dev.product.price-io.jonasg.ktea.product.PriceUpdated",
-			[]string{`
-	{
-		"type": "record",
-		"name": "PriceUpdated",
-		"namespace": "io.jonasg.ktea.product",
-		"doc": "Schema for the PriceUpdated event.",
-		"fields": [
-			{"name": "id", "type": "string", "doc": "Unique identifier for the price update."},
-			{"name": "productId", "type": "string", "doc": "Unique identifier for the product."},
-			{"name": "price", "type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2, "doc": "Updated price of the product."},
-			{"name": "currency", "type": "string", "doc": "Currency of the price."},
-			{"name": "updateDate", "type": "string", "doc": "Date when the price was updated, in ISO 8601 format."}
-		]
-	}
-	`},
-			func(id string) interface{} {
-				return map[string]interface{}{
-					"id":         id,
-					"productId":  uuid.New().String(),
-					"price":      big.NewRat(2000, 100),
-					"currency":   "USD",
-					"updateDate": time.Now().Format(time.RFC3339),
-				}
-			},
-		},
-		{
-			"dev.customer.profile",
-			"dev.customer.profile-io.jonasg.ktea.customer.ProfileUpdated",
-			[]string{`
-	{
-		"type": "record",
-		"name": "ProfileUpdated",
-		"namespace": "io.jonasg.ktea.customer",
-		"doc": "Schema for the ProfileUpdated event.",
-		"fields": [
-			{"name": "id", "type": "string", "doc": "Unique identifier for the profile update."},
-			{"name": "customerId", "type": "string", "doc": "Unique identifier for the customer."},
-			{"name": "updateDate", "type": "string", "doc": "Date when the profile was updated, in ISO 8601 format."},
-			{"name": "changes", "type": "string", "doc": "Description of the changes made to the profile."}
-		]
-	}
-	`},
-			func(id string) interface{} {
-				return map[string]interface{}{
-					"id":         id,
-					"customerId": uuid.New().String(),
-					"updateDate": time.Now().Format(time.RFC3339),
-					"changes":    "Updated email address and phone number.",
-				}
-			},
-		},
-		{
-			"dev.customer.action",
-			"dev.customer.action-io.jonasg.ktea.customer.ActionLogged",
-			[]string{
-				`
-	{
-		"type": "record",
-		"name": "ActionLogged",
-		"namespace": "io.jonasg.ktea.customer",
-		"doc": "Schema for the ActionLogged event.",
-		"fields": [
-			{"name": "id", "type": "string", "doc": "Unique identifier for the action log."},
-			{"name": "customerId", "type": "string", "doc": "Unique identifier for the customer."},
-			{"name": "origin", "type": "string", "doc": "Original of the action."},
-			{"name": "platform", "type": "string", "doc": "Platform from which the action was performed."},
-			{"name": "action", "type": "string", "doc": "Description of the action performed by the customer."},
-			{"name": "actionDate", "type": "string", "doc": "Date when the action was performed, in ISO 8601 format."}
-		]
-	}
-	`,
-				`
-	{
-		"type": "record",
-		"name": "ActionLogged",
-		"namespace": "io.jonasg.ktea.customer",
-		"doc": "Schema for the ActionLogged event.",
-		"fields": [
-			{"name": "id", "type": "string", "doc": "Unique identifier for the action log."},
-			{"name": "customerId", "type": "string", "doc": "Unique identifier for the customer."},
-			{"name": "origin", "type": "string", "doc": "Original of the action."},
-			{"name": "action", "type": "string", "doc": "Description of the action performed by the customer."},
-			{"name": "actionDate", "type": "string", "doc": "Date when the action was performed, in ISO 8601 format."}
-		]
-	}
-	`,
-				`
-	{
-		"type": "record",
-		"name": "ActionLogged",
-		"namespace": "io.jonasg.ktea.customer",
-		"doc": "Schema for the ActionLogged event.",
-		"fields": [
-			{"name": "id", "type": "string", "doc": "Unique identifier for the action log."},
-			{"name": "customerId", "type": "string", "doc": "Unique identifier for the customer."},
-			{"name": "action", "type": "string", "doc": "Description of the action performed by the customer."},
-			{"name": "actionDate", "type": "string", "doc": "Date when the action was performed, in ISO 8601 format."}
-		]
-	}
-	`},
-			func(id string) interface{} {
-				return map[string]interface{}{
-					"id":         id,
-					"customerId": uuid.New().String(),
-					"action":     "Logged in to the system.",
-					"actionDate": time.Now().Format(time.RFC3339),
-				}
-			},
-		},
-		{
-			"dev.customer.feedback",
-			"dev.customer.feedback-io.jonasg.ktea.customer.FeedbackReceived",
-			[]string{`
-	{
-		"type": "record",
-		"name": "FeedbackReceived",
-		"namespace": "io.jonasg.ktea.customer",
-		"doc": "Schema for the FeedbackReceived event.",
-		"fields": [
-			{"name": "id", "type": "string", "doc": "Unique identifier for the feedback."},
-			{"name": "customerId", "type": "string", "doc": "Unique identifier for the customer."},
-			{"name": "feedback", "type": "string", "doc": "Content of the feedback provided by the customer."},
-			{"name": "feedbackDate", "type": "string", "doc": "Date when the feedback was provided, in ISO 8601 format."}
-		]
-	}
-	`},
-			func(id string) interface{} {
-				return map[string]interface{}{
-					"id":           id,
-					"customerId":   uuid.New().String(),
-					"feedback":     "Great service!",
-					"feedbackDate": time.Now().Format(time.RFC3339),
-				}
-			},
-		},
-	}
+Example:
+  go run -tags prd ./cmd/generate -topic orders -records 10000 -rate 500 \
+    -key '{{uuid}}' -value '{"id":{{.Seq}},"amount":{{int 1 100}},"status":"{{pick "NEW" "PAID"}}"}'
 
-	ka, sa := getAdmins()
+Options:
+`
 
-	wg := sync.WaitGroup{}
-	wg.Add(len(genData))
+var acks = map[string]kadmin.Acks{
+	"all":    kadmin.AcksAll,
+	"leader": kadmin.AcksLeader,
+	"none":   kadmin.AcksNone,
+}
 
-	for _, gd := range genData {
-		go func() {
-			defer wg.Done()
-			if !topicExists(ka, gd.topic) {
-				createTopic(ka, gd.topic)
-			}
+var compressions = map[string]kadmin.Compression{
+	"none":   kadmin.CompressionNone,
+	"gzip":   kadmin.CompressionGzip,
+	"snappy": kadmin.CompressionSnappy,
+	"lz4":    kadmin.CompressionLz4,
+	"zstd":   kadmin.CompressionZstd,
+}
 
-			if !subjectExists(sa, gd.subject) {
-				for _, s := range gd.schema {
-					registerSchema(sa, gd.subject, s)
-				}
-			}
+type options struct {
+	cluster        string
+	bootstrap      string
+	schemaRegistry string
+	details        kadmin.LoadDetails
+}
 
-			schemaInfo := getLatestSchema(sa, gd.subject)
+func parseFlags(args []string, output io.Writer) (options, error) {
+	var (
+		opts        options
+		valueFile   string
+		subject     string
+		version     int
+		message     string
+		acksName    string
+		compression string
+	)
+	opts.details.HeaderTemplates = map[string]string{}
+
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.Usage = func() {
+		fmt.Fprint(output, usage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&opts.cluster, "cluster", "", "name of the cluster in the ktea config, the active cluster by default")
+	flags.StringVar(&opts.bootstrap, "bootstrap", "", "comma separated bootstrap servers, instead of a configured cluster")
+	flags.StringVar(&opts.schemaRegistry, "schema-registry", "", "url of the Schema Registry, together with -bootstrap")
+	flags.StringVar(&opts.details.Topic, "topic", "", "topic to publish to")
+	flags.IntVar(&opts.details.Records, "records", 0, "number of records to publish")
+	flags.DurationVar(&opts.details.Duration, "duration", 0, "duration to publish for, like 30s or 5m")
+	flags.IntVar(&opts.details.Rate, "rate", 0, "target records per second, as fast as possible when 0")
+	flags.IntVar(&opts.details.Concurrency, "concurrency", kadmin.DefaultLoadConcurrency, "number of records in flight")
+	flags.StringVar(&opts.details.KeyTemplate, "key", "", "key template, null keys when empty")
+	flags.StringVar(&opts.details.ValueTemplate, "value", "", "value template")
+	flags.StringVar(&valueFile, "value-file", "", "file with the value template")
+	flags.Func("header", "header as key=template, repeatable", func(header string) error {
+		key, template, found := strings.Cut(header, "=")
+		if !found {
+			return fmt.Errorf("'%s' is not in the format key=template", header)
+		}
+		opts.details.HeaderTemplates[key] = template
+		return nil
+	})
+	flags.StringVar(&subject, "subject", "", "generate random values matching the schema of the subject")
+	flags.IntVar(&version, "version", serdes.LatestVersion, "version of the subject, the latest when 0")
+	flags.StringVar(&message, "message", "", "Protobuf message, the first message of the schema when empty")
+	flags.StringVar(&acksName, "acks", "all", "all, leader or none")
+	flags.StringVar(&compression, "compression", "none", "none, gzip, snappy, lz4 or zstd")
+	flags.BoolVar(&opts.details.Settings.Idempotent, "idempotent", false, "publish with an idempotent producer")
 
-			for i := 0; i < 1000; i++ {
-				id := uuid.New().String()
-				event := gd.eventGenFunc(id)
-				publish(ka, gd.topic, id, event, schemaInfo)
-			}
-			fmt.Printf("Published 10.000 events to topic %s with subject %s\n", gd.topic, gd.subject)
-		}()
+	if err := flags.Parse(args); err != nil {
+		return opts, err
 	}
 
-	wg.Wait()
-}
-
-func publish(ka kadmin.Kadmin, topic string, id string, event interface{}, schemaInfo sradmin.Schema) {
-	//personJson, _ := json.Marshal(event)
-	codec, _ := goavro.NewCodec(schemaInfo.Value)
-	valueBytes, err := codec.BinaryFromNative(nil, event)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to convert JSON to native Avro: %v", err))
+	if opts.details.Topic == "" {
+		return opts, errors.New("-topic is required")
 	}
-	//valueBytes, _ := codec.BinaryFromNative(nil, native)
-	schemaId, err := strconv.Atoi(schemaInfo.Id)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to convert schema ID to bytes: %v", err))
+	if opts.details.Records <= 0 && opts.details.Duration <= 0 {
+		return opts, errors.New("-records or -duration is required")
 	}
-	schemaIDBytes := make([]byte, 4)
-	binary.BigEndian.PutUint32(schemaIDBytes, uint32(schemaId))
 
-	var record []byte
-	record = append(record, byte(0))
-	record = append(record, schemaIDBytes...)
-	record = append(record, valueBytes...)
-
-	msg := ka.PublishRecord(&kadmin.ProducerRecord{
-		Key:       id,
-		Value:     record,
-		Topic:     topic,
-		Partition: nil,
-		Headers: map[string]string{
-			"content-type": "application/vnd.apache.avro+json",
-			"eventId":      id,
-			"eventType":    "ProductCreated",
-			"eventSource":  "ktea",
-			"eventVersion": "1.0",
-			"eventTime":    time.Now().String(),
-		},
-	})
-	switch msg := msg.AwaitCompletion().(type) {
-	case kadmin.PublicationSucceeded:
-	case kadmin.PublicationFailed:
-		panic(fmt.Sprintf("Failed to publish message %v", msg.Err))
+	var ok bool
+	if opts.details.Settings.Acks, ok = acks[acksName]; !ok {
+		return opts, fmt.Errorf("unknown acks %s", acksName)
+	}
+	if opts.details.Settings.Compression, ok = compressions[compression]; !ok {
+		return opts, fmt.Errorf("unknown compression %s", compression)
 	}
-}
-
-func getLatestSchema(sa sradmin.SrAdmin, subject string) sradmin.Schema {
-	msg := sa.GetLatestSchemaBySubject(subject).(sradmin.FetchingLatestSchemaBySubjectMsg)
 
-	var schemaInfo sradmin.Schema
-	switch msg := msg.AwaitCompletion().(type) {
-	case sradmin.LatestSchemaBySubjectReceived:
-		fmt.Println("Latest schema fetched successfully for subject:", subject)
-		schemaInfo = msg.Schema
-	case sradmin.FailedToFetchLatestSchemaBySubject:
-		panic(fmt.Sprintf("Failed to get latest schema by subject: %v", msg.Err))
+	switch {
+	case subject != "":
+		opts.details.ValueSchema = &serdes.SchemaSelection{Subject: subject, Version: version, Message: message}
+	case valueFile != "":
+		template, err := os.ReadFile(valueFile)
+		if err != nil {
+			return opts, err
+		}
+		opts.details.ValueTemplate = string(template)
+	case opts.details.ValueTemplate == "":
+		return opts, errors.New("-value, -value-file or -subject is required")
 	}
-	return schemaInfo
+	return opts, nil
 }
 
-func registerSchema(srAdmin sradmin.SrAdmin, subject string, schema string) {
-	msg := srAdmin.CreateSchema(sradmin.SubjectCreationDetails{
-		Subject: subject,
-		Schema:  schema,
-	}).(sradmin.SchemaCreationStartedMsg)
-
-	switch msg := msg.AwaitCompletion().(type) {
-	case sradmin.SchemaCreatedMsg:
-		fmt.Println("Schema created successfully for subject:", subject)
-	case sradmin.SchemaCreationErrMsg:
-		panic(fmt.Sprintf("Failed to create schema for subject %s: %v", subject, msg.Err))
+// connect to the bootstrap servers, or else the configured cluster.
+func connect(opts options) (kadmin.Kadmin, error) {
+	var (
+		cd  kadmin.ConnectionDetails
+		sra sradmin.SrAdmin
+	)
+	if opts.bootstrap != "" {
+		cd.BootstrapServers = strings.Split(opts.bootstrap, ",")
+		if opts.schemaRegistry != "" {
+			sra = sradmin.New(&config.SchemaRegistryConfig{Url: opts.schemaRegistry})
+		}
+	} else {
+		cfg := config.New(config.NewDefaultIO())
+		cluster := cfg.ActiveCluster()
+		if opts.cluster != "" {
+			cluster = nil
+			for i := range cfg.Clusters {
+				if cfg.Clusters[i].Name == opts.cluster {
+					cluster = &cfg.Clusters[i]
+				}
+			}
+		}
+		if cluster == nil {
+			return nil, errors.New("no cluster found, configure one in ktea or use -bootstrap")
+		}
+		cd = kadmin.ToConnectionDetails(cluster)
+		if cluster.HasSchemaRegistry() {
+			sra = sradmin.New(cluster.SchemaRegistry)
+		}
 	}
-}
 
-func getAdmins() (kadmin.Kadmin, sradmin.SrAdmin) {
-	ka, err := kadmin.NewSaramaKadmin(kadmin.ConnectionDetails{
-		BootstrapServers: []string{"localhost:9092"},
-		SASLConfig:       nil,
-		SSLEnabled:       false,
-	})
+	ka, err := kadmin.NewSaramaKadmin(cd)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to create Kafka admin client: %v", err))
+		return nil, err
 	}
-
-	sa := sradmin.New(&config.SchemaRegistryConfig{
-		Url:      "http://localhost:8081",
-		Username: "",
-		Password: "",
-	})
-
-	return ka, sa
+	if sra != nil {
+		ka.SetSra(sra)
+	}
+	return ka, nil
 }
 
-func createTopic(ka kadmin.Kadmin, topic string) {
-	tm := ka.CreateTopic(kadmin.TopicCreationDetails{
-		Name:              topic,
-		NumPartitions:     1,
-		ReplicationFactor: 1,
-	}).(kadmin.TopicCreationStartedMsg)
+func formatStats(stats kadmin.LoadStats) string {
+	return fmt.Sprintf(
+		"published %d, failed %d in %s: %.1f records/s, latency p50 %s p95 %s p99 %s max %s",
+		stats.Published,
+		stats.Failed,
+		stats.Elapsed.Round(time.Millisecond),
+		stats.Throughput(),
+		stats.P50.Round(time.Microsecond),
+		stats.P95.Round(time.Microsecond),
+		stats.P99.Round(time.Microsecond),
+		stats.Max.Round(time.Microsecond),
+	)
+}
 
-	switch msg := tm.AwaitCompletion().(type) {
-	case kadmin.TopicCreatedMsg:
-		fmt.Printf("Topic %s created successfully", topic)
-	case kadmin.TopicCreationErrMsg:
-		panic(fmt.Sprintf("Failed to create topic: %v", msg.Err))
+func main() {
+	// without flags the local cluster of the docker-compose setup is seeded
+	if len(os.Args) == 1 {
+		seed()
+		return
 	}
-}
 
-func topicExists(ka kadmin.Kadmin, expectedTopic string) bool {
-	msg := ka.ListTopics().(kadmin.TopicListingStartedMsg)
-	switch msg := msg.AwaitTopicListCompletion().(type) {
-	case kadmin.TopicsListedMsg:
-		topics := msg.Topics
-		for _, topic := range topics {
-			if topic.Name == expectedTopic {
-				fmt.Println("Topic " + expectedTopic + " already exists")
-				return true
-			}
-		}
-	case kadmin.TopicListedErrorMsg:
-		panic(fmt.Sprintf("Failed to list topics: %v", msg.Err))
+	opts, err := parseFlags(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
 	}
-	return false
-}
 
-func subjectExists(srAdmin sradmin.SrAdmin, subject string) bool {
-	msg := srAdmin.ListSubjects().(sradmin.SubjectListingStartedMsg)
-	switch msg := msg.AwaitCompletion().(type) {
-	case sradmin.SubjectsListedMsg:
-		for _, s := range msg.Subjects {
-			if s.Name == subject {
-				return true
+	ka, err := connect(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "unable to connect:", err)
+		os.Exit(1)
+	}
+
+	// ctrl+c stops publishing and reports the results so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	startedMsg := ka.GenerateLoad(ctx, opts.details).(kadmin.LoadStartedMsg)
+	for {
+		switch msg := startedMsg.AwaitActivity().(type) {
+		case kadmin.LoadProgressMsg:
+			fmt.Fprintln(os.Stderr, formatStats(msg.Stats))
+		case kadmin.LoadGeneratedMsg:
+			fmt.Println(formatStats(msg.Stats))
+			if msg.Stats.LastErr != nil {
+				fmt.Fprintln(os.Stderr, "last error:", msg.Stats.LastErr)
+				os.Exit(1)
 			}
+			return
+		case kadmin.LoadGenerationErrMsg:
+			fmt.Fprintln(os.Stderr, msg.Err)
+			os.Exit(1)
 		}
-	case sradmin.SubjectListingErrorMsg:
-		panic(fmt.Sprintf("Failed to list subjects: %v", msg.Err))
 	}
-	return false
 }
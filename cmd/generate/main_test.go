@@ -0,0 +1,103 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"ktea/kadmin"
+	"ktea/serdes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseFlags(t *testing.T) {
+	t.Run("templated load", func(t *testing.T) {
+		opts, err := parseFlags([]string{
+			"-topic", "orders",
+			"-records", "1000",
+			"-rate", "50",
+			"-key", "{{uuid}}",
+			"-value", `{"id":{{.Seq}}}`,
+			"-header", "source=load",
+			"-header", "run={{now}}",
+			"-acks", "leader",
+			"-compression", "zstd",
+		}, io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, kadmin.LoadDetails{
+			Topic:         "orders",
+			Records:       1000,
+			Rate:          50,
+			KeyTemplate:   "{{uuid}}",
+			ValueTemplate: `{"id":{{.Seq}}}`,
+			HeaderTemplates: map[string]string{
+				"source": "load",
+				"run":    "{{now}}",
+			},
+			Settings: kadmin.ProducerSettings{
+				Acks:        kadmin.AcksLeader,
+				Compression: kadmin.CompressionZstd,
+			},
+			Concurrency: kadmin.DefaultLoadConcurrency,
+		}, opts.details)
+	})
+
+	t.Run("schema generated values for a duration", func(t *testing.T) {
+		opts, err := parseFlags([]string{
+			"-topic", "orders",
+			"-duration", "30s",
+			"-subject", "orders-value",
+			"-version", "2",
+		}, io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 30*time.Second, opts.details.Duration)
+		assert.Equal(t, &serdes.SchemaSelection{Subject: "orders-value", Version: 2}, opts.details.ValueSchema)
+	})
+
+	t.Run("value template from a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "order.json")
+		assert.NoError(t, os.WriteFile(path, []byte(`{"id":"{{uuid}}"}`), 0644))
+
+		opts, err := parseFlags([]string{"-topic", "orders", "-records", "1", "-value-file", path}, io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{"id":"{{uuid}}"}`, opts.details.ValueTemplate)
+	})
+
+	t.Run("invalid flags", func(t *testing.T) {
+		for _, tc := range []struct {
+			args []string
+			err  string
+		}{
+			{[]string{"-records", "1", "-value", "v"}, "-topic is required"},
+			{[]string{"-topic", "orders", "-value", "v"}, "-records or -duration is required"},
+			{[]string{"-topic", "orders", "-records", "1"}, "-value, -value-file or -subject is required"},
+			{[]string{"-topic", "orders", "-records", "1", "-value", "v", "-acks", "some"}, "unknown acks some"},
+			{[]string{"-topic", "orders", "-records", "1", "-value", "v", "-header", "source"}, "'source' is not in the format key=template"},
+		} {
+			_, err := parseFlags(tc.args, io.Discard)
+
+			assert.ErrorContains(t, err, tc.err)
+		}
+	})
+}
+
+func TestFormatStats(t *testing.T) {
+	stats := kadmin.LoadStats{
+		Published: 500,
+		Failed:    1,
+		Elapsed:   2 * time.Second,
+		P50:       2 * time.Millisecond,
+		P95:       5 * time.Millisecond,
+		P99:       9 * time.Millisecond,
+		Max:       12 * time.Millisecond,
+	}
+
+	assert.Equal(t,
+		"published 500, failed 1 in 2s: 250.0 records/s, latency p50 2ms p95 5ms p99 9ms max 12ms",
+		formatStats(stats),
+	)
+}
@@ -293,7 +293,11 @@ func (m *Model) boostrapUI(cluster *config.Cluster) tea.Cmd {
 		}
 		m.cgroupsTabCtrl, cmd = cgroups_tab.New(m.ka, m.ka, m.ka)
 		cmds = append(cmds, cmd)
-		m.topicsTabCtrl, cmd = topics_tab.New(m.ktx, m.ka)
+		var sra sradmin.SrAdmin
+		if m.ktx.Config.ActiveCluster().HasSchemaRegistry() {
+			sra = m.sra
+		}
+		m.topicsTabCtrl, cmd = topics_tab.New(m.ktx, m.ka, sra)
 		cmds = append(cmds, cmd)
 		m.clustersTabCtrl, cmd = clusters_tab.New(m.ktx, kadmin.CheckKafkaConnectivity, sradmin.CheckSchemaRegistryConn)
 		cmds = append(cmds, cmd)
@@ -5,6 +5,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
 	"os"
+	"path"
+	"slices"
 )
 
 type AuthMethod int
@@ -36,28 +38,116 @@ type KafkaConnectConfig struct {
 	Password *string `yaml:"password"`
 }
 
+// LocalSchema maps the records of a topic to a local schema file, to decode
+// schemaless records or records of clusters without a Schema Registry.
+type LocalSchema struct {
+	// Topic is the name of the topic or a glob pattern like orders-*.
+	Topic string `yaml:"topic"`
+	// File is an Avro schema (.avsc), a Protobuf schema (.proto)
+	// or a Protobuf descriptor set (.desc, .pb or .protoset).
+	File string `yaml:"file"`
+	// Message is the fully qualified name of the Protobuf message,
+	// defaults to the first message of a .proto file.
+	Message string `yaml:"message,omitempty"`
+	// ImportPaths are the directories the imports of a .proto file are resolved from.
+	ImportPaths []string `yaml:"import-paths,omitempty"`
+	// Key applies the schema to the record keys instead of the values.
+	Key bool `yaml:"key,omitempty"`
+}
+
+// Matches reports whether the schema applies to the keys, or values, of the topic.
+func (s *LocalSchema) Matches(topic string, key bool) bool {
+	if s.Key != key {
+		return false
+	}
+	matched, err := path.Match(s.Topic, topic)
+	return err == nil && matched
+}
+
+// TopicFormat holds the formats the keys and values of a topic are deserialized with,
+// an empty format means the format is detected automatically.
+type TopicFormat struct {
+	Key   string `yaml:"key,omitempty"`
+	Value string `yaml:"value,omitempty"`
+}
+
+// ConsumptionQuery is a named consumption of a topic that can be run again.
+type ConsumptionQuery struct {
+	Name  string `yaml:"name"`
+	Topic string `yaml:"topic"`
+	// Partitions are the partitions to read, all partitions are read when empty.
+	Partitions      []int  `yaml:"partitions,omitempty"`
+	StartPoint      string `yaml:"start-point,omitempty"`
+	Limit           int    `yaml:"limit,omitempty"`
+	KeyFilter       string `yaml:"key-filter,omitempty"`
+	KeyFilterTerm   string `yaml:"key-filter-term,omitempty"`
+	ValueFilter     string `yaml:"value-filter,omitempty"`
+	ValueFilterTerm string `yaml:"value-filter-term,omitempty"`
+	KeyFormat       string `yaml:"key-format,omitempty"`
+	ValueFormat     string `yaml:"value-format,omitempty"`
+	ConsumerGroup   string `yaml:"consumer-group,omitempty"`
+	CommitOffsets   bool   `yaml:"commit-offsets,omitempty"`
+	// ReadCommitted skips the records of open and aborted transactions.
+	ReadCommitted    bool `yaml:"read-committed,omitempty"`
+	ShowTransactions bool `yaml:"show-transactions,omitempty"`
+}
+
 type Cluster struct {
-	Name                 string                `yaml:"name"`
-	Color                string                `yaml:"color"`
-	Active               bool                  `yaml:"active"`
-	BootstrapServers     []string              `yaml:"servers"`
-	SASLConfig           *SASLConfig           `yaml:"sasl"`
-	SchemaRegistry       *SchemaRegistryConfig `yaml:"schema-registry"`
-	SSLEnabled           bool                  `yaml:"ssl-enabled"`
-	KafkaConnectClusters []KafkaConnectConfig  `yaml:"kafka-connect-clusters"`
+	Name                 string                 `yaml:"name"`
+	Color                string                 `yaml:"color"`
+	Active               bool                   `yaml:"active"`
+	BootstrapServers     []string               `yaml:"servers"`
+	SASLConfig           *SASLConfig            `yaml:"sasl"`
+	SchemaRegistry       *SchemaRegistryConfig  `yaml:"schema-registry"`
+	SSLEnabled           bool                   `yaml:"ssl-enabled"`
+	KafkaConnectClusters []KafkaConnectConfig   `yaml:"kafka-connect-clusters"`
+	LocalSchemas         []LocalSchema          `yaml:"local-schemas,omitempty"`
+	TopicFormats         map[string]TopicFormat `yaml:"topic-formats,omitempty"`
+	HeaderDecoders       map[string]string      `yaml:"header-decoders,omitempty"`
+	TopicColumns         map[string][]string    `yaml:"topic-columns,omitempty"`
+	SavedQueries         []ConsumptionQuery     `yaml:"saved-queries,omitempty"`
 }
 
 func (c *Cluster) HasSchemaRegistry() bool {
 	return c.SchemaRegistry != nil
 }
 
+// TopicFormat returns the formats chosen for the topic.
+func (c *Cluster) TopicFormat(topic string) TopicFormat {
+	return c.TopicFormats[topic]
+}
+
+// Columns returns the columns shown when consuming the topic, nil means the default columns.
+func (c *Cluster) Columns(topic string) []string {
+	return c.TopicColumns[topic]
+}
+
+// Queries returns the saved consumption queries of the topic.
+func (c *Cluster) Queries(topic string) []ConsumptionQuery {
+	var queries []ConsumptionQuery
+	for _, query := range c.SavedQueries {
+		if query.Topic == topic {
+			queries = append(queries, query)
+		}
+	}
+	return queries
+}
+
+// HeaderDecoder returns the decoder chosen for the header key.
+func (c *Cluster) HeaderDecoder(key string) string {
+	return c.HeaderDecoders[key]
+}
+
 func (c *Cluster) HasKafkaConnect() bool {
 	return len(c.KafkaConnectClusters) > 0
 }
 
 type Config struct {
 	Clusters []Cluster `yaml:"clusters"`
-	ConfigIO IO        `yaml:"-"`
+	// ConsumptionBufferSize is the maximum number of records kept while consuming,
+	// the oldest records are dropped once it is reached.
+	ConsumptionBufferSize int `yaml:"consumption-buffer-size,omitempty"`
+	ConfigIO              IO  `yaml:"-"`
 }
 
 func (c *Config) HasClusters() bool {
@@ -157,6 +247,12 @@ func (c *Config) RegisterCluster(details RegistrationDetails) tea.Msg {
 		if c.Clusters[i].Name == details.Name {
 			isActive := c.Clusters[i].Active
 			cluster.Active = isActive
+			// settings that are only configurable through the config file
+			cluster.LocalSchemas = c.Clusters[i].LocalSchemas
+			cluster.TopicFormats = c.Clusters[i].TopicFormats
+			cluster.HeaderDecoders = c.Clusters[i].HeaderDecoders
+			cluster.TopicColumns = c.Clusters[i].TopicColumns
+			cluster.SavedQueries = c.Clusters[i].SavedQueries
 			c.Clusters[i] = cluster
 			if details.NewName != nil {
 				c.Clusters[i].Name = *details.NewName
@@ -234,6 +330,124 @@ func (c *Config) flush() {
 	log.Debug("flushed config")
 }
 
+// SaveTopicFormat remembers the formats chosen for the topic of the cluster.
+func (c *Config) SaveTopicFormat(clusterName string, topic string, format TopicFormat) {
+	for i := range c.Clusters {
+		if c.Clusters[i].Name != clusterName {
+			continue
+		}
+
+		if c.Clusters[i].TopicFormat(topic) == format {
+			return
+		}
+
+		if format == (TopicFormat{}) {
+			delete(c.Clusters[i].TopicFormats, topic)
+		} else {
+			if c.Clusters[i].TopicFormats == nil {
+				c.Clusters[i].TopicFormats = make(map[string]TopicFormat)
+			}
+			c.Clusters[i].TopicFormats[topic] = format
+		}
+
+		c.flush()
+		return
+	}
+}
+
+// SaveTopicColumns remembers the columns shown when consuming the topic of the cluster,
+// no columns restore the default columns.
+func (c *Config) SaveTopicColumns(clusterName string, topic string, columns []string) {
+	for i := range c.Clusters {
+		if c.Clusters[i].Name != clusterName {
+			continue
+		}
+
+		if slices.Equal(c.Clusters[i].Columns(topic), columns) {
+			return
+		}
+
+		if len(columns) == 0 {
+			delete(c.Clusters[i].TopicColumns, topic)
+		} else {
+			if c.Clusters[i].TopicColumns == nil {
+				c.Clusters[i].TopicColumns = make(map[string][]string)
+			}
+			c.Clusters[i].TopicColumns[topic] = columns
+		}
+
+		c.flush()
+		return
+	}
+}
+
+// SaveQuery saves the consumption query of the cluster,
+// replacing the query of the same topic with the same name.
+func (c *Config) SaveQuery(clusterName string, query ConsumptionQuery) {
+	for i := range c.Clusters {
+		if c.Clusters[i].Name != clusterName {
+			continue
+		}
+
+		idx := slices.IndexFunc(c.Clusters[i].SavedQueries, func(saved ConsumptionQuery) bool {
+			return saved.Topic == query.Topic && saved.Name == query.Name
+		})
+		if idx == -1 {
+			c.Clusters[i].SavedQueries = append(c.Clusters[i].SavedQueries, query)
+		} else {
+			c.Clusters[i].SavedQueries[idx] = query
+		}
+
+		c.flush()
+		return
+	}
+}
+
+// DeleteQuery deletes the consumption query with the name of the topic of the cluster.
+func (c *Config) DeleteQuery(clusterName string, topic string, name string) {
+	for i := range c.Clusters {
+		if c.Clusters[i].Name != clusterName {
+			continue
+		}
+
+		queries := slices.DeleteFunc(slices.Clone(c.Clusters[i].SavedQueries), func(saved ConsumptionQuery) bool {
+			return saved.Topic == topic && saved.Name == name
+		})
+		if len(queries) == len(c.Clusters[i].SavedQueries) {
+			return
+		}
+		c.Clusters[i].SavedQueries = queries
+
+		c.flush()
+		return
+	}
+}
+
+// SaveHeaderDecoder remembers the decoder chosen for the header key of the cluster.
+func (c *Config) SaveHeaderDecoder(clusterName string, key string, decoder string) {
+	for i := range c.Clusters {
+		if c.Clusters[i].Name != clusterName {
+			continue
+		}
+
+		if c.Clusters[i].HeaderDecoder(key) == decoder {
+			return
+		}
+
+		if decoder == "" {
+			delete(c.Clusters[i].HeaderDecoders, key)
+		} else {
+			if c.Clusters[i].HeaderDecoders == nil {
+				c.Clusters[i].HeaderDecoders = make(map[string]string)
+			}
+			c.Clusters[i].HeaderDecoders[key] = decoder
+		}
+
+		c.flush()
+		return
+	}
+}
+
 func (c *Config) SwitchCluster(name string) *Cluster {
 	var activeCluster *Cluster
 
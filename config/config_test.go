@@ -135,6 +135,29 @@ func TestConfig(t *testing.T) {
 		assert.Equal(t, config.Clusters[0].BootstrapServers, []string{"localhost:9093"})
 	})
 
+	t.Run("Registering an existing cluster keeps its local schemas", func(t *testing.T) {
+		// given
+		config := New(&InMemoryConfigIO{})
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880808",
+			Host:       "localhost:9092",
+			AuthMethod: NoneAuthMethod,
+		})
+		config.Clusters[0].LocalSchemas = []LocalSchema{{Topic: "orders", File: "orders.avsc"}}
+
+		// when
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880801",
+			Host:       "localhost:9093",
+			AuthMethod: NoneAuthMethod,
+		})
+
+		// then
+		assert.Equal(t, []LocalSchema{{Topic: "orders", File: "orders.avsc"}}, config.Clusters[0].LocalSchemas)
+	})
+
 	t.Run("Registering an existing active cluster keeps it active", func(t *testing.T) {
 		// given
 		config := New(&InMemoryConfigIO{})
@@ -486,3 +509,254 @@ func TestConfig(t *testing.T) {
 		assert.Nil(t, cluster)
 	})
 }
+
+func TestLocalSchemaMatches(t *testing.T) {
+	t.Run("Exact topic name", func(t *testing.T) {
+		schema := LocalSchema{Topic: "orders", File: "orders.avsc"}
+
+		assert.True(t, schema.Matches("orders", false))
+		assert.False(t, schema.Matches("orders-dlq", false))
+	})
+
+	t.Run("Topic pattern", func(t *testing.T) {
+		schema := LocalSchema{Topic: "orders-*", File: "orders.avsc"}
+
+		assert.True(t, schema.Matches("orders-eu", false))
+		assert.False(t, schema.Matches("payments-eu", false))
+	})
+
+	t.Run("Key schemas only match keys", func(t *testing.T) {
+		schema := LocalSchema{Topic: "orders", File: "order-key.avsc", Key: true}
+
+		assert.True(t, schema.Matches("orders", true))
+		assert.False(t, schema.Matches("orders", false))
+	})
+}
+
+func TestSaveTopicFormat(t *testing.T) {
+	t.Run("Remember formats of a topic", func(t *testing.T) {
+		// given
+		config := New(&InMemoryConfigIO{})
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880808",
+			Host:       "localhost:9092",
+			AuthMethod: NoneAuthMethod,
+		})
+
+		// when
+		config.SaveTopicFormat("prd", "orders", TopicFormat{Key: "int64", Value: "schema-registry"})
+
+		// then
+		assert.Equal(t, TopicFormat{Key: "int64", Value: "schema-registry"}, config.Clusters[0].TopicFormat("orders"))
+		assert.Equal(t, TopicFormat{}, config.Clusters[0].TopicFormat("payments"))
+	})
+
+	t.Run("Forget formats that are reset", func(t *testing.T) {
+		// given
+		config := New(&InMemoryConfigIO{})
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880808",
+			Host:       "localhost:9092",
+			AuthMethod: NoneAuthMethod,
+		})
+		config.SaveTopicFormat("prd", "orders", TopicFormat{Key: "int64"})
+
+		// when
+		config.SaveTopicFormat("prd", "orders", TopicFormat{})
+
+		// then
+		assert.Empty(t, config.Clusters[0].TopicFormats)
+	})
+
+	t.Run("Registering an existing cluster keeps its topic formats", func(t *testing.T) {
+		// given
+		config := New(&InMemoryConfigIO{})
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880808",
+			Host:       "localhost:9092",
+			AuthMethod: NoneAuthMethod,
+		})
+		config.SaveTopicFormat("prd", "orders", TopicFormat{Key: "int64"})
+
+		// when
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880801",
+			Host:       "localhost:9093",
+			AuthMethod: NoneAuthMethod,
+		})
+
+		// then
+		assert.Equal(t, TopicFormat{Key: "int64"}, config.Clusters[0].TopicFormat("orders"))
+	})
+}
+
+func TestSaveHeaderDecoder(t *testing.T) {
+	t.Run("Remember decoder of a header key", func(t *testing.T) {
+		// given
+		config := New(&InMemoryConfigIO{})
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880808",
+			Host:       "localhost:9092",
+			AuthMethod: NoneAuthMethod,
+		})
+
+		// when
+		config.SaveHeaderDecoder("prd", "retry-count", "int32")
+
+		// then
+		assert.Equal(t, "int32", config.Clusters[0].HeaderDecoder("retry-count"))
+		assert.Equal(t, "", config.Clusters[0].HeaderDecoder("traceparent"))
+	})
+
+	t.Run("Forget decoder that is reset", func(t *testing.T) {
+		// given
+		config := New(&InMemoryConfigIO{})
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880808",
+			Host:       "localhost:9092",
+			AuthMethod: NoneAuthMethod,
+		})
+		config.SaveHeaderDecoder("prd", "retry-count", "int32")
+
+		// when
+		config.SaveHeaderDecoder("prd", "retry-count", "")
+
+		// then
+		assert.Empty(t, config.Clusters[0].HeaderDecoders)
+	})
+}
+
+func TestSaveTopicColumns(t *testing.T) {
+	t.Run("Remember columns of a topic", func(t *testing.T) {
+		// given
+		config := New(&InMemoryConfigIO{})
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880808",
+			Host:       "localhost:9092",
+			AuthMethod: NoneAuthMethod,
+		})
+
+		// when
+		config.SaveTopicColumns("prd", "orders", []string{"key", "$.order.status"})
+
+		// then
+		assert.Equal(t, []string{"key", "$.order.status"}, config.Clusters[0].Columns("orders"))
+		assert.Nil(t, config.Clusters[0].Columns("payments"))
+	})
+
+	t.Run("Forget columns that are reset", func(t *testing.T) {
+		// given
+		config := New(&InMemoryConfigIO{})
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880808",
+			Host:       "localhost:9092",
+			AuthMethod: NoneAuthMethod,
+		})
+		config.SaveTopicColumns("prd", "orders", []string{"key", "header:trace-id"})
+
+		// when
+		config.SaveTopicColumns("prd", "orders", nil)
+
+		// then
+		assert.NotContains(t, config.Clusters[0].TopicColumns, "orders")
+	})
+
+	t.Run("Registering an existing cluster keeps its columns", func(t *testing.T) {
+		// given
+		config := New(&InMemoryConfigIO{})
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880808",
+			Host:       "localhost:9092",
+			AuthMethod: NoneAuthMethod,
+		})
+		config.SaveTopicColumns("prd", "orders", []string{"offset", "key"})
+
+		// when
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880801",
+			Host:       "localhost:9093",
+			AuthMethod: NoneAuthMethod,
+		})
+
+		// then
+		assert.Equal(t, []string{"offset", "key"}, config.Clusters[0].Columns("orders"))
+	})
+}
+
+func TestSavedQueries(t *testing.T) {
+	newConfig := func() *Config {
+		config := New(&InMemoryConfigIO{})
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880808",
+			Host:       "localhost:9092",
+			AuthMethod: NoneAuthMethod,
+		})
+		return config
+	}
+
+	t.Run("Save queries per topic", func(t *testing.T) {
+		// given
+		config := newConfig()
+
+		// when
+		config.SaveQuery("prd", ConsumptionQuery{Name: "failed", Topic: "orders", Limit: 50})
+		config.SaveQuery("prd", ConsumptionQuery{Name: "failed", Topic: "payments", Limit: 500})
+
+		// then
+		assert.Equal(t, []ConsumptionQuery{{Name: "failed", Topic: "orders", Limit: 50}}, config.Clusters[0].Queries("orders"))
+	})
+
+	t.Run("Replace a query with the same name", func(t *testing.T) {
+		// given
+		config := newConfig()
+		config.SaveQuery("prd", ConsumptionQuery{Name: "failed", Topic: "orders", Limit: 50})
+
+		// when
+		config.SaveQuery("prd", ConsumptionQuery{Name: "failed", Topic: "orders", Limit: 5000})
+
+		// then
+		assert.Equal(t, []ConsumptionQuery{{Name: "failed", Topic: "orders", Limit: 5000}}, config.Clusters[0].Queries("orders"))
+	})
+
+	t.Run("Delete a query", func(t *testing.T) {
+		// given
+		config := newConfig()
+		config.SaveQuery("prd", ConsumptionQuery{Name: "failed", Topic: "orders"})
+		config.SaveQuery("prd", ConsumptionQuery{Name: "failed", Topic: "payments"})
+
+		// when
+		config.DeleteQuery("prd", "orders", "failed")
+
+		// then
+		assert.Empty(t, config.Clusters[0].Queries("orders"))
+		assert.Len(t, config.Clusters[0].Queries("payments"), 1)
+	})
+
+	t.Run("Registering an existing cluster keeps its queries", func(t *testing.T) {
+		// given
+		config := newConfig()
+		config.SaveQuery("prd", ConsumptionQuery{Name: "failed", Topic: "orders"})
+
+		// when
+		config.RegisterCluster(RegistrationDetails{
+			Name:       "prd",
+			Color:      "#880801",
+			Host:       "localhost:9093",
+			AuthMethod: NoneAuthMethod,
+		})
+
+		// then
+		assert.Len(t, config.Clusters[0].Queries("orders"), 1)
+	})
+}
@@ -0,0 +1,89 @@
+package kadmin
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"runtime"
+	"sync"
+)
+
+// deserializationPool deserializes consumed messages on a bounded number of workers.
+//
+// Messages are submitted per partition, the pending results of a partition are
+// awaited in the order they were submitted so the order within a partition is preserved
+// while records of the same partition are still deserialized in parallel.
+type deserializationPool struct {
+	jobs        chan deserializationJob
+	deserialize func(msg *sarama.ConsumerMessage) ConsumerRecord
+	wg          sync.WaitGroup
+}
+
+type deserializationJob struct {
+	msg         *sarama.ConsumerMessage
+	transaction TransactionState
+	result      chan ConsumerRecord
+}
+
+// pendingRecord yields the record once its message has been deserialized.
+type pendingRecord <-chan ConsumerRecord
+
+// submit schedules the deserialization of the message,
+// false is returned when the context is done before a worker picked it up.
+func (p *deserializationPool) submit(ctx context.Context, msg *sarama.ConsumerMessage) (pendingRecord, bool) {
+	return p.submitInTransaction(ctx, msg, NoTransaction)
+}
+
+// submitInTransaction schedules the deserialization of a message that was produced in a transaction.
+func (p *deserializationPool) submitInTransaction(
+	ctx context.Context,
+	msg *sarama.ConsumerMessage,
+	transaction TransactionState,
+) (pendingRecord, bool) {
+	job := deserializationJob{msg: msg, transaction: transaction, result: make(chan ConsumerRecord, 1)}
+	select {
+	case p.jobs <- job:
+		return job.result, true
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// close stops the workers once all submitted messages have been deserialized.
+func (p *deserializationPool) close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
+
+func (p *deserializationPool) work() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		record := p.deserialize(job.msg)
+		record.Transaction = job.transaction
+		job.result <- record
+	}
+}
+
+// size is the number of workers, and the number of records a partition can have pending.
+func (p *deserializationPool) size() int {
+	return cap(p.jobs)
+}
+
+func newDeserializationPool(
+	workers int,
+	deserialize func(msg *sarama.ConsumerMessage) ConsumerRecord,
+) *deserializationPool {
+	pool := &deserializationPool{
+		jobs:        make(chan deserializationJob, workers),
+		deserialize: deserialize,
+	}
+	pool.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go pool.work()
+	}
+	return pool
+}
+
+// deserializationWorkers is the number of workers deserializing the records of a single read.
+func deserializationWorkers() int {
+	return runtime.GOMAXPROCS(0)
+}
@@ -0,0 +1,185 @@
+package kadmin
+
+import (
+	"context"
+	"fmt"
+	"github.com/IBM/sarama"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"ktea/serdes"
+	"ktea/sradmin"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestDeserializationPool(t *testing.T) {
+	t.Run("Preserve the order of submitted messages", func(t *testing.T) {
+		pool := newDeserializationPool(4, func(msg *sarama.ConsumerMessage) ConsumerRecord {
+			// finish out of order
+			time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+			return ConsumerRecord{Offset: msg.Offset}
+		})
+		defer pool.close()
+
+		pending := make(chan pendingRecord, pool.size())
+		go func() {
+			defer close(pending)
+			for offset := int64(0); offset < 200; offset++ {
+				result, _ := pool.submit(context.Background(), &sarama.ConsumerMessage{Offset: offset})
+				pending <- result
+			}
+		}()
+
+		var offsets []int64
+		for result := range pending {
+			offsets = append(offsets, (<-result).Offset)
+		}
+
+		assert.Len(t, offsets, 200)
+		for i, offset := range offsets {
+			assert.Equal(t, int64(i), offset)
+		}
+	})
+
+	t.Run("Stop submitting when the context is done", func(t *testing.T) {
+		block := make(chan struct{})
+		pool := newDeserializationPool(1, func(msg *sarama.ConsumerMessage) ConsumerRecord {
+			<-block
+			return ConsumerRecord{}
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// occupy the worker and fill the queue
+		pool.submit(ctx, &sarama.ConsumerMessage{})
+		pool.submit(ctx, &sarama.ConsumerMessage{})
+		cancel()
+		_, ok := pool.submit(ctx, &sarama.ConsumerMessage{})
+
+		assert.False(t, ok)
+		close(block)
+		pool.close()
+	})
+}
+
+const benchmarkSchema = `{
+  "type": "record",
+  "name": "Order",
+  "fields": [
+    {"name": "id", "type": "string"},
+    {"name": "customer", "type": "string"},
+    {"name": "amount", "type": "double"},
+    {"name": "lines", "type": {"type": "array", "items": {
+      "type": "record",
+      "name": "Line",
+      "fields": [
+        {"name": "sku", "type": "string"},
+        {"name": "quantity", "type": "int"}
+      ]
+    }}}
+  ]
+}`
+
+// BenchmarkDeserializationPool compares deserializing Confluent framed Avro records one by one with a new
+// deserializer per record, as records were read before, with deserializing them in the pool of the record
+// reader, which shares the deserializers and keeps the records of each partition in order.
+func BenchmarkDeserializationPool(b *testing.B) {
+	const (
+		records    = 1000
+		partitions = 4
+	)
+	codec, err := goavro.NewCodec(benchmarkSchema)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data, err := codec.BinaryFromNative([]byte{0, 0, 0, 0, 1}, map[string]interface{}{
+		"id":       "order-1",
+		"customer": "John",
+		"amount":   12.5,
+		"lines": []interface{}{
+			map[string]interface{}{"sku": "tea", "quantity": 2},
+			map[string]interface{}{"sku": "cup", "quantity": 1},
+		},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	sraMock := sradmin.NewMock()
+	sraMock.GetSchemaByIdFunc = func(id int) tea.Msg {
+		return sradmin.SchemaByIdReceived{Schema: sradmin.Schema{Value: benchmarkSchema}}
+	}
+	msgs := make([]*sarama.ConsumerMessage, records)
+	for i := range msgs {
+		msgs[i] = &sarama.ConsumerMessage{
+			Topic:     "orders",
+			Partition: int32(i % partitions),
+			Offset:    int64(i / partitions),
+			Value:     data,
+		}
+	}
+	rd := ReadDetails{KeyFormat: serdes.AutoFormat, ValueFormat: serdes.AutoFormat}
+	reportRecordsPerSecond := func(b *testing.B) {
+		b.ReportMetric(float64(records*b.N)/b.Elapsed().Seconds(), "records/s")
+	}
+
+	b.Run("serial with a new deserializer per record", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, msg := range msgs {
+				// a new admin has no deserializers cached yet
+				record := (&SaramaKafkaAdmin{sra: sraMock}).toConsumerRecord(rd, msg)
+				if record.Err != nil {
+					b.Fatal(record.Err)
+				}
+			}
+		}
+		reportRecordsPerSecond(b)
+	})
+
+	b.Run("pool with shared deserializers", func(b *testing.B) {
+		ka := &SaramaKafkaAdmin{sra: sraMock}
+		pool := newDeserializationPool(deserializationWorkers(), func(msg *sarama.ConsumerMessage) ConsumerRecord {
+			return ka.toConsumerRecord(rd, msg)
+		})
+		defer pool.close()
+
+		for i := 0; i < b.N; i++ {
+			// like the record reader, a partition is read by its own goroutine and receives its records in order
+			done := make(chan error, partitions)
+			for partition := 0; partition < partitions; partition++ {
+				go func(partition int32) {
+					pending := make(chan pendingRecord, pool.size())
+					go func() {
+						defer close(pending)
+						for _, msg := range msgs {
+							if msg.Partition != partition {
+								continue
+							}
+							result, _ := pool.submit(context.Background(), msg)
+							pending <- result
+						}
+					}()
+					offset := int64(0)
+					var err error
+					for result := range pending {
+						record := <-result
+						if record.Err != nil && err == nil {
+							err = record.Err
+						}
+						if record.Offset != offset && err == nil {
+							err = fmt.Errorf("partition %d: expected offset %d, got %d", partition, offset, record.Offset)
+						}
+						offset++
+					}
+					done <- err
+				}(int32(partition))
+			}
+			for partition := 0; partition < partitions; partition++ {
+				if err := <-done; err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		reportRecordsPerSecond(b)
+	})
+}
@@ -0,0 +1,385 @@
+package kadmin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/IBM/sarama"
+	"github.com/charmbracelet/log"
+	"sync"
+	"time"
+)
+
+// commitInterval is how often the marked offsets are committed while reading records.
+const commitInterval = time.Second
+
+// groupProtocol is the protocol the consumer group is joined with. Consumers of other applications
+// use other protocols, a group they are active in can not be joined.
+const groupProtocol = "ktea"
+
+// groupOffsets commits the offsets of the records read to a consumer group. The group is joined
+// so the offsets are committed as a member of the group, with its generation, which is only possible
+// while the group has no active members of other applications.
+type groupOffsets struct {
+	client sarama.Client
+	config *sarama.Config
+	group  string
+	topic  string
+	mu     sync.Mutex
+	// marked holds the next offset to commit by partition.
+	marked map[int]int64
+	// committed holds the next offset that was committed by partition.
+	committed map[int]int64
+	// generationID and memberID identify the membership of the group the offsets are committed with.
+	generationID int32
+	memberID     string
+}
+
+// OffsetCommitError reports that the offsets of the read records could not be committed to the group.
+type OffsetCommitError struct {
+	Group string
+	Err   error
+}
+
+func (e OffsetCommitError) Error() string {
+	if errors.Is(e.Err, sarama.ErrInconsistentGroupProtocol) ||
+		errors.Is(e.Err, sarama.ErrUnknownMemberId) ||
+		errors.Is(e.Err, sarama.ErrIllegalGeneration) {
+		return fmt.Sprintf("unable to commit the offsets of %s, the group has active members: %v", e.Group, e.Err)
+	}
+	return fmt.Sprintf("unable to commit the offsets of %s: %v", e.Group, e.Err)
+}
+
+func (e OffsetCommitError) Unwrap() error {
+	return e.Err
+}
+
+// newGroupOffsets returns the groupOffsets of the consumer group of the ReadDetails,
+// or nil when its offsets are not committed.
+func (ka *SaramaKafkaAdmin) newGroupOffsets(rd ReadDetails) *groupOffsets {
+	if rd.ConsumerGroup == "" || !rd.CommitOffsets {
+		return nil
+	}
+	return &groupOffsets{
+		client:    ka.client,
+		config:    ka.config,
+		group:     rd.ConsumerGroup,
+		topic:     rd.TopicName,
+		marked:    make(map[int]int64),
+		committed: make(map[int]int64),
+	}
+}
+
+// fetchGroupOffsets fetches the offsets committed by the consumer group of the ReadDetails
+// into offsetsByPartition. It does nothing when no consumer group is given.
+func (ka *SaramaKafkaAdmin) fetchGroupOffsets(
+	rd ReadDetails,
+	offsetsByPartition map[int]offsets,
+) error {
+	if rd.ConsumerGroup == "" {
+		return nil
+	}
+
+	partitions := make([]int32, len(rd.PartitionToRead))
+	for i, partition := range rd.PartitionToRead {
+		partitions[i] = int32(partition)
+	}
+	response, err := ka.admin.ListConsumerGroupOffsets(rd.ConsumerGroup, map[string][]int32{rd.TopicName: partitions})
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range rd.PartitionToRead {
+		partitionOffsets := offsetsByPartition[partition]
+		// without a committed offset the group has no block or an offset of -1
+		partitionOffsets.committed = -1
+		if block := response.GetBlock(rd.TopicName, int32(partition)); block != nil {
+			if !errors.Is(block.Err, sarama.ErrNoError) {
+				return block.Err
+			}
+			partitionOffsets.committed = block.Offset
+		}
+		offsetsByPartition[partition] = partitionOffsets
+	}
+	return nil
+}
+
+// markRead marks the record as read, so the group continues after it once committed. Records are
+// marked once they are displayed, not when they are read, and marking an earlier record does nothing.
+func (g *groupOffsets) markRead(record ConsumerRecord) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	partition := int(record.Partition)
+	if next, ok := g.marked[partition]; !ok || record.Offset+1 > next {
+		g.marked[partition] = record.Offset + 1
+	}
+}
+
+// join joins the group, or joins it again after a rebalance, as a member that is not assigned any partitions.
+func (g *groupOffsets) join() error {
+	var err error
+	for retries := 0; retries <= g.config.Consumer.Group.Rebalance.Retry.Max; retries++ {
+		if err = g.joinOnce(); err == nil {
+			return nil
+		}
+		switch {
+		case errors.Is(err, sarama.ErrNotCoordinatorForConsumer), errors.Is(err, sarama.ErrConsumerCoordinatorNotAvailable):
+			_ = g.client.RefreshCoordinator(g.group)
+		case errors.Is(err, sarama.ErrUnknownMemberId):
+			g.mu.Lock()
+			g.memberID = ""
+			g.mu.Unlock()
+		case errors.Is(err, sarama.ErrRebalanceInProgress):
+		default:
+			return err
+		}
+		time.Sleep(g.config.Consumer.Group.Rebalance.Retry.Backoff)
+	}
+	return err
+}
+
+func (g *groupOffsets) joinOnce() error {
+	coordinator, err := g.client.Coordinator(g.group)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	memberID := g.memberID
+	g.mu.Unlock()
+	// version 2 is the oldest version brokers still support
+	join := &sarama.JoinGroupRequest{
+		Version:          2,
+		GroupId:          g.group,
+		SessionTimeout:   int32(g.config.Consumer.Group.Session.Timeout / time.Millisecond),
+		RebalanceTimeout: int32(g.config.Consumer.Group.Rebalance.Timeout / time.Millisecond),
+		MemberId:         memberID,
+		ProtocolType:     "consumer",
+	}
+	metadata := &sarama.ConsumerGroupMemberMetadata{Topics: []string{g.topic}}
+	if err := join.AddGroupProtocolMetadata(groupProtocol, metadata); err != nil {
+		return err
+	}
+	joined, err := coordinator.JoinGroup(join)
+	if err != nil {
+		return err
+	}
+	if !errors.Is(joined.Err, sarama.ErrNoError) {
+		return joined.Err
+	}
+
+	syncRequest := &sarama.SyncGroupRequest{
+		Version:      1,
+		GroupId:      g.group,
+		GenerationId: joined.GenerationId,
+		MemberId:     joined.MemberId,
+	}
+	// the leader assigns the partitions, the members only commit offsets so none are assigned
+	if joined.LeaderId == joined.MemberId {
+		members, err := joined.GetMembers()
+		if err != nil {
+			return err
+		}
+		for member := range members {
+			if err := syncRequest.AddGroupAssignmentMember(member, &sarama.ConsumerGroupMemberAssignment{}); err != nil {
+				return err
+			}
+		}
+	}
+	synced, err := coordinator.SyncGroup(syncRequest)
+	if err != nil {
+		return err
+	}
+	if !errors.Is(synced.Err, sarama.ErrNoError) {
+		return synced.Err
+	}
+
+	g.mu.Lock()
+	g.generationID = joined.GenerationId
+	g.memberID = joined.MemberId
+	g.mu.Unlock()
+	return nil
+}
+
+// heartbeat keeps the membership of the group alive, the group is joined again after a rebalance.
+func (g *groupOffsets) heartbeat() error {
+	coordinator, err := g.client.Coordinator(g.group)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	request := &sarama.HeartbeatRequest{
+		Version:      1,
+		GroupId:      g.group,
+		GenerationId: g.generationID,
+		MemberId:     g.memberID,
+	}
+	g.mu.Unlock()
+	response, err := coordinator.Heartbeat(request)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case errors.Is(response.Err, sarama.ErrNoError):
+		return nil
+	case errors.Is(response.Err, sarama.ErrUnknownMemberId):
+		// the membership expired
+		g.mu.Lock()
+		g.memberID = ""
+		g.mu.Unlock()
+		return g.join()
+	case errors.Is(response.Err, sarama.ErrRebalanceInProgress), errors.Is(response.Err, sarama.ErrIllegalGeneration):
+		return g.join()
+	}
+	return response.Err
+}
+
+// leave leaves the group, so it does not wait for the member to expire before it rebalances.
+func (g *groupOffsets) leave() error {
+	coordinator, err := g.client.Coordinator(g.group)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	request := &sarama.LeaveGroupRequest{
+		Version:  1,
+		GroupId:  g.group,
+		MemberId: g.memberID,
+	}
+	g.mu.Unlock()
+	response, err := coordinator.LeaveGroup(request)
+	if err != nil {
+		return err
+	}
+	if !errors.Is(response.Err, sarama.ErrNoError) {
+		return response.Err
+	}
+	return nil
+}
+
+// commit commits the offsets marked since the last commit. Offsets that failed to commit
+// are committed again with the next commit.
+func (g *groupOffsets) commit() error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	marked := make(map[int]int64)
+	for partition, offset := range g.marked {
+		if committed, ok := g.committed[partition]; !ok || committed != offset {
+			marked[partition] = offset
+		}
+	}
+	generationID, memberID := g.generationID, g.memberID
+	g.mu.Unlock()
+	if len(marked) == 0 {
+		return nil
+	}
+
+	if err := g.commitOffsets(marked, generationID, memberID); err != nil {
+		return OffsetCommitError{Group: g.group, Err: err}
+	}
+	g.mu.Lock()
+	for partition, offset := range marked {
+		g.committed[partition] = offset
+	}
+	g.mu.Unlock()
+	return nil
+}
+
+func (g *groupOffsets) commitOffsets(marked map[int]int64, generationID int32, memberID string) error {
+	coordinator, err := g.client.Coordinator(g.group)
+	if err != nil {
+		return err
+	}
+
+	// version 2 is the oldest version brokers still support, the retention is left to the broker
+	request := &sarama.OffsetCommitRequest{
+		Version:                 2,
+		ConsumerGroup:           g.group,
+		ConsumerGroupGeneration: generationID,
+		ConsumerID:              memberID,
+		RetentionTime:           -1,
+	}
+	for partition, offset := range marked {
+		request.AddBlock(g.topic, int32(partition), offset, 0, "")
+	}
+
+	response, err := coordinator.CommitOffset(request)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for partition, kerr := range response.Errors[g.topic] {
+		if errors.Is(kerr, sarama.ErrNoError) {
+			continue
+		}
+		if errors.Is(kerr, sarama.ErrNotCoordinatorForConsumer) {
+			_ = g.client.RefreshCoordinator(g.group)
+		}
+		errs = append(errs, fmt.Errorf("partition %d: %w", partition, kerr))
+	}
+	return errors.Join(errs...)
+}
+
+// commitWhileMember stays a member of the group and commits the marked offsets every commitInterval
+// until ctx is done, when the last marked offsets are committed and the group is left.
+// Failures are reported on errChan until reading is closed, after which reported is closed
+// and failures are logged, as nobody awaits them anymore.
+func (g *groupOffsets) commitWhileMember(
+	ctx context.Context,
+	reading <-chan struct{},
+	reported chan<- struct{},
+	errChan chan<- error,
+) {
+	commitTicker := time.NewTicker(commitInterval)
+	defer commitTicker.Stop()
+	heartbeatTicker := time.NewTicker(g.config.Consumer.Group.Heartbeat.Interval)
+	defer heartbeatTicker.Stop()
+
+	report := func(err error) {
+		if errChan != nil {
+			select {
+			case errChan <- err:
+				return
+			case <-reading:
+			case <-ctx.Done():
+			}
+		}
+		log.Error("Unable to commit consumer group offsets", "err", err)
+	}
+
+	for {
+		select {
+		case <-reading:
+			errChan, reading = nil, nil
+			close(reported)
+		case <-heartbeatTicker.C:
+			if err := g.heartbeat(); err != nil {
+				report(OffsetCommitError{Group: g.group, Err: err})
+			}
+		case <-commitTicker.C:
+			if err := g.commit(); err != nil {
+				report(err)
+			}
+		case <-ctx.Done():
+			if err := g.commit(); err != nil {
+				log.Error("Unable to commit consumer group offsets", "err", err)
+			}
+			if err := g.leave(); err != nil {
+				log.Error("Unable to leave consumer group", "group", g.group, "err", err)
+			}
+			if reading != nil {
+				<-reading
+				close(reported)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package kadmin
+
+import (
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGroupOffsets(t *testing.T) {
+	newGroup := func(broker *sarama.MockBroker, joinResponse sarama.MockResponse) *groupOffsets {
+		broker.SetHandlerByMap(map[string]sarama.MockResponse{
+			"MetadataRequest": sarama.NewMockMetadataResponse(t).
+				SetBroker(broker.Addr(), broker.BrokerID()).
+				SetLeader("orders", 0, broker.BrokerID()),
+			"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+				SetCoordinator(sarama.CoordinatorGroup, "order-processor", broker),
+			"JoinGroupRequest":    joinResponse,
+			"SyncGroupRequest":    sarama.NewMockSyncGroupResponse(t),
+			"OffsetCommitRequest": sarama.NewMockOffsetCommitResponse(t),
+		})
+		config := sarama.NewConfig()
+		config.Consumer.Group.Rebalance.Retry.Backoff = time.Millisecond
+		client, err := sarama.NewClient([]string{broker.Addr()}, config)
+		if err != nil {
+			t.Fatal("Unable to create client", err)
+		}
+		t.Cleanup(func() { client.Close() })
+		return (&SaramaKafkaAdmin{client: client, config: config}).newGroupOffsets(ReadDetails{
+			TopicName:     "orders",
+			ConsumerGroup: "order-processor",
+			CommitOffsets: true,
+		})
+	}
+	joined := func() sarama.MockResponse {
+		return sarama.NewMockJoinGroupResponse(t).
+			SetGenerationId(3).
+			SetGroupProtocol(groupProtocol).
+			SetMemberId("ktea-1").
+			SetLeaderId("ktea-1").
+			SetMember("ktea-1", &sarama.ConsumerGroupMemberMetadata{Topics: []string{"orders"}})
+	}
+	commitRequests := func(broker *sarama.MockBroker) []*sarama.OffsetCommitRequest {
+		var requests []*sarama.OffsetCommitRequest
+		for _, rr := range broker.History() {
+			if request, ok := rr.Request.(*sarama.OffsetCommitRequest); ok {
+				requests = append(requests, request)
+			}
+		}
+		return requests
+	}
+
+	t.Run("Commit the marked offsets as a member of the joined group", func(t *testing.T) {
+		broker := sarama.NewMockBroker(t, 1)
+		defer broker.Close()
+		group := newGroup(broker, joined())
+
+		assert.NoError(t, group.join())
+		group.markRead(ConsumerRecord{Partition: 0, Offset: 4})
+		// marking an earlier record does not move the group back
+		group.markRead(ConsumerRecord{Partition: 0, Offset: 2})
+		assert.NoError(t, group.commit())
+
+		requests := commitRequests(broker)
+		assert.Len(t, requests, 1)
+		assert.Equal(t, int32(3), requests[0].ConsumerGroupGeneration)
+		assert.Equal(t, "ktea-1", requests[0].ConsumerID)
+		offset, _, err := requests[0].Offset("orders", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), offset)
+	})
+
+	t.Run("Only commit offsets that were marked since the last commit", func(t *testing.T) {
+		broker := sarama.NewMockBroker(t, 1)
+		defer broker.Close()
+		group := newGroup(broker, joined())
+
+		assert.NoError(t, group.join())
+		group.markRead(ConsumerRecord{Partition: 0, Offset: 4})
+		assert.NoError(t, group.commit())
+		assert.NoError(t, group.commit())
+
+		assert.Len(t, commitRequests(broker), 1)
+	})
+
+	t.Run("Report that the group has active members of other applications", func(t *testing.T) {
+		broker := sarama.NewMockBroker(t, 1)
+		defer broker.Close()
+		group := newGroup(broker, sarama.NewMockJoinGroupResponse(t).SetError(sarama.ErrInconsistentGroupProtocol))
+
+		err := group.join()
+
+		assert.ErrorIs(t, err, sarama.ErrInconsistentGroupProtocol)
+		assert.Contains(t, OffsetCommitError{Group: "order-processor", Err: err}.Error(), "the group has active members")
+	})
+}
@@ -0,0 +1,125 @@
+package kadmin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"math"
+	"strconv"
+)
+
+// HeaderDecoder decodes the value of a header.
+type HeaderDecoder string
+
+const (
+	// AutoHeaderDecoder decodes the value based on its content, see HeaderValue.String.
+	AutoHeaderDecoder   HeaderDecoder = ""
+	StringHeaderDecoder HeaderDecoder = "string"
+	Int32HeaderDecoder  HeaderDecoder = "int32"
+	Int64HeaderDecoder  HeaderDecoder = "int64"
+	// FloatHeaderDecoder decodes 4 byte values as float32 and 8 byte values as float64.
+	FloatHeaderDecoder  HeaderDecoder = "float"
+	UuidHeaderDecoder   HeaderDecoder = "uuid"
+	HexHeaderDecoder    HeaderDecoder = "hex"
+	Base64HeaderDecoder HeaderDecoder = "base64"
+	JsonHeaderDecoder   HeaderDecoder = "json"
+)
+
+// HeaderDecoders lists all decoders in the order they are presented.
+var HeaderDecoders = []HeaderDecoder{
+	AutoHeaderDecoder,
+	StringHeaderDecoder,
+	Int32HeaderDecoder,
+	Int64HeaderDecoder,
+	FloatHeaderDecoder,
+	UuidHeaderDecoder,
+	HexHeaderDecoder,
+	Base64HeaderDecoder,
+	JsonHeaderDecoder,
+}
+
+func (d HeaderDecoder) String() string {
+	if d == AutoHeaderDecoder {
+		return "auto"
+	}
+	return string(d)
+}
+
+// Next returns the decoder following d in HeaderDecoders, wrapping around after the last one.
+func (d HeaderDecoder) Next() HeaderDecoder {
+	for i, decoder := range HeaderDecoders {
+		if decoder == d {
+			return HeaderDecoders[(i+1)%len(HeaderDecoders)]
+		}
+	}
+	return AutoHeaderDecoder
+}
+
+// Decode decodes the value using the given decoder.
+func (v HeaderValue) Decode(decoder HeaderDecoder) (string, error) {
+	switch decoder {
+	case AutoHeaderDecoder:
+		return v.String(), nil
+	case StringHeaderDecoder:
+		return string(v.data), nil
+	case Int32HeaderDecoder:
+		if err := v.expectLength(decoder, 4); err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(int64(int32(binary.BigEndian.Uint32(v.data))), 10), nil
+	case Int64HeaderDecoder:
+		if err := v.expectLength(decoder, 8); err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(int64(binary.BigEndian.Uint64(v.data)), 10), nil
+	case FloatHeaderDecoder:
+		switch len(v.data) {
+		case 4:
+			value := math.Float32frombits(binary.BigEndian.Uint32(v.data))
+			return strconv.FormatFloat(float64(value), 'g', -1, 32), nil
+		case 8:
+			value := math.Float64frombits(binary.BigEndian.Uint64(v.data))
+			return strconv.FormatFloat(value, 'g', -1, 64), nil
+		default:
+			return "", fmt.Errorf("%s expects 4 or 8 bytes but got %d", decoder, len(v.data))
+		}
+	case UuidHeaderDecoder:
+		// UUIDs are either sent as their 16 bytes or as text
+		if len(v.data) == 16 {
+			return uuid.UUID(v.data).String(), nil
+		}
+		id, err := uuid.ParseBytes(v.data)
+		if err != nil {
+			return "", fmt.Errorf("%s expects 16 bytes or a textual UUID: %w", decoder, err)
+		}
+		return id.String(), nil
+	case HexHeaderDecoder:
+		return hex.EncodeToString(v.data), nil
+	case Base64HeaderDecoder:
+		return base64.StdEncoding.EncodeToString(v.data), nil
+	case JsonHeaderDecoder:
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, v.data, "", "  "); err != nil {
+			return "", fmt.Errorf("invalid json: %w", err)
+		}
+		return indented.String(), nil
+	default:
+		return "", fmt.Errorf("unknown header decoder %s", decoder)
+	}
+}
+
+// Raw returns the undecoded bytes of the value.
+func (v HeaderValue) Raw() []byte {
+	return v.data
+}
+
+func (v HeaderValue) expectLength(decoder HeaderDecoder, length int) error {
+	if len(v.data) != length {
+		return fmt.Errorf("%s expects %d bytes but got %d", decoder, length, len(v.data))
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package kadmin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHeaderValue(t *testing.T) {
+	t.Run("String", func(t *testing.T) {
+		tests := []struct {
+			name string
+			data []byte
+			want string
+		}{
+			{"text", []byte("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			{"int32", []byte{0, 0, 0x01, 0x00}, "256"},
+			{"negative int32", []byte{0xff, 0xff, 0xff, 0xff}, "-1"},
+			{"int64", []byte{0, 0, 0, 0, 0, 0, 0, 0x2a}, "42"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, HeaderValue{tt.data}.String())
+			})
+		}
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			decoder HeaderDecoder
+			data    []byte
+			want    string
+		}{
+			{"string", StringHeaderDecoder, []byte("abc"), "abc"},
+			{"int32", Int32HeaderDecoder, []byte{0, 0, 0, 0x2a}, "42"},
+			{"int64", Int64HeaderDecoder, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}, "-2"},
+			{"float32", FloatHeaderDecoder, []byte{0x3f, 0xc0, 0, 0}, "1.5"},
+			{"float64", FloatHeaderDecoder, []byte{0x40, 0x09, 0x21, 0xfb, 0x54, 0x44, 0x2d, 0x18}, "3.141592653589793"},
+			{"binary uuid", UuidHeaderDecoder, []byte{
+				0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3,
+				0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00,
+			}, "123e4567-e89b-12d3-a456-426614174000"},
+			{"textual uuid", UuidHeaderDecoder, []byte("123E4567-E89B-12D3-A456-426614174000"), "123e4567-e89b-12d3-a456-426614174000"},
+			{"hex", HexHeaderDecoder, []byte{0xca, 0xfe}, "cafe"},
+			{"base64", Base64HeaderDecoder, []byte{0xca, 0xfe}, "yv4="},
+			{"json", JsonHeaderDecoder, []byte(`{"a":1}`), "{\n  \"a\": 1\n}"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				value, err := HeaderValue{tt.data}.Decode(tt.decoder)
+
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, value)
+			})
+		}
+
+		t.Run("unexpected number of bytes", func(t *testing.T) {
+			_, err := HeaderValue{[]byte{0, 1}}.Decode(Int32HeaderDecoder)
+
+			assert.EqualError(t, err, "int32 expects 4 bytes but got 2")
+		})
+
+		t.Run("invalid json", func(t *testing.T) {
+			_, err := HeaderValue{[]byte(`{"a":`)}.Decode(JsonHeaderDecoder)
+
+			assert.ErrorContains(t, err, "invalid json")
+		})
+	})
+
+	t.Run("Next decoder wraps around", func(t *testing.T) {
+		assert.Equal(t, StringHeaderDecoder, AutoHeaderDecoder.Next())
+		assert.Equal(t, AutoHeaderDecoder, JsonHeaderDecoder.Next())
+	})
+}
@@ -18,7 +18,11 @@ type Kadmin interface {
 	TopicDeleter
 	TopicLister
 	Publisher
+	RecordImporter
 	RecordReader
+	RecordExporter
+	RecordReplayer
+	LoadGenerator
 	OffsetLister
 	CGroupLister
 	CGroupDeleter
@@ -31,6 +35,7 @@ type ConnectionDetails struct {
 	BootstrapServers []string
 	SASLConfig       *SASLConfig
 	SSLEnabled       bool
+	LocalSchemas     []config.LocalSchema
 }
 
 type SASLProtocol int
@@ -0,0 +1,375 @@
+package kadmin
+
+import (
+	"context"
+	"errors"
+	tea "github.com/charmbracelet/bubbletea"
+	"ktea/serdes"
+	"ktea/sradmin"
+	"math"
+	"sync"
+	"time"
+)
+
+// DefaultLoadConcurrency is the number of records that are in flight at once when generating load.
+const DefaultLoadConcurrency = 100
+
+// loadProgressInterval is how often progress is reported while generating load.
+const loadProgressInterval = 500 * time.Millisecond
+
+type LoadGenerator interface {
+	GenerateLoad(ctx context.Context, ld LoadDetails) tea.Msg
+}
+
+type LoadDetails struct {
+	Topic string
+	// Records is the number of records to publish, zero to publish until the Duration has passed.
+	Records int
+	// Duration to publish for, zero to publish until all Records are published.
+	Duration time.Duration
+	// Rate is the target number of records per second, zero to publish as fast as possible.
+	Rate int
+	// KeyTemplate renders the keys, records have a null key when empty.
+	KeyTemplate string
+	// ValueTemplate renders the values, unless a ValueSchema is set.
+	ValueTemplate string
+	// ValueSchema generates random values matching the schema.
+	ValueSchema *serdes.SchemaSelection
+	// HeaderTemplates render the header values by header key.
+	HeaderTemplates map[string]string
+	Settings        ProducerSettings
+	// Concurrency is the number of records in flight, DefaultLoadConcurrency when zero.
+	Concurrency int
+}
+
+// LoadStats are the results of generating load so far.
+type LoadStats struct {
+	Published int
+	Failed    int
+	Elapsed   time.Duration
+	// P50, P95 and P99 are the latency percentiles of the publications, from sending until acknowledged.
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	Max time.Duration
+	// LastErr is the error of the last failed publication.
+	LastErr error
+}
+
+// Throughput is the number of published records per second.
+func (s LoadStats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Published) / s.Elapsed.Seconds()
+}
+
+type LoadProgressMsg struct {
+	Stats LoadStats
+}
+
+type LoadGeneratedMsg struct {
+	Stats LoadStats
+}
+
+type LoadGenerationErrMsg struct {
+	Err error
+}
+
+type LoadStartedMsg struct {
+	Progress  chan LoadStats
+	Generated chan LoadStats
+	Err       chan error
+}
+
+// AwaitActivity returns
+// a LoadProgressMsg while generating,
+// a LoadGeneratedMsg upon completion or cancellation
+// or a LoadGenerationErrMsg when the records could not be generated.
+func (msg *LoadStartedMsg) AwaitActivity() tea.Msg {
+	select {
+	case stats := <-msg.Progress:
+		return LoadProgressMsg{stats}
+	case stats := <-msg.Generated:
+		return LoadGeneratedMsg{stats}
+	case err := <-msg.Err:
+		return LoadGenerationErrMsg{err}
+	}
+}
+
+func (ka *SaramaKafkaAdmin) GenerateLoad(ctx context.Context, ld LoadDetails) tea.Msg {
+	return generateLoad(ctx, ka, ka.sra, ld)
+}
+
+func generateLoad(ctx context.Context, publisher Publisher, sra sradmin.SrAdmin, ld LoadDetails) tea.Msg {
+	// the outcome is buffered and progress is skipped while it is not awaited,
+	// so the load is generated when the page no longer awaits it
+	startedMsg := LoadStartedMsg{
+		Progress:  make(chan LoadStats),
+		Generated: make(chan LoadStats, 1),
+		Err:       make(chan error, 1),
+	}
+
+	go func() {
+		records, err := newRecordGenerator(sra, ld)
+		if err != nil {
+			startedMsg.Err <- err
+			return
+		}
+		doGenerateLoad(ctx, publisher, records, ld, startedMsg)
+	}()
+
+	return startedMsg
+}
+
+// recordGenerator renders the records to publish.
+type recordGenerator struct {
+	topic    string
+	key      *PayloadTemplate
+	value    *PayloadTemplate
+	schema   serdes.Generator
+	headers  map[string]*PayloadTemplate
+	settings ProducerSettings
+}
+
+func newRecordGenerator(sra sradmin.SrAdmin, ld LoadDetails) (*recordGenerator, error) {
+	if ld.Records <= 0 && ld.Duration <= 0 {
+		return nil, errors.New("a number of records or a duration is required")
+	}
+
+	g := &recordGenerator{
+		topic:    ld.Topic,
+		headers:  make(map[string]*PayloadTemplate, len(ld.HeaderTemplates)),
+		settings: ld.Settings,
+	}
+
+	var err error
+	if ld.KeyTemplate != "" {
+		if g.key, err = ParsePayloadTemplate(ld.KeyTemplate); err != nil {
+			return nil, err
+		}
+	}
+	if ld.ValueSchema != nil {
+		if g.schema, err = serdes.NewGenerator(sra, *ld.ValueSchema); err != nil {
+			return nil, err
+		}
+		// fail before anything is published when the schema can not be generated
+		if _, err = g.schema.Generate(); err != nil {
+			return nil, err
+		}
+	} else if g.value, err = ParsePayloadTemplate(ld.ValueTemplate); err != nil {
+		return nil, err
+	}
+	for key, header := range ld.HeaderTemplates {
+		if g.headers[key], err = ParsePayloadTemplate(header); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func (g *recordGenerator) record(seq int) (*ProducerRecord, error) {
+	record := &ProducerRecord{
+		Topic:    g.topic,
+		NullKey:  g.key == nil,
+		Headers:  make(map[string]string, len(g.headers)),
+		Settings: g.settings,
+	}
+
+	var err error
+	if g.key != nil {
+		if record.Key, err = g.key.Render(seq); err != nil {
+			return nil, err
+		}
+	}
+	if g.schema != nil {
+		record.Value, err = g.schema.Generate()
+	} else {
+		var value string
+		value, err = g.value.Render(seq)
+		record.Value = []byte(value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for key, header := range g.headers {
+		if record.Headers[key], err = header.Render(seq); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+// loadRecorder records the outcome of publications.
+type loadRecorder struct {
+	mu        sync.Mutex
+	start     time.Time
+	published int
+	failed    int
+	latencies latencyHistogram
+	lastErr   error
+}
+
+func (r *loadRecorder) record(latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.failed++
+		r.lastErr = err
+		return
+	}
+	r.published++
+	r.latencies.record(latency)
+}
+
+func (r *loadRecorder) stats() LoadStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return LoadStats{
+		Published: r.published,
+		Failed:    r.failed,
+		Elapsed:   time.Since(r.start),
+		P50:       r.latencies.percentile(50),
+		P95:       r.latencies.percentile(95),
+		P99:       r.latencies.percentile(99),
+		Max:       r.latencies.max,
+		LastErr:   r.lastErr,
+	}
+}
+
+const (
+	// latencyBucketGrowth is the factor by which each latency bucket is wider than the previous one,
+	// percentiles are rounded up by at most this factor.
+	latencyBucketGrowth = 1.05
+	// latencyBuckets covers latencies from a microsecond up to more than an hour.
+	latencyBuckets = 460
+)
+
+// latencyHistogram counts latencies in buckets that grow exponentially,
+// so the percentiles of any number of latencies are known in constant memory.
+type latencyHistogram struct {
+	counts [latencyBuckets]int
+	total  int
+	max    time.Duration
+}
+
+func (h *latencyHistogram) record(latency time.Duration) {
+	bucket := 0
+	if latency > time.Microsecond {
+		bucket = int(math.Ceil(math.Log(float64(latency)/float64(time.Microsecond)) / math.Log(latencyBucketGrowth)))
+	}
+	h.counts[min(bucket, latencyBuckets-1)]++
+	h.total++
+	h.max = max(h.max, latency)
+}
+
+// percentile is the upper bound of the bucket holding the latency of the nearest rank.
+func (h *latencyHistogram) percentile(p float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := max(int(math.Ceil(p/100*float64(h.total))), 1)
+	var count int
+	for bucket, bucketCount := range h.counts {
+		count += bucketCount
+		if count >= rank {
+			upperBound := time.Duration(math.Pow(latencyBucketGrowth, float64(bucket)) * float64(time.Microsecond))
+			return min(upperBound, h.max)
+		}
+	}
+	return h.max
+}
+
+func doGenerateLoad(
+	ctx context.Context,
+	publisher Publisher,
+	records *recordGenerator,
+	ld LoadDetails,
+	startedMsg LoadStartedMsg,
+) {
+	concurrency := ld.Concurrency
+	if concurrency < 1 {
+		concurrency = DefaultLoadConcurrency
+	}
+
+	recorder := &loadRecorder{start: time.Now()}
+	if ld.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, recorder.start.Add(ld.Duration))
+		defer cancel()
+	}
+
+	reported := make(chan struct{})
+	doneReporting := make(chan struct{})
+	go func() {
+		defer close(reported)
+		ticker := time.NewTicker(loadProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				select {
+				case startedMsg.Progress <- recorder.stats():
+				default:
+				}
+			case <-doneReporting:
+				return
+			}
+		}
+	}()
+
+	var (
+		inFlight = make(chan struct{}, concurrency)
+		wg       sync.WaitGroup
+	)
+	for seq := 1; ld.Records <= 0 || seq <= ld.Records; seq++ {
+		if ld.Rate > 0 {
+			// records are scheduled from the start, so a delayed record is caught up with
+			scheduled := recorder.start.Add(time.Duration(seq-1) * time.Second / time.Duration(ld.Rate))
+			if wait := time.Until(scheduled); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+				}
+			}
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		record, err := records.record(seq)
+		if err != nil {
+			// a record that can not be generated fails like a record that can not be published
+			recorder.record(0, err)
+			<-inFlight
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-inFlight }()
+			sent := time.Now()
+			publication := publisher.PublishRecord(record)
+			switch msg := publication.AwaitCompletion().(type) {
+			case PublicationFailed:
+				recorder.record(0, msg.Err)
+			default:
+				recorder.record(time.Since(sent), nil)
+			}
+		}()
+	}
+	wg.Wait()
+
+	close(doneReporting)
+	<-reported
+
+	startedMsg.Generated <- recorder.stats()
+}
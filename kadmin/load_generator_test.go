@@ -0,0 +1,237 @@
+package kadmin
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func awaitLoad(msg LoadStartedMsg) (LoadStats, error) {
+	for {
+		switch activity := msg.AwaitActivity().(type) {
+		case LoadGeneratedMsg:
+			return activity.Stats, nil
+		case LoadGenerationErrMsg:
+			return LoadStats{}, activity.Err
+		}
+	}
+}
+
+func TestPayloadTemplate(t *testing.T) {
+	t.Run("Render placeholders", func(t *testing.T) {
+		tmpl, err := ParsePayloadTemplate(`{"id":"{{uuid}}","seq":{{.Seq}},"n":{{int 5 5}},"color":"{{pick "red"}}","at":{{nowMillis}},"on":"{{now}}"}`)
+		assert.NoError(t, err)
+
+		rendered, err := tmpl.Render(3)
+
+		assert.NoError(t, err)
+		assert.Regexp(t, `^\{"id":"[0-9a-f-]{36}","seq":3,"n":5,"color":"red","at":\d{13},"on":"\d{4}-\d\d-\d\dT.+"}$`, rendered)
+	})
+
+	t.Run("Report invalid templates", func(t *testing.T) {
+		_, err := ParsePayloadTemplate(`{{int 10 1}}`)
+		assert.ErrorContains(t, err, "the upper bound is below the lower bound")
+
+		_, err = ParsePayloadTemplate(`{{unknown}}`)
+		assert.ErrorContains(t, err, "invalid template")
+	})
+}
+
+func TestGenerateLoad(t *testing.T) {
+	t.Run("Publish a number of records", func(t *testing.T) {
+		publisher := &capturingPublisher{failKey: "fail"}
+
+		msg := generateLoad(context.Background(), publisher, nil, LoadDetails{
+			Topic:           "orders",
+			Records:         50,
+			KeyTemplate:     "{{.Seq}}",
+			ValueTemplate:   `{"id":{{.Seq}}}`,
+			HeaderTemplates: map[string]string{"source": "load"},
+			Settings:        ProducerSettings{Compression: CompressionLz4},
+		}).(LoadStartedMsg)
+
+		stats, err := awaitLoad(msg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 50, stats.Published)
+		assert.Equal(t, 0, stats.Failed)
+		assert.LessOrEqual(t, stats.P50, stats.P99)
+		assert.LessOrEqual(t, stats.P99, stats.Max)
+		assert.Len(t, publisher.records, 50)
+
+		keys := map[string]bool{}
+		for _, record := range publisher.records {
+			keys[record.Key] = true
+			assert.Equal(t, `{"id":`+record.Key+`}`, string(record.Value))
+			assert.Equal(t, map[string]string{"source": "load"}, record.Headers)
+			assert.Equal(t, CompressionLz4, record.Settings.Compression)
+		}
+		assert.Len(t, keys, 50)
+	})
+
+	t.Run("Publish null keys without a key template", func(t *testing.T) {
+		publisher := &capturingPublisher{failKey: "fail"}
+
+		msg := generateLoad(context.Background(), publisher, nil, LoadDetails{
+			Topic:         "orders",
+			Records:       1,
+			ValueTemplate: "value",
+		}).(LoadStartedMsg)
+
+		_, err := awaitLoad(msg)
+
+		assert.NoError(t, err)
+		assert.True(t, publisher.records[0].NullKey)
+	})
+
+	t.Run("Count failed publications", func(t *testing.T) {
+		publisher := &capturingPublisher{failKey: "fail"}
+
+		msg := generateLoad(context.Background(), publisher, nil, LoadDetails{
+			Topic:         "orders",
+			Records:       4,
+			KeyTemplate:   `{{if eq .Seq 2}}fail{{else}}{{.Seq}}{{end}}`,
+			ValueTemplate: "value",
+		}).(LoadStartedMsg)
+
+		stats, err := awaitLoad(msg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, stats.Published)
+		assert.Equal(t, 1, stats.Failed)
+		assert.EqualError(t, stats.LastErr, "broker unavailable")
+	})
+
+	t.Run("Count records that can not be generated as failed", func(t *testing.T) {
+		publisher := &capturingPublisher{failKey: "fail"}
+
+		msg := generateLoad(context.Background(), publisher, nil, LoadDetails{
+			Topic:         "orders",
+			Records:       4,
+			KeyTemplate:   `{{if eq .Seq 2}}{{index "ab" 5}}{{else}}{{.Seq}}{{end}}`,
+			ValueTemplate: "value",
+		}).(LoadStartedMsg)
+
+		stats, err := awaitLoad(msg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, stats.Published)
+		assert.Equal(t, 1, stats.Failed)
+		assert.ErrorContains(t, stats.LastErr, "index out of range")
+	})
+
+	t.Run("Publish at the target rate", func(t *testing.T) {
+		publisher := &capturingPublisher{failKey: "fail"}
+
+		msg := generateLoad(context.Background(), publisher, nil, LoadDetails{
+			Topic:         "orders",
+			Records:       11,
+			Rate:          100,
+			ValueTemplate: "value",
+		}).(LoadStartedMsg)
+
+		stats, err := awaitLoad(msg)
+
+		assert.NoError(t, err)
+		// the last of 11 records is scheduled after 100ms
+		assert.GreaterOrEqual(t, stats.Elapsed, 100*time.Millisecond)
+		assert.Equal(t, 11, stats.Published)
+	})
+
+	t.Run("Publish for a duration", func(t *testing.T) {
+		publisher := &capturingPublisher{failKey: "fail"}
+
+		msg := generateLoad(context.Background(), publisher, nil, LoadDetails{
+			Topic:         "orders",
+			Duration:      200 * time.Millisecond,
+			Rate:          50,
+			ValueTemplate: "value",
+		}).(LoadStartedMsg)
+
+		stats, err := awaitLoad(msg)
+
+		assert.NoError(t, err)
+		assert.InDelta(t, 10, stats.Published, 2)
+	})
+
+	t.Run("Stop when cancelled", func(t *testing.T) {
+		publisher := &capturingPublisher{failKey: "fail"}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		msg := generateLoad(ctx, publisher, nil, LoadDetails{
+			Topic:         "orders",
+			Duration:      time.Hour,
+			Rate:          100,
+			ValueTemplate: "value",
+		}).(LoadStartedMsg)
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		stats, err := awaitLoad(msg)
+
+		assert.NoError(t, err)
+		assert.Less(t, stats.Elapsed, time.Second)
+	})
+
+	t.Run("Finish when the activity is no longer awaited", func(t *testing.T) {
+		publisher := &capturingPublisher{failKey: "fail"}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		msg := generateLoad(ctx, publisher, nil, LoadDetails{
+			Topic:         "orders",
+			Duration:      time.Hour,
+			Rate:          100,
+			ValueTemplate: "value",
+		}).(LoadStartedMsg)
+		time.AfterFunc(2*loadProgressInterval, cancel)
+
+		assert.Eventually(t, func() bool {
+			return len(msg.Generated) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Require a number of records or a duration", func(t *testing.T) {
+		msg := generateLoad(context.Background(), &capturingPublisher{}, nil, LoadDetails{
+			Topic:         "orders",
+			ValueTemplate: "value",
+		}).(LoadStartedMsg)
+
+		_, err := awaitLoad(msg)
+
+		assert.EqualError(t, err, "a number of records or a duration is required")
+	})
+
+	t.Run("Report invalid templates before publishing", func(t *testing.T) {
+		publisher := &capturingPublisher{failKey: "fail"}
+
+		msg := generateLoad(context.Background(), publisher, nil, LoadDetails{
+			Topic:         "orders",
+			Records:       1,
+			ValueTemplate: "{{pick}}",
+		}).(LoadStartedMsg)
+
+		_, err := awaitLoad(msg)
+
+		assert.ErrorContains(t, err, "pick requires at least one value")
+		assert.Empty(t, publisher.records)
+	})
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	var histogram latencyHistogram
+	for i := 1; i <= 100; i++ {
+		histogram.record(time.Duration(i) * time.Millisecond)
+	}
+
+	for _, p := range []float64{50, 95, 99} {
+		t.Run(strconv.FormatFloat(p, 'f', 0, 64), func(t *testing.T) {
+			percentile := histogram.percentile(p)
+			assert.GreaterOrEqual(t, percentile, time.Duration(p)*time.Millisecond)
+			assert.LessOrEqual(t, float64(percentile), latencyBucketGrowth*p*float64(time.Millisecond))
+		})
+	}
+	assert.Equal(t, 100*time.Millisecond, histogram.percentile(100))
+	assert.Equal(t, 100*time.Millisecond, histogram.max)
+	assert.Equal(t, time.Duration(0), (&latencyHistogram{}).percentile(50))
+}
@@ -0,0 +1,70 @@
+package kadmin
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"math/rand/v2"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// PayloadTemplate renders the key, value or a header value of generated records. Placeholders are
+// Go template actions:
+//
+//	{{uuid}}          a random UUID
+//	{{now}}           the current time in RFC 3339
+//	{{nowMillis}}     the current time in epoch millis
+//	{{int 1 100}}     a random integer between both bounds, inclusive
+//	{{pick "a" "b"}}  one of the values at random
+//	{{.Seq}}          the number of the record, starting at 1
+type PayloadTemplate struct {
+	tmpl *template.Template
+}
+
+type templateData struct {
+	Seq int
+}
+
+var templateFuncs = template.FuncMap{
+	"uuid": uuid.NewString,
+	"now": func() string {
+		return time.Now().Format(time.RFC3339Nano)
+	},
+	"nowMillis": func() int64 {
+		return time.Now().UnixMilli()
+	},
+	"int": func(low int, high int) (int, error) {
+		if high < low {
+			return 0, fmt.Errorf("int %d %d: the upper bound is below the lower bound", low, high)
+		}
+		return low + rand.IntN(high-low+1), nil
+	},
+	"pick": func(values ...string) (string, error) {
+		if len(values) == 0 {
+			return "", fmt.Errorf("pick requires at least one value")
+		}
+		return values[rand.IntN(len(values))], nil
+	},
+}
+
+func ParsePayloadTemplate(text string) (*PayloadTemplate, error) {
+	tmpl, err := template.New("payload").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	// render once to report errors, like a missing argument, before anything is published
+	if _, err := (&PayloadTemplate{tmpl}).Render(1); err != nil {
+		return nil, err
+	}
+	return &PayloadTemplate{tmpl}, nil
+}
+
+// Render renders the template for the record with the given sequence number.
+func (t *PayloadTemplate) Render(seq int) (string, error) {
+	var rendered strings.Builder
+	if err := t.tmpl.Execute(&rendered, templateData{Seq: seq}); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	return rendered.String(), nil
+}
@@ -34,10 +34,26 @@ func (m MockKadmin) PublishRecord(p *ProducerRecord) PublicationStartedMsg {
 	return PublicationStartedMsg{}
 }
 
+func (m MockKadmin) ImportRecords(id ImportDetails) tea.Msg {
+	return nil
+}
+
 func (m MockKadmin) ReadRecords(ctx context.Context, rd ReadDetails) tea.Msg {
 	return ReadingStartedMsg{}
 }
 
+func (m MockKadmin) ExportRecords(ctx context.Context, ed ExportDetails) tea.Msg {
+	return nil
+}
+
+func (m MockKadmin) ReplayRecords(ctx context.Context, rd ReplayDetails) tea.Msg {
+	return nil
+}
+
+func (m MockKadmin) GenerateLoad(ctx context.Context, ld LoadDetails) tea.Msg {
+	return nil
+}
+
 func (m MockKadmin) ListOffsets(group string) tea.Msg {
 	return nil
 }
@@ -0,0 +1,353 @@
+package kadmin
+
+import (
+	"cmp"
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/IBM/sarama"
+	"slices"
+	"time"
+)
+
+// controlRecordAbort is the type of the control record that aborts a transaction, a commit is 1.
+const controlRecordAbort = 0
+
+// maxFetchRetries bounds how often a fetch is retried after the leader of the partition moved or went away.
+const maxFetchRetries = 5
+
+// fetchPartition reads the records of a partition using fetch requests, when transactions are shown, determining
+// the transaction state of the records, and queues their pending records and the transaction markers in the order
+// they were fetched. Records of aborted transactions, and records past the last stable offset, are only read
+// when reading uncommitted records.
+func (ka *SaramaKafkaAdmin) fetchPartition(
+	ctx context.Context,
+	rd ReadDetails,
+	partition int,
+	readingOffsets readingOffsets,
+	pool *deserializationPool,
+	pending chan<- pendingRecord,
+	errChan chan<- error,
+) {
+	defer close(pending)
+
+	sendErr := func(err error) {
+		select {
+		case errChan <- err:
+		case <-ctx.Done():
+		}
+	}
+
+	queue := func(result pendingRecord) bool {
+		select {
+		case pending <- result:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	offset := readingOffsets.start
+	for ctx.Err() == nil {
+		// only the brokers know which transactions were aborted, they list them when reading committed records
+		block, err := ka.fetch(ctx, rd.TopicName, int32(partition), offset, sarama.ReadCommitted)
+		if err != nil {
+			sendErr(err)
+			return
+		}
+		states := transactionStatesByAbortedList
+		// records after the last stable offset are only returned when reading uncommitted records
+		if rd.IsolationLevel == ReadUncommitted && offset >= block.LastStableOffset && block.LastStableOffset < block.HighWaterMarkOffset {
+			block, err = ka.fetch(ctx, rd.TopicName, int32(partition), offset, sarama.ReadUncommitted)
+			if err != nil {
+				sendErr(err)
+				return
+			}
+			states = transactionStatesByMarkers
+		}
+
+		var batches []*sarama.RecordBatch
+		for _, records := range block.RecordsSet {
+			if records.RecordBatch == nil {
+				sendErr(errors.New("transactions can not be read from topics using the legacy message format"))
+				return
+			}
+			batches = append(batches, records.RecordBatch)
+		}
+
+		for i, state := range states(batches, block) {
+			batch := batches[i]
+			for _, record := range batch.Records {
+				recordOffset := batch.FirstOffset + record.OffsetDelta
+				// a fetch can start in the middle of a batch
+				if recordOffset < offset {
+					continue
+				}
+				if rd.StartPoint != Live && recordOffset > readingOffsets.end {
+					return
+				}
+
+				timestamp := batch.FirstTimestamp.Add(record.TimestampDelta)
+				if batch.LogAppendTime {
+					timestamp = batch.MaxTimestamp
+				}
+
+				switch {
+				case state.IsMarker():
+					marker := make(chan ConsumerRecord, 1)
+					marker <- ConsumerRecord{
+						Partition:   int64(partition),
+						Offset:      recordOffset,
+						Timestamp:   timestamp,
+						Transaction: state,
+					}
+					if !queue(marker) {
+						return
+					}
+				case state == AbortedTransaction && rd.IsolationLevel == ReadCommitted:
+					continue
+				default:
+					result, ok := pool.submitInTransaction(ctx, &sarama.ConsumerMessage{
+						Headers:        record.Headers,
+						Timestamp:      timestamp,
+						BlockTimestamp: batch.MaxTimestamp,
+						Key:            record.Key,
+						Value:          record.Value,
+						Topic:          rd.TopicName,
+						Partition:      int32(partition),
+						Offset:         recordOffset,
+					}, state)
+					if !ok || !queue(result) {
+						return
+					}
+				}
+			}
+
+			offset = max(offset, batch.LastOffset()+1)
+		}
+
+		if rd.StartPoint != Live {
+			// records after the last stable offset are not returned when reading committed records only
+			readable := block.HighWaterMarkOffset
+			if rd.IsolationLevel == ReadCommitted {
+				readable = block.LastStableOffset
+			}
+			if offset > readingOffsets.end || offset >= readable {
+				return
+			}
+		}
+	}
+}
+
+// committedRecordsLeft reports whether records of the partition that are read committed are left, from the offset
+// up to and including the end offset.
+func (ka *SaramaKafkaAdmin) committedRecordsLeft(
+	ctx context.Context,
+	topic string,
+	partition int32,
+	offset int64,
+	end int64,
+) (bool, error) {
+	for offset <= end {
+		block, err := ka.fetch(ctx, topic, partition, offset, sarama.ReadCommitted)
+		if err != nil {
+			return false, err
+		}
+
+		var batches []*sarama.RecordBatch
+		for _, records := range block.RecordsSet {
+			// legacy message formats have no transactions, nor does a batch that was only partially fetched tell
+			if records.RecordBatch == nil || records.RecordBatch.PartialTrailingRecord {
+				return true, nil
+			}
+			batches = append(batches, records.RecordBatch)
+		}
+		if len(batches) == 0 {
+			return false, nil
+		}
+
+		fetched := offset
+		for i, state := range transactionStatesByAbortedList(batches, block) {
+			batch := batches[i]
+			if !state.IsMarker() && state != AbortedTransaction {
+				for _, record := range batch.Records {
+					recordOffset := batch.FirstOffset + record.OffsetDelta
+					if recordOffset >= offset && recordOffset <= end {
+						return true, nil
+					}
+				}
+			}
+			fetched = max(fetched, batch.LastOffset()+1)
+		}
+		if fetched == offset {
+			// nothing was read, leave it to the consumer
+			return true, nil
+		}
+		offset = fetched
+	}
+	return false, nil
+}
+
+// transactionStatesByAbortedList determines the transaction state of the batches of a read committed fetch,
+// whose aborted transactions are listed by the broker.
+func transactionStatesByAbortedList(batches []*sarama.RecordBatch, block *sarama.FetchResponseBlock) []TransactionState {
+	abortedTransactions := slices.Clone(block.AbortedTransactions)
+	slices.SortFunc(abortedTransactions, func(a, b *sarama.AbortedTransaction) int {
+		return cmp.Compare(a.FirstOffset, b.FirstOffset)
+	})
+	// producers whose aborted transaction started, until their abort marker is read
+	abortedProducers := make(map[int64]struct{})
+
+	states := make([]TransactionState, len(batches))
+	for i, batch := range batches {
+		for len(abortedTransactions) > 0 && abortedTransactions[0].FirstOffset <= batch.LastOffset() {
+			abortedProducers[abortedTransactions[0].ProducerID] = struct{}{}
+			abortedTransactions = abortedTransactions[1:]
+		}
+
+		states[i] = batchTransactionState(batch, abortedProducers, block.LastStableOffset)
+		if states[i] == AbortMarker {
+			delete(abortedProducers, batch.ProducerID)
+		}
+	}
+	return states
+}
+
+// transactionStatesByMarkers determines the transaction state of the batches of a read uncommitted fetch
+// by the marker of their producer that follows them. Transactions without a marker yet are open.
+func transactionStatesByMarkers(batches []*sarama.RecordBatch, _ *sarama.FetchResponseBlock) []TransactionState {
+	states := make([]TransactionState, len(batches))
+	// the state of the marker that follows, by producer
+	completed := make(map[int64]TransactionState)
+	for i := len(batches) - 1; i >= 0; i-- {
+		batch := batches[i]
+		switch {
+		case batch.Control:
+			states[i] = batchTransactionState(batch, nil, 0)
+			completed[batch.ProducerID] = CommittedTransaction
+			if states[i] == AbortMarker {
+				completed[batch.ProducerID] = AbortedTransaction
+			}
+		case !batch.IsTransactional:
+			states[i] = NoTransaction
+		default:
+			states[i] = OpenTransaction
+			if state, ok := completed[batch.ProducerID]; ok {
+				states[i] = state
+			}
+		}
+	}
+	return states
+}
+
+// fetch fetches the records of the partition from its leader, starting at the offset. Fetches failing
+// because the leader moved or is unreachable are retried, with the leader looked up again.
+func (ka *SaramaKafkaAdmin) fetch(
+	ctx context.Context,
+	topic string,
+	partition int32,
+	offset int64,
+	isolation sarama.IsolationLevel,
+) (*sarama.FetchResponseBlock, error) {
+	for retries := 0; ; retries++ {
+		block, err := ka.fetchFromLeader(topic, partition, offset, isolation)
+		if err == nil || !isRetriableFetchErr(err) || retries == maxFetchRetries {
+			return block, err
+		}
+
+		select {
+		case <-time.After(ka.config.Consumer.Retry.Backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if err := ka.client.RefreshMetadata(topic); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (ka *SaramaKafkaAdmin) fetchFromLeader(
+	topic string,
+	partition int32,
+	offset int64,
+	isolation sarama.IsolationLevel,
+) (*sarama.FetchResponseBlock, error) {
+	broker, err := ka.client.Leader(topic, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &sarama.FetchRequest{
+		// version 4 added the isolation level and aborted transactions
+		Version:     4,
+		MaxWaitTime: int32(ka.config.Consumer.MaxWaitTime / time.Millisecond),
+		MinBytes:    ka.config.Consumer.Fetch.Min,
+		MaxBytes:    sarama.MaxResponseSize,
+		Isolation:   isolation,
+	}
+	request.AddBlock(topic, partition, offset, ka.config.Consumer.Fetch.Default, -1)
+
+	response, err := broker.Fetch(request)
+	if err != nil {
+		// the connection is opened again when the leader is looked up
+		_ = broker.Close()
+		return nil, brokerErr{err}
+	}
+
+	block := response.GetBlock(topic, partition)
+	if block == nil {
+		return nil, sarama.ErrIncompleteResponse
+	}
+	if !errors.Is(block.Err, sarama.ErrNoError) {
+		return nil, block.Err
+	}
+	return block, nil
+}
+
+// brokerErr is a failure to reach the broker, which may no longer be the leader of the partition.
+type brokerErr struct {
+	error
+}
+
+func (e brokerErr) Unwrap() error {
+	return e.error
+}
+
+// isRetriableFetchErr reports whether the fetch can succeed after looking up the leader of the partition again,
+// the errors sarama's consumer redispatches on.
+func isRetriableFetchErr(err error) bool {
+	var brokerErr brokerErr
+	return errors.As(err, &brokerErr) ||
+		errors.Is(err, sarama.ErrUnknownTopicOrPartition) ||
+		errors.Is(err, sarama.ErrNotLeaderForPartition) ||
+		errors.Is(err, sarama.ErrLeaderNotAvailable) ||
+		errors.Is(err, sarama.ErrReplicaNotAvailable) ||
+		errors.Is(err, sarama.ErrFencedLeaderEpoch) ||
+		errors.Is(err, sarama.ErrUnknownLeaderEpoch)
+}
+
+// batchTransactionState determines the transaction state of the records in the batch.
+func batchTransactionState(
+	batch *sarama.RecordBatch,
+	abortedProducers map[int64]struct{},
+	lastStableOffset int64,
+) TransactionState {
+	if batch.Control {
+		// the key of a control record holds its version followed by its type
+		if len(batch.Records) > 0 && len(batch.Records[0].Key) >= 4 &&
+			binary.BigEndian.Uint16(batch.Records[0].Key[2:4]) == controlRecordAbort {
+			return AbortMarker
+		}
+		return CommitMarker
+	}
+	if !batch.IsTransactional {
+		return NoTransaction
+	}
+	if _, aborted := abortedProducers[batch.ProducerID]; aborted {
+		return AbortedTransaction
+	}
+	if batch.LastOffset() >= lastStableOffset {
+		return OpenTransaction
+	}
+	return CommittedTransaction
+}
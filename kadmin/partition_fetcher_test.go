@@ -0,0 +1,202 @@
+package kadmin
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBatchTransactionState(t *testing.T) {
+	controlBatch := func(recordType byte) *sarama.RecordBatch {
+		return &sarama.RecordBatch{
+			Control:         true,
+			IsTransactional: true,
+			ProducerID:      1,
+			Records:         []*sarama.Record{{Key: []byte{0, 0, 0, recordType}}},
+		}
+	}
+	transactionalBatch := func(producerID int64, firstOffset int64) *sarama.RecordBatch {
+		return &sarama.RecordBatch{
+			IsTransactional: true,
+			ProducerID:      producerID,
+			FirstOffset:     firstOffset,
+			LastOffsetDelta: 1,
+		}
+	}
+	abortedProducers := map[int64]struct{}{2: {}}
+
+	tests := []struct {
+		name  string
+		batch *sarama.RecordBatch
+		want  TransactionState
+	}{
+		{"commit marker", controlBatch(1), CommitMarker},
+		{"abort marker", controlBatch(0), AbortMarker},
+		{"not transactional", &sarama.RecordBatch{ProducerID: 2}, NoTransaction},
+		{"aborted transaction", transactionalBatch(2, 10), AbortedTransaction},
+		{"committed transaction", transactionalBatch(1, 10), CommittedTransaction},
+		{"open transaction", transactionalBatch(1, 99), OpenTransaction},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, batchTransactionState(test.batch, abortedProducers, 100))
+		})
+	}
+}
+
+func TestTransactionStatesByMarkers(t *testing.T) {
+	batch := func(producerID int64, firstOffset int64) *sarama.RecordBatch {
+		return &sarama.RecordBatch{IsTransactional: true, ProducerID: producerID, FirstOffset: firstOffset}
+	}
+	marker := func(producerID int64, firstOffset int64, recordType byte) *sarama.RecordBatch {
+		return &sarama.RecordBatch{
+			Control:         true,
+			IsTransactional: true,
+			ProducerID:      producerID,
+			FirstOffset:     firstOffset,
+			Records:         []*sarama.Record{{Key: []byte{0, 0, 0, recordType}}},
+		}
+	}
+
+	states := transactionStatesByMarkers([]*sarama.RecordBatch{
+		batch(1, 0),
+		batch(2, 1),
+		{ProducerID: 3, FirstOffset: 2},
+		marker(2, 3, 0),
+		batch(2, 4),
+		marker(2, 5, 1),
+		batch(1, 6),
+	}, nil)
+
+	assert.Equal(t, []TransactionState{
+		OpenTransaction,
+		AbortedTransaction,
+		NoTransaction,
+		AbortMarker,
+		CommittedTransaction,
+		CommitMarker,
+		OpenTransaction,
+	}, states)
+}
+
+// newFetchingAdmin creates an admin fetching the partition 0 of the orders topic from the broker,
+// which responds to the fetches with the responses in order.
+func newFetchingAdmin(t *testing.T, broker *sarama.MockBroker, fetchResponses ...interface{}) *SaramaKafkaAdmin {
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("orders", 0, broker.BrokerID()),
+		"FetchRequest": sarama.NewMockSequence(fetchResponses...),
+	})
+	config := sarama.NewConfig()
+	config.Consumer.Retry.Backoff = time.Millisecond
+	client, err := sarama.NewClient([]string{broker.Addr()}, config)
+	if err != nil {
+		t.Fatal("Unable to create client", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return &SaramaKafkaAdmin{client: client, config: config}
+}
+
+func TestFetch(t *testing.T) {
+	newAdmin := func(broker *sarama.MockBroker, fetchResponses ...interface{}) *SaramaKafkaAdmin {
+		return newFetchingAdmin(t, broker, fetchResponses...)
+	}
+	notLeader := func() sarama.MockResponse {
+		response := &sarama.FetchResponse{Version: 4}
+		response.AddError("orders", 0, sarama.ErrNotLeaderForPartition)
+		return sarama.NewMockWrapper(response)
+	}
+
+	t.Run("Retry when the leader moved", func(t *testing.T) {
+		broker := sarama.NewMockBroker(t, 1)
+		defer broker.Close()
+		ka := newAdmin(broker, notLeader(), notLeader(), sarama.NewMockFetchResponse(t, 1).
+			SetMessage("orders", 0, 0, sarama.StringEncoder("order-1")).
+			SetHighWaterMark("orders", 0, 1))
+
+		block, err := ka.fetch(context.Background(), "orders", 0, 0, sarama.ReadCommitted)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), block.HighWaterMarkOffset)
+	})
+
+	t.Run("Give up after retrying a number of times", func(t *testing.T) {
+		broker := sarama.NewMockBroker(t, 1)
+		defer broker.Close()
+		var responses []interface{}
+		for i := 0; i <= maxFetchRetries; i++ {
+			responses = append(responses, notLeader())
+		}
+		ka := newAdmin(broker, responses...)
+
+		_, err := ka.fetch(context.Background(), "orders", 0, 0, sarama.ReadCommitted)
+
+		assert.ErrorIs(t, err, sarama.ErrNotLeaderForPartition)
+	})
+
+	t.Run("Do not retry other errors", func(t *testing.T) {
+		broker := sarama.NewMockBroker(t, 1)
+		defer broker.Close()
+		outOfRange := &sarama.FetchResponse{Version: 4}
+		outOfRange.AddError("orders", 0, sarama.ErrOffsetOutOfRange)
+		ka := newAdmin(broker, sarama.NewMockWrapper(outOfRange))
+
+		_, err := ka.fetch(context.Background(), "orders", 0, 0, sarama.ReadCommitted)
+
+		assert.ErrorIs(t, err, sarama.ErrOffsetOutOfRange)
+	})
+}
+
+func TestCommittedRecordsLeft(t *testing.T) {
+	const (
+		committingProducer = 1
+		abortingProducer   = 2
+	)
+	// a committed record, an aborted record and their markers
+	fetched := func() *sarama.FetchResponse {
+		response := &sarama.FetchResponse{Version: 4}
+		response.AddRecordBatch("orders", 0, nil, sarama.StringEncoder("order-1"), 0, committingProducer, true)
+		response.AddRecordBatch("orders", 0, nil, sarama.StringEncoder("order-2"), 1, abortingProducer, true)
+		response.AddControlRecord("orders", 0, 2, committingProducer, sarama.ControlRecordCommit)
+		response.AddControlRecord("orders", 0, 3, abortingProducer, sarama.ControlRecordAbort)
+		response.SetLastStableOffset("orders", 0, 4)
+		block := response.GetBlock("orders", 0)
+		block.HighWaterMarkOffset = 4
+		block.AbortedTransactions = []*sarama.AbortedTransaction{{ProducerID: abortingProducer, FirstOffset: 1}}
+		return response
+	}
+
+	t.Run("Committed records are left", func(t *testing.T) {
+		broker := sarama.NewMockBroker(t, 1)
+		defer broker.Close()
+		ka := newFetchingAdmin(t, broker, sarama.NewMockWrapper(fetched()))
+
+		left, err := ka.committedRecordsLeft(context.Background(), "orders", 0, 0, 3)
+
+		assert.NoError(t, err)
+		assert.True(t, left)
+	})
+
+	t.Run("No records are left when only aborted records and markers remain", func(t *testing.T) {
+		broker := sarama.NewMockBroker(t, 1)
+		defer broker.Close()
+		ka := newFetchingAdmin(t, broker, sarama.NewMockWrapper(fetched()))
+
+		left, err := ka.committedRecordsLeft(context.Background(), "orders", 0, 1, 3)
+
+		assert.NoError(t, err)
+		assert.False(t, left)
+	})
+
+	t.Run("No records are left past the end offset", func(t *testing.T) {
+		ka := &SaramaKafkaAdmin{}
+
+		left, err := ka.committedRecordsLeft(context.Background(), "orders", 0, 4, 3)
+
+		assert.NoError(t, err)
+		assert.False(t, left)
+	})
+}
@@ -0,0 +1,354 @@
+package kadmin
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/linkedin/goavro/v2"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	JsonLinesExportFormat ExportFormat = "jsonl"
+	CsvExportFormat       ExportFormat = "csv"
+	AvroExportFormat      ExportFormat = "avro"
+)
+
+// exportedRecordSchema is the schema of the records written to an Avro Object Container File.
+// The (deserialized) value is stored as a string, as records of a single topic
+// are not guaranteed to share one schema.
+const exportedRecordSchema = `
+{
+	"type": "record",
+	"name": "ConsumerRecord",
+	"namespace": "io.ktea.export",
+	"fields": [
+		{"name": "key", "type": ["null", "string"]},
+		{"name": "value", "type": ["null", "string"]},
+		{"name": "headers", "type": {"type": "map", "values": "string"}},
+		{"name": "partition", "type": "long"},
+		{"name": "offset", "type": "long"},
+		{"name": "timestamp", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "schemaId", "type": ["null", "int"]}
+	]
+}`
+
+type RecordExporter interface {
+	ExportRecords(ctx context.Context, ed ExportDetails) tea.Msg
+}
+
+type ExportDetails struct {
+	Format ExportFormat
+	Path   string
+	// Records are the records to export, when nil
+	// the records are streamed from the topic using ReadDetails.
+	Records     []ConsumerRecord
+	ReadDetails *ReadDetails
+}
+
+type ExportStartedMsg struct {
+	Exported chan int
+	Err      chan error
+	path     string
+}
+
+type RecordsExportedMsg struct {
+	Count int
+	Path  string
+}
+
+type RecordsExportErrMsg struct {
+	Err error
+}
+
+func (msg *ExportStartedMsg) AwaitCompletion() tea.Msg {
+	select {
+	case count := <-msg.Exported:
+		return RecordsExportedMsg{Count: count, Path: msg.path}
+	case err := <-msg.Err:
+		return RecordsExportErrMsg{Err: err}
+	}
+}
+
+// RecordWriter writes ConsumerRecords in a specific ExportFormat.
+type RecordWriter interface {
+	Write(record ConsumerRecord) error
+	// Close flushes all buffered records, it does not close the underlying io.Writer.
+	Close() error
+}
+
+// JsonRecord is the representation of a record within a JSON Lines file.
+type JsonRecord struct {
+	Key       *string           `json:"key"`
+	Value     *string           `json:"value"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Partition *int64            `json:"partition,omitempty"`
+	Offset    *int64            `json:"offset,omitempty"`
+	Timestamp *time.Time        `json:"timestamp,omitempty"`
+	SchemaId  *int              `json:"schemaId,omitempty"`
+}
+
+func toJsonRecord(record ConsumerRecord) JsonRecord {
+	jr := JsonRecord{
+		Key:       &record.Key,
+		Value:     &record.Payload.Value,
+		Headers:   headersAsMap(record.Headers),
+		Partition: &record.Partition,
+		Offset:    &record.Offset,
+		Timestamp: &record.Timestamp,
+	}
+	if record.Payload.SchemaId != 0 {
+		jr.SchemaId = &record.Payload.SchemaId
+	}
+	// tombstones are exported as a null value, so they are imported as tombstones again
+	if record.Tombstone {
+		jr.Value = nil
+	}
+	return jr
+}
+
+func headersAsMap(headers []Header) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(headers))
+	for _, h := range headers {
+		m[h.Key] = h.Value.String()
+	}
+	return m
+}
+
+type jsonLinesWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonLinesWriter) Write(record ConsumerRecord) error {
+	return w.encoder.Encode(toJsonRecord(record))
+}
+
+func (w *jsonLinesWriter) Close() error {
+	return nil
+}
+
+var csvHeader = []string{"key", "value", "headers", "partition", "offset", "timestamp", "schema_id"}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvWriter) Write(record ConsumerRecord) error {
+	if !w.headerWritten {
+		if err := w.writer.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	var headers string
+	if len(record.Headers) > 0 {
+		h, err := json.Marshal(headersAsMap(record.Headers))
+		if err != nil {
+			return err
+		}
+		headers = string(h)
+	}
+
+	var schemaId string
+	if record.Payload.SchemaId != 0 {
+		schemaId = strconv.Itoa(record.Payload.SchemaId)
+	}
+
+	return w.writer.Write([]string{
+		record.Key,
+		record.Payload.Value,
+		headers,
+		strconv.FormatInt(record.Partition, 10),
+		strconv.FormatInt(record.Offset, 10),
+		record.Timestamp.Format(time.RFC3339Nano),
+		schemaId,
+	})
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type avroWriter struct {
+	writer *goavro.OCFWriter
+}
+
+func (w *avroWriter) Write(record ConsumerRecord) error {
+	headers := make(map[string]interface{}, len(record.Headers))
+	for _, h := range record.Headers {
+		headers[h.Key] = h.Value.String()
+	}
+
+	var schemaId interface{}
+	if record.Payload.SchemaId != 0 {
+		schemaId = goavro.Union("int", int32(record.Payload.SchemaId))
+	}
+
+	var value interface{}
+	if !record.Tombstone {
+		value = goavro.Union("string", record.Payload.Value)
+	}
+
+	return w.writer.Append([]map[string]interface{}{
+		{
+			"key":       goavro.Union("string", record.Key),
+			"value":     value,
+			"headers":   headers,
+			"partition": record.Partition,
+			"offset":    record.Offset,
+			"timestamp": record.Timestamp,
+			"schemaId":  schemaId,
+		},
+	})
+}
+
+func (w *avroWriter) Close() error {
+	return nil
+}
+
+func NewRecordWriter(format ExportFormat, w io.Writer) (RecordWriter, error) {
+	switch format {
+	case JsonLinesExportFormat:
+		return &jsonLinesWriter{json.NewEncoder(w)}, nil
+	case CsvExportFormat:
+		return &csvWriter{writer: csv.NewWriter(w)}, nil
+	case AvroExportFormat:
+		ocfWriter, err := goavro.NewOCFWriter(goavro.OCFConfig{
+			W:               w,
+			Schema:          exportedRecordSchema,
+			CompressionName: goavro.CompressionDeflateLabel,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &avroWriter{ocfWriter}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format: %s", format)
+	}
+}
+
+// WriteRecords writes all records using the given RecordWriter and closes it afterwards.
+func WriteRecords(records []ConsumerRecord, rw RecordWriter) error {
+	for _, record := range records {
+		if err := rw.Write(record); err != nil {
+			return err
+		}
+	}
+	return rw.Close()
+}
+
+// ErrUnboundedRead is returned when streaming records of a read that never ends, like live consumption.
+var ErrUnboundedRead = errors.New("a live consumption never ends, use the loaded records instead")
+
+// StreamRecords writes all records, emitted by the given ReadingStartedMsg,
+// using the given RecordWriter without retaining them in memory.
+// It returns the amount of records written.
+func StreamRecords(rsm ReadingStartedMsg, rw RecordWriter) (int, error) {
+	var (
+		count      int
+		recordChan = rsm.ConsumerRecord
+		errChan    = rsm.Err
+	)
+
+	for recordChan != nil {
+		select {
+		case record, ok := <-recordChan:
+			if !ok {
+				recordChan = nil
+				break
+			}
+			if err := rw.Write(record); err != nil {
+				rsm.CancelFunc()
+				return count, err
+			}
+			count++
+		case <-rsm.EmptyTopic:
+			recordChan = nil
+		case err, ok := <-errChan:
+			if !ok {
+				// both channels are closed at the same time, keep draining the buffered records
+				errChan = nil
+				break
+			}
+			rsm.CancelFunc()
+			return count, err
+		}
+	}
+
+	return count, rw.Close()
+}
+
+func (ka *SaramaKafkaAdmin) ExportRecords(ctx context.Context, ed ExportDetails) tea.Msg {
+	// buffered, so the export finishes when the page no longer awaits it
+	exportedChan := make(chan int, 1)
+	errChan := make(chan error, 1)
+
+	go ka.doExportRecords(ctx, ed, exportedChan, errChan)
+
+	return ExportStartedMsg{
+		Exported: exportedChan,
+		Err:      errChan,
+		path:     ed.Path,
+	}
+}
+
+func (ka *SaramaKafkaAdmin) doExportRecords(
+	ctx context.Context,
+	ed ExportDetails,
+	exportedChan chan int,
+	errChan chan error,
+) {
+	MaybeIntroduceLatency()
+
+	if ed.Records == nil && ed.ReadDetails != nil && ed.ReadDetails.Unbounded() {
+		errChan <- ErrUnboundedRead
+		return
+	}
+
+	file, err := os.Create(ed.Path)
+	if err != nil {
+		errChan <- err
+		return
+	}
+	defer file.Close()
+
+	rw, err := NewRecordWriter(ed.Format, file)
+	if err != nil {
+		errChan <- err
+		return
+	}
+
+	var count int
+	if ed.Records != nil || ed.ReadDetails == nil {
+		err = WriteRecords(ed.Records, rw)
+		count = len(ed.Records)
+	} else {
+		msg := ka.ReadRecords(ctx, *ed.ReadDetails)
+		rsm, ok := msg.(ReadingStartedMsg)
+		if !ok {
+			errChan <- fmt.Errorf("unable to read records: unexpected %T", msg)
+			return
+		}
+		count, err = StreamRecords(rsm, rw)
+	}
+	if err != nil {
+		errChan <- err
+		return
+	}
+
+	exportedChan <- count
+}
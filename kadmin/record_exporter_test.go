@@ -0,0 +1,224 @@
+package kadmin
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"ktea/serdes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordWriters(t *testing.T) {
+	timestamp := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	records := []ConsumerRecord{
+		{
+			Key:       "key-1",
+			Payload:   serdes.DesData{Value: `{"id":"123"}`, SchemaId: 7},
+			Partition: 1,
+			Offset:    42,
+			Headers:   []Header{{"trace-id", NewHeaderValue("abc")}},
+			Timestamp: timestamp,
+		},
+		{
+			Key:       "key-2",
+			Payload:   serdes.DesData{Value: "plain text"},
+			Partition: 0,
+			Offset:    3,
+			Timestamp: timestamp,
+		},
+	}
+
+	t.Run("JSON Lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		rw, _ := NewRecordWriter(JsonLinesExportFormat, &buf)
+
+		err := WriteRecords(records, rw)
+
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 2)
+
+		var jr JsonRecord
+		_ = json.Unmarshal([]byte(lines[0]), &jr)
+		assert.Equal(t, "key-1", *jr.Key)
+		assert.Equal(t, `{"id":"123"}`, *jr.Value)
+		assert.Equal(t, map[string]string{"trace-id": "abc"}, jr.Headers)
+		assert.Equal(t, int64(1), *jr.Partition)
+		assert.Equal(t, int64(42), *jr.Offset)
+		assert.Equal(t, 7, *jr.SchemaId)
+		assert.True(t, timestamp.Equal(*jr.Timestamp))
+
+		jr = JsonRecord{}
+		_ = json.Unmarshal([]byte(lines[1]), &jr)
+		assert.Nil(t, jr.SchemaId)
+		assert.Nil(t, jr.Headers)
+	})
+
+	t.Run("Export tombstones as a null value", func(t *testing.T) {
+		var buf bytes.Buffer
+		rw, _ := NewRecordWriter(JsonLinesExportFormat, &buf)
+
+		err := WriteRecords([]ConsumerRecord{{Key: "key-3", Tombstone: true}}, rw)
+
+		assert.NoError(t, err)
+		var jr JsonRecord
+		_ = json.Unmarshal(buf.Bytes(), &jr)
+		assert.Equal(t, "key-3", *jr.Key)
+		assert.Nil(t, jr.Value)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		rw, _ := NewRecordWriter(CsvExportFormat, &buf)
+
+		err := WriteRecords(records, rw)
+
+		assert.NoError(t, err)
+		rows, _ := csv.NewReader(&buf).ReadAll()
+		assert.Equal(t, [][]string{
+			{"key", "value", "headers", "partition", "offset", "timestamp", "schema_id"},
+			{"key-1", `{"id":"123"}`, `{"trace-id":"abc"}`, "1", "42", "2025-03-01T10:30:00Z", "7"},
+			{"key-2", "plain text", "", "0", "3", "2025-03-01T10:30:00Z", ""},
+		}, rows)
+	})
+
+	t.Run("Avro Object Container File", func(t *testing.T) {
+		var buf bytes.Buffer
+		rw, _ := NewRecordWriter(AvroExportFormat, &buf)
+
+		err := WriteRecords(records, rw)
+
+		assert.NoError(t, err)
+		ocfReader, err := goavro.NewOCFReader(&buf)
+		assert.NoError(t, err)
+
+		var decoded []map[string]interface{}
+		for ocfReader.Scan() {
+			datum, err := ocfReader.Read()
+			assert.NoError(t, err)
+			decoded = append(decoded, datum.(map[string]interface{}))
+		}
+		assert.Len(t, decoded, 2)
+		assert.Equal(t, map[string]interface{}{"string": "key-1"}, decoded[0]["key"])
+		assert.Equal(t, map[string]interface{}{"string": `{"id":"123"}`}, decoded[0]["value"])
+		assert.Equal(t, map[string]interface{}{"trace-id": "abc"}, decoded[0]["headers"])
+		assert.Equal(t, int64(42), decoded[0]["offset"])
+		assert.Equal(t, map[string]interface{}{"int": int32(7)}, decoded[0]["schemaId"])
+		assert.Nil(t, decoded[1]["schemaId"])
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		_, err := NewRecordWriter("xml", &bytes.Buffer{})
+
+		assert.EqualError(t, err, "unknown export format: xml")
+	})
+
+	t.Run("Stream records from a reading started msg", func(t *testing.T) {
+		rsm := ReadingStartedMsg{
+			ConsumerRecord: make(chan ConsumerRecord, len(records)),
+			Err:            make(chan error),
+			EmptyTopic:     make(chan bool),
+			CancelFunc:     func() {},
+		}
+		for _, r := range records {
+			rsm.ConsumerRecord <- r
+		}
+		close(rsm.ConsumerRecord)
+		close(rsm.Err)
+
+		var buf bytes.Buffer
+		rw, _ := NewRecordWriter(JsonLinesExportFormat, &buf)
+
+		count, err := StreamRecords(rsm, rw)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 2)
+	})
+}
+
+func TestExportRecords(t *testing.T) {
+	t.Run("Refuse streaming a live consumption", func(t *testing.T) {
+		path := t.TempDir() + "/export.jsonl"
+		esm := (&SaramaKafkaAdmin{}).ExportRecords(context.Background(), ExportDetails{
+			Format: JsonLinesExportFormat,
+			Path:   path,
+			ReadDetails: &ReadDetails{
+				TopicName:       "orders",
+				PartitionToRead: []int{0},
+				StartPoint:      Live,
+			},
+		}).(ExportStartedMsg)
+
+		exportErr := esm.AwaitCompletion().(RecordsExportErrMsg)
+		assert.ErrorIs(t, exportErr.Err, ErrUnboundedRead)
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("Finish exporting when the completion is no longer awaited", func(t *testing.T) {
+		path := t.TempDir() + "/export.jsonl"
+		esm := (&SaramaKafkaAdmin{}).ExportRecords(context.Background(), ExportDetails{
+			Format:  JsonLinesExportFormat,
+			Path:    path,
+			Records: []ConsumerRecord{{Key: "1", Payload: serdes.DesData{Value: "one"}}},
+		}).(ExportStartedMsg)
+
+		assert.Eventually(t, func() bool {
+			return len(esm.Exported) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Stream from topic", func(t *testing.T) {
+		topic := topicName()
+		// given
+		msg := ka.CreateTopic(TopicCreationDetails{
+			Name:              topic,
+			NumPartitions:     1,
+			ReplicationFactor: 1,
+		}).(TopicCreationStartedMsg)
+
+		switch msg.AwaitCompletion().(type) {
+		case TopicCreatedMsg:
+		case TopicCreationErrMsg:
+			t.Fatal("Unable to create topic", msg.Err)
+		}
+
+		for i := 0; i < 10; i++ {
+			psm := ka.PublishRecord(&ProducerRecord{
+				Topic: topic,
+				Key:   "key",
+				Value: []byte("{\"id\":\"123\"}"),
+			})
+			switch psm.AwaitCompletion().(type) {
+			case PublicationFailed:
+				t.Fatal("Unable to publish")
+			}
+		}
+
+		// when
+		path := t.TempDir() + "/export.jsonl"
+		esm := ka.ExportRecords(context.Background(), ExportDetails{
+			Format: JsonLinesExportFormat,
+			Path:   path,
+			ReadDetails: &ReadDetails{
+				TopicName:       topic,
+				PartitionToRead: []int{0},
+				StartPoint:      Beginning,
+				Limit:           50,
+			},
+		}).(ExportStartedMsg)
+
+		// then
+		exported := esm.AwaitCompletion().(RecordsExportedMsg)
+		assert.Equal(t, 10, exported.Count)
+		assert.Equal(t, path, exported.Path)
+
+		// clean up
+		ka.DeleteTopic(topic)
+	})
+}
@@ -0,0 +1,262 @@
+package kadmin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxImportLineSize is the maximum size of a single line within an imported JSON Lines file.
+const maxImportLineSize = 10 * 1024 * 1024
+
+type RecordImporter interface {
+	ImportRecords(id ImportDetails) tea.Msg
+}
+
+type ImportDetails struct {
+	Topic          string
+	PartitionCount int
+	Path           string
+	// BatchSize is the number of records that are published concurrently
+	// before progress is reported.
+	BatchSize int
+	// DryRun parses and validates all lines without publishing them.
+	DryRun bool
+	// IgnorePartitions publishes records using the default partitioner
+	// instead of the partition mentioned in the file.
+	IgnorePartitions bool
+}
+
+// LineError is an error that occurred while importing a specific line.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err.Error())
+}
+
+type ImportProgressMsg struct {
+	// Processed is the number of lines that have been processed so far.
+	Processed int
+	Total     int
+}
+
+type RecordsImportedMsg struct {
+	Published int
+	Errors    []LineError
+	DryRun    bool
+}
+
+type RecordsImportErrMsg struct {
+	Err error
+}
+
+type ImportStartedMsg struct {
+	Progress chan ImportProgressMsg
+	Imported chan RecordsImportedMsg
+	Err      chan error
+}
+
+// AwaitActivity returns
+// an ImportProgressMsg while importing,
+// a RecordsImportedMsg upon completion
+// or a RecordsImportErrMsg when the file could not be processed.
+func (msg *ImportStartedMsg) AwaitActivity() tea.Msg {
+	select {
+	case progress := <-msg.Progress:
+		return progress
+	case imported := <-msg.Imported:
+		return imported
+	case err := <-msg.Err:
+		return RecordsImportErrMsg{Err: err}
+	}
+}
+
+type importLine struct {
+	number int
+	record *ProducerRecord
+}
+
+func (ka *SaramaKafkaAdmin) ImportRecords(id ImportDetails) tea.Msg {
+	return importRecords(ka, id)
+}
+
+func importRecords(publisher Publisher, id ImportDetails) tea.Msg {
+	startedMsg := ImportStartedMsg{
+		Progress: make(chan ImportProgressMsg),
+		Imported: make(chan RecordsImportedMsg),
+		Err:      make(chan error),
+	}
+
+	go doImportRecords(publisher, id, startedMsg)
+
+	return startedMsg
+}
+
+func doImportRecords(publisher Publisher, id ImportDetails, startedMsg ImportStartedMsg) {
+	total, err := countLines(id.Path)
+	if err != nil {
+		startedMsg.Err <- err
+		return
+	}
+
+	file, err := os.Open(id.Path)
+	if err != nil {
+		startedMsg.Err <- err
+		return
+	}
+	defer file.Close()
+
+	batchSize := id.BatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	var (
+		imported = RecordsImportedMsg{DryRun: id.DryRun}
+		batch    []importLine
+		lineNr   int
+	)
+
+	publishBatch := func() {
+		published := len(batch)
+		if !id.DryRun {
+			lineErrors := publishLines(publisher, batch)
+			imported.Errors = append(imported.Errors, lineErrors...)
+			published -= len(lineErrors)
+		}
+		imported.Published += published
+		batch = batch[:0]
+		startedMsg.Progress <- ImportProgressMsg{Processed: lineNr, Total: total}
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLineSize)
+	for scanner.Scan() {
+		lineNr++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		record, err := parseImportLine(line, id)
+		if err != nil {
+			imported.Errors = append(imported.Errors, LineError{lineNr, err})
+			continue
+		}
+
+		batch = append(batch, importLine{lineNr, record})
+		if len(batch) == batchSize {
+			publishBatch()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		startedMsg.Err <- err
+		return
+	}
+	if len(batch) > 0 {
+		publishBatch()
+	}
+
+	startedMsg.Imported <- imported
+}
+
+// publishLines publishes all lines concurrently and returns the lines that failed.
+func publishLines(publisher Publisher, lines []importLine) []LineError {
+	var lineErrors []LineError
+
+	publications := make([]PublicationStartedMsg, len(lines))
+	for i, line := range lines {
+		publications[i] = publisher.PublishRecord(line.record)
+	}
+
+	for i, publication := range publications {
+		if failed, ok := publication.AwaitCompletion().(PublicationFailed); ok {
+			lineErrors = append(lineErrors, LineError{
+				lines[i].number,
+				fmt.Errorf("publication failed: %w", failed.Err),
+			})
+		}
+	}
+
+	return lineErrors
+}
+
+func parseImportLine(line string, id ImportDetails) (*ProducerRecord, error) {
+	var jr JsonRecord
+	if err := json.Unmarshal([]byte(line), &jr); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	record := &ProducerRecord{
+		Topic:   id.Topic,
+		Headers: jr.Headers,
+	}
+
+	if jr.Key != nil {
+		record.Key = *jr.Key
+	} else {
+		record.NullKey = true
+	}
+
+	if jr.Value != nil {
+		record.Value = []byte(*jr.Value)
+	}
+
+	if jr.Partition != nil && !id.IgnorePartitions {
+		partition := int(*jr.Partition)
+		if partition < 0 || (id.PartitionCount > 0 && partition >= id.PartitionCount) {
+			return nil, fmt.Errorf("partition %d is invalid, valid range is 0-%d", partition, id.PartitionCount-1)
+		}
+		record.Partition = &partition
+	}
+
+	if jr.Timestamp != nil {
+		record.Timestamp = *jr.Timestamp
+	}
+
+	return record, nil
+}
+
+func countLines(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var (
+		count    int
+		lastByte byte = '\n'
+		buf           = make([]byte, 32*1024)
+	)
+	for {
+		n, err := file.Read(buf)
+		for _, b := range buf[:n] {
+			if b == '\n' {
+				count++
+			}
+		}
+		if n > 0 {
+			lastByte = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	// last line without a trailing newline
+	if lastByte != '\n' {
+		count++
+	}
+
+	return count, nil
+}
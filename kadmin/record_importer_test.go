@@ -0,0 +1,177 @@
+package kadmin
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type capturingPublisher struct {
+	mu      sync.Mutex
+	records []*ProducerRecord
+	failKey string
+}
+
+func (p *capturingPublisher) PublishRecord(record *ProducerRecord) PublicationStartedMsg {
+	errChan := make(chan error, 1)
+	published := make(chan bool, 1)
+	if record.Key == p.failKey {
+		errChan <- errors.New("broker unavailable")
+	} else {
+		p.mu.Lock()
+		p.records = append(p.records, record)
+		p.mu.Unlock()
+		published <- true
+	}
+	return PublicationStartedMsg{Err: errChan, Published: published}
+}
+
+func awaitImport(t *testing.T, msg ImportStartedMsg) (RecordsImportedMsg, []ImportProgressMsg) {
+	var progress []ImportProgressMsg
+	for {
+		switch activity := msg.AwaitActivity().(type) {
+		case ImportProgressMsg:
+			progress = append(progress, activity)
+		case RecordsImportedMsg:
+			return activity, progress
+		case RecordsImportErrMsg:
+			t.Fatal("Unable to import", activity.Err)
+		}
+	}
+}
+
+func writeImportFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "records.jsonl")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportRecords(t *testing.T) {
+	t.Run("Publish every line", func(t *testing.T) {
+		path := writeImportFile(t, `{"key":"1","value":"{\"id\":1}","headers":{"h1":"v1"},"partition":2,"timestamp":"2025-03-01T10:30:00Z"}
+{"key":"2","value":"{\"id\":2}"}
+
+{"key":"3","value":"{\"id\":3}"}`)
+		publisher := &capturingPublisher{}
+
+		msg := importRecords(publisher, ImportDetails{
+			Topic:          "topic1",
+			PartitionCount: 3,
+			Path:           path,
+			BatchSize:      2,
+		}).(ImportStartedMsg)
+
+		imported, progress := awaitImport(t, msg)
+
+		assert.Equal(t, 3, imported.Published)
+		assert.Empty(t, imported.Errors)
+		assert.Equal(t, []ImportProgressMsg{{2, 4}, {4, 4}}, progress)
+		assert.Len(t, publisher.records, 3)
+
+		first := publisher.records[0]
+		assert.Equal(t, "topic1", first.Topic)
+		assert.Equal(t, "1", first.Key)
+		assert.Equal(t, []byte(`{"id":1}`), first.Value)
+		assert.Equal(t, map[string]string{"h1": "v1"}, first.Headers)
+		assert.Equal(t, 2, *first.Partition)
+		assert.Equal(t, time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC), first.Timestamp)
+	})
+
+	t.Run("Publish a missing key as a null key", func(t *testing.T) {
+		path := writeImportFile(t, `{"value":"a"}
+{"key":"","value":"b"}
+`)
+		publisher := &capturingPublisher{failKey: "fail"}
+
+		msg := importRecords(publisher, ImportDetails{
+			Topic:     "topic1",
+			Path:      path,
+			BatchSize: 10,
+		}).(ImportStartedMsg)
+
+		imported, _ := awaitImport(t, msg)
+
+		assert.Equal(t, 2, imported.Published)
+		assert.True(t, publisher.records[0].NullKey)
+		assert.False(t, publisher.records[1].NullKey)
+	})
+
+	t.Run("Report errors per line", func(t *testing.T) {
+		path := writeImportFile(t, `{"key":"1","value":"a"}
+not json
+{"key":"3","value":"c","partition":5}
+{"key":"fail","value":"d"}
+`)
+		publisher := &capturingPublisher{failKey: "fail"}
+
+		msg := importRecords(publisher, ImportDetails{
+			Topic:          "topic1",
+			PartitionCount: 3,
+			Path:           path,
+			BatchSize:      10,
+		}).(ImportStartedMsg)
+
+		imported, _ := awaitImport(t, msg)
+
+		assert.Equal(t, 1, imported.Published)
+		assert.Len(t, imported.Errors, 3)
+		assert.Equal(t, 2, imported.Errors[0].Line)
+		assert.Contains(t, imported.Errors[0].Error(), "invalid JSON")
+		assert.Equal(t, 3, imported.Errors[1].Line)
+		assert.EqualError(t, imported.Errors[1], "line 3: partition 5 is invalid, valid range is 0-2")
+		assert.Equal(t, 4, imported.Errors[2].Line)
+		assert.EqualError(t, imported.Errors[2], "line 4: publication failed: broker unavailable")
+	})
+
+	t.Run("Ignore partitions from file", func(t *testing.T) {
+		path := writeImportFile(t, `{"key":"1","value":"a","partition":5}`)
+		publisher := &capturingPublisher{}
+
+		msg := importRecords(publisher, ImportDetails{
+			Topic:            "topic1",
+			PartitionCount:   3,
+			Path:             path,
+			BatchSize:        1,
+			IgnorePartitions: true,
+		}).(ImportStartedMsg)
+
+		imported, _ := awaitImport(t, msg)
+
+		assert.Equal(t, 1, imported.Published)
+		assert.Nil(t, publisher.records[0].Partition)
+	})
+
+	t.Run("Dry run does not publish", func(t *testing.T) {
+		path := writeImportFile(t, `{"key":"1","value":"a"}
+{"key":"2","value":"b"}
+`)
+		publisher := &capturingPublisher{}
+
+		msg := importRecords(publisher, ImportDetails{
+			Topic:     "topic1",
+			Path:      path,
+			BatchSize: 10,
+			DryRun:    true,
+		}).(ImportStartedMsg)
+
+		imported, _ := awaitImport(t, msg)
+
+		assert.True(t, imported.DryRun)
+		assert.Equal(t, 2, imported.Published)
+		assert.Empty(t, publisher.records)
+	})
+
+	t.Run("Unreadable file", func(t *testing.T) {
+		msg := importRecords(&capturingPublisher{}, ImportDetails{
+			Path: filepath.Join(t.TempDir(), "missing.jsonl"),
+		}).(ImportStartedMsg)
+
+		assert.IsType(t, RecordsImportErrMsg{}, msg.AwaitActivity())
+	})
+}
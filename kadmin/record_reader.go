@@ -1,9 +1,10 @@
 package kadmin
 
 import (
-	"bytes"
 	"context"
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"github.com/charmbracelet/log"
 	"ktea/serdes"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/IBM/sarama"
@@ -44,6 +46,71 @@ const (
 	Live       StartPoint = 2
 )
 
+// IsolationLevel determines whether records of open and aborted transactions are read.
+type IsolationLevel int
+
+const (
+	ReadUncommitted IsolationLevel = 0
+	ReadCommitted   IsolationLevel = 1
+)
+
+// TransactionState tells whether a record was produced in a transaction and how that transaction ended.
+// It is only determined when ReadDetails.ShowTransactions is set.
+type TransactionState int
+
+const (
+	NoTransaction TransactionState = iota
+	CommittedTransaction
+	// OpenTransaction records belong to a transaction that has not been committed or aborted yet.
+	OpenTransaction
+	AbortedTransaction
+	// CommitMarker and AbortMarker are the control records ending a transaction.
+	CommitMarker
+	AbortMarker
+)
+
+func (s TransactionState) IsMarker() bool {
+	return s == CommitMarker || s == AbortMarker
+}
+
+func (s TransactionState) String() string {
+	switch s {
+	case CommittedTransaction:
+		return "committed"
+	case OpenTransaction:
+		return "open"
+	case AbortedTransaction:
+		return "aborted"
+	case CommitMarker:
+		return "commit marker"
+	case AbortMarker:
+		return "abort marker"
+	default:
+		return ""
+	}
+}
+
+// TimestampType tells whether the timestamp of a record was set by the producer or by the broker
+// when the record was appended to the log.
+type TimestampType int
+
+const (
+	UnknownTimestampType TimestampType = iota
+	CreateTime
+	LogAppendTime
+)
+
+func (t TimestampType) String() string {
+	switch t {
+	case CreateTime:
+		return "CreateTime"
+	case LogAppendTime:
+		return "LogAppendTime"
+	default:
+		return "unknown"
+	}
+}
+
 type RecordReader interface {
 	ReadRecords(ctx context.Context, rd ReadDetails) tea.Msg
 }
@@ -53,6 +120,10 @@ type ReadingStartedMsg struct {
 	EmptyTopic     chan bool
 	Err            chan error
 	CancelFunc     context.CancelFunc
+	// MarkRead marks the record as read by the consumer group, when its offsets are committed, once the
+	// record is displayed. The group is left, after committing the marked offsets, once the context of the
+	// read is done.
+	MarkRead func(record ConsumerRecord)
 }
 
 type Filter struct {
@@ -66,8 +137,34 @@ type ReadDetails struct {
 	TopicName       string
 	PartitionToRead []int
 	StartPoint      StartPoint
-	Limit           int
 	Filter          *Filter
+	// Limit is the maximum number of records to read,
+	// live consumption without a Limit keeps reading until it is cancelled.
+	Limit int
+	// KeyFormat and ValueFormat select the deserializer of the keys and values,
+	// serdes.AutoFormat uses the local schemas or the Schema Registry.
+	KeyFormat   serdes.Format
+	ValueFormat serdes.Format
+	// ConsumerGroup, when set, starts reading the partitions at the offsets committed by the group,
+	// partitions without a committed offset are read from the StartPoint.
+	ConsumerGroup string
+	// CommitOffsets commits the offsets of the read records to the ConsumerGroup.
+	CommitOffsets  bool
+	IsolationLevel IsolationLevel
+	// ShowTransactions includes the transaction markers and flags the records of open and aborted transactions.
+	ShowTransactions bool
+}
+
+// Unbounded reports whether reading keeps going until it is cancelled, as live consumption does
+// and reads without a Limit would.
+func (rd ReadDetails) Unbounded() bool {
+	return rd.StartPoint == Live || rd.Limit <= 0
+}
+
+// readsTransactions reports whether the records are fetched by fetchPartition,
+// as sarama's consumer hides the transaction markers and which records were aborted.
+func (rd ReadDetails) readsTransactions() bool {
+	return rd.ShowTransactions
 }
 
 type HeaderValue struct {
@@ -78,42 +175,37 @@ func NewHeaderValue(data string) HeaderValue {
 	return HeaderValue{[]byte(data)}
 }
 
+func NewBinaryHeaderValue(data []byte) HeaderValue {
+	return HeaderValue{data}
+}
+
+// String decodes the value based on its content: printable UTF-8 text as is,
+// 4 and 8 byte values as big-endian int32 and int64.
 func (v HeaderValue) String() string {
-	if utf8.Valid(v.data) {
+	if isText(v.data) {
 		return string(v.data)
 	}
 
-	if len(v.data) >= 4 {
-		var int32Val int32
-		err := binary.Read(bytes.NewReader(v.data), binary.BigEndian, &int32Val)
-		if err == nil {
-			return string(int32Val)
-		}
-	}
-	if len(v.data) >= 8 {
-		var int64Val int64
-		err := binary.Read(bytes.NewReader(v.data), binary.BigEndian, &int64Val)
-		if err == nil {
-			return strconv.FormatInt(int64Val, 10)
-		}
+	switch len(v.data) {
+	case 4:
+		return strconv.FormatInt(int64(int32(binary.BigEndian.Uint32(v.data))), 10)
+	case 8:
+		return strconv.FormatInt(int64(binary.BigEndian.Uint64(v.data)), 10)
 	}
 
-	if len(v.data) >= 4 {
-		var float32Val float32
-		err := binary.Read(bytes.NewReader(v.data), binary.BigEndian, &float32Val)
-		if err == nil {
-			return strconv.FormatFloat(float64(float32Val), 'f', -1, 32)
-		}
+	return string(v.data)
+}
+
+func isText(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
 	}
-	if len(v.data) >= 8 {
-		var float64Val float64
-		err := binary.Read(bytes.NewReader(v.data), binary.BigEndian, &float64Val)
-		if err == nil {
-			return strconv.FormatFloat(float64Val, 'f', -1, 64)
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
 		}
 	}
-
-	return string(v.data)
+	return true
 }
 
 type Header struct {
@@ -122,19 +214,35 @@ type Header struct {
 }
 
 type ConsumerRecord struct {
-	Key       string
-	Payload   serdes.DesData
-	Err       error
-	Partition int64
-	Offset    int64
-	Headers   []Header
-	Timestamp time.Time
+	Key string
+	// KeySchema is the schema used to deserialize the key, empty when the key is not schema based.
+	KeySchema string
+	// KeySchemaId is the Schema Registry id of the key schema, zero when the key is not schema based.
+	KeySchemaId int
+	Payload     serdes.DesData
+	Err         error
+	Partition   int64
+	Offset      int64
+	Headers     []Header
+	Timestamp   time.Time
+	// TimestampType is determined by the message.timestamp.type of the topic.
+	TimestampType TimestampType
+	// BlockTimestamp is the max timestamp of the batch the record was written in.
+	BlockTimestamp time.Time
+	// RawKey and RawValue hold the key and value as they were read from the topic, before deserialization.
+	RawKey      []byte
+	RawValue    []byte
+	Transaction TransactionState
+	// Tombstone is set when the value is null, as opposed to empty, marking the key as deleted in compacted topics.
+	Tombstone bool
 }
 
 type offsets struct {
 	oldest int64
 	// most recent available, unused, offset
 	firstAvailable int64
+	// next offset to read according to the consumer group, negative when the group has not committed one
+	committed int64
 }
 
 func (o *offsets) newest() int64 {
@@ -142,28 +250,58 @@ func (o *offsets) newest() int64 {
 }
 
 func (ka *SaramaKafkaAdmin) ReadRecords(ctx context.Context, rd ReadDetails) tea.Msg {
-	ctx, cancelFunc := context.WithCancel(ctx)
+	readCtx, cancelFunc := context.WithCancel(ctx)
 	startedMsg := ReadingStartedMsg{
 		ConsumerRecord: make(chan ConsumerRecord, len(rd.PartitionToRead)),
-		Err:            make(chan error),
+		Err:            make(chan error, 1),
 		EmptyTopic:     make(chan bool),
 		CancelFunc:     cancelFunc,
 	}
 
-	go ka.doReadRecords(ctx, rd, startedMsg, cancelFunc)
+	group := ka.newGroupOffsets(rd)
+	startedMsg.MarkRead = group.markRead
+
+	go ka.doReadRecords(readCtx, ctx, rd, startedMsg, cancelFunc, group)
 	return startedMsg
 }
 
+// doReadRecords reads the records until ctx is done, the consumer group of the group offsets
+// is left once membership is done.
 func (ka *SaramaKafkaAdmin) doReadRecords(
 	ctx context.Context,
+	membership context.Context,
 	rd ReadDetails,
 	startedMsg ReadingStartedMsg,
 	cancelFunc context.CancelFunc,
+	group *groupOffsets,
 ) {
-	client, err := sarama.NewConsumerFromClient(ka.client)
+	var err error
+	readClient := ka.client
+	if rd.IsolationLevel == ReadCommitted {
+		readClient, err = ka.readCommittedClient()
+		if err != nil {
+			startedMsg.Err <- err
+			close(startedMsg.ConsumerRecord)
+			close(startedMsg.Err)
+			cancelFunc()
+			return
+		}
+	}
+	closeReadClient := func() {
+		if readClient == ka.client {
+			return
+		}
+		if err := readClient.Close(); err != nil {
+			log.Error("Unable to close read committed client", "err", err)
+		}
+	}
+
+	client, err := sarama.NewConsumerFromClient(readClient)
 	if err != nil {
+		closeReadClient()
 		close(startedMsg.ConsumerRecord)
 		close(startedMsg.Err)
+		return
 	}
 
 	var (
@@ -173,95 +311,118 @@ func (ka *SaramaKafkaAdmin) doReadRecords(
 		offsets   map[int]offsets
 	)
 
-	offsets, err = ka.fetchOffsets(rd.PartitionToRead, rd.TopicName)
+	offsets, err = ka.fetchOffsets(readClient, rd.PartitionToRead, rd.TopicName)
 	if err != nil {
+		closeReadClient()
 		startedMsg.Err <- err
 		close(startedMsg.ConsumerRecord)
 		close(startedMsg.Err)
 		cancelFunc()
+		return
 	}
 
-	wg.Add(len(rd.PartitionToRead))
+	if err := ka.fetchGroupOffsets(rd, offsets); err != nil {
+		closeReadClient()
+		startedMsg.Err <- err
+		close(startedMsg.ConsumerRecord)
+		close(startedMsg.Err)
+		cancelFunc()
+		return
+	}
+
+	// the group stays joined while the read records are displayed, after reading ends
+	reading := make(chan struct{})
+	reported := make(chan struct{})
+	if group != nil {
+		if err := group.join(); err != nil {
+			closeReadClient()
+			startedMsg.Err <- OffsetCommitError{Group: group.group, Err: err}
+			close(startedMsg.ConsumerRecord)
+			close(startedMsg.Err)
+			cancelFunc()
+			return
+		}
+		go group.commitWhileMember(membership, reading, reported, startedMsg.Err)
+	} else {
+		close(reported)
+	}
+
+	timestampType := ka.topicTimestampType(rd.TopicName)
+	pool := newDeserializationPool(deserializationWorkers(), func(msg *sarama.ConsumerMessage) ConsumerRecord {
+		record := ka.toConsumerRecord(rd, msg)
+		record.TimestampType = timestampType
+		return record
+	})
 
 	emptyTopic := true
 	for _, partition := range rd.PartitionToRead {
-		// if there is no data in the partition, we don't need to read it unless live consumption is requested
-		if offsets[partition].firstAvailable != offsets[partition].oldest || rd.StartPoint == Live {
+		partitionOffsets := offsets[partition]
+		readingOffsets := ka.determineReadingOffsets(rd, partitionOffsets)
+		// if there is no data left in the partition, we don't need to read it unless live consumption is requested
+		if readingOffsets.start <= partitionOffsets.newest() || rd.StartPoint == Live {
 			emptyTopic = false
+			wg.Add(1)
 			go func(partition int) {
 				defer wg.Done()
 
-				readingOffsets := ka.determineReadingOffsets(rd, offsets[partition])
-				consumer, err := client.ConsumePartition(
-					rd.TopicName,
-					int32(partition),
-					readingOffsets.start,
-				)
-				if err != nil {
-					startedMsg.Err <- err
-					cancelFunc()
-					return
-				}
-				defer consumer.Close()
+				pending := make(chan pendingRecord, pool.size())
+				if rd.readsTransactions() {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						ka.fetchPartition(ctx, rd, partition, readingOffsets, pool, pending, startedMsg.Err)
+					}()
+				} else {
+					consumer, err := client.ConsumePartition(
+						rd.TopicName,
+						int32(partition),
+						readingOffsets.start,
+					)
+					if err != nil {
+						startedMsg.Err <- err
+						cancelFunc()
+						return
+					}
 
-				msgChan := consumer.Messages()
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						defer consumer.Close()
+						ka.submitPartition(ctx, rd, partition, consumer, readingOffsets, pool, pending, startedMsg.Err)
+					}()
+				}
 
-				for {
+				// records are emitted in the order they were consumed
+				for result := range pending {
+					var consumerRecord ConsumerRecord
 					select {
-					case err := <-consumer.Errors():
-						startedMsg.Err <- err
-						return
+					case consumerRecord = <-result:
 					case <-ctx.Done():
 						return
-					case msg := <-msgChan:
-						var headers []Header
-						for _, h := range msg.Headers {
-							headers = append(headers, Header{
-								string(h.Key),
-								HeaderValue{h.Value},
-							})
-						}
-
-						var desData serdes.DesData
-						key := string(msg.Key)
-						desData, err = ka.deserialize(msg)
-
-						if rd.Filter != nil && err == nil {
-							if !ka.matchesFilter(key, desData.Value, rd.Filter) {
-								continue
-							}
-						}
+					}
 
-						consumerRecord := ConsumerRecord{
-							Key:       key,
-							Payload:   desData,
-							Err:       err,
-							Partition: int64(msg.Partition),
-							Offset:    msg.Offset,
-							Headers:   headers,
-							Timestamp: msg.Timestamp,
+					// filtered records are read by the group all the same, once a later record is marked as read
+					if rd.Filter != nil && consumerRecord.Err == nil && !consumerRecord.Transaction.IsMarker() {
+						if !ka.matchesFilter(consumerRecord.Key, consumerRecord.Payload.Value, rd.Filter) {
+							continue
 						}
+					}
 
-						var shouldClose bool
-
-						if msgCount.Add(1) >= int64(rd.Limit) {
-							shouldClose = true
-						}
+					var shouldClose bool
 
-						select {
-						case startedMsg.ConsumerRecord <- consumerRecord:
-						case <-ctx.Done():
-							return
-						}
+					if rd.Limit > 0 && msgCount.Add(1) >= int64(rd.Limit) {
+						shouldClose = true
+					}
 
-						if shouldClose {
-							cancelFunc() // Cancel the context to stop other goroutines
-							return
-						}
+					select {
+					case startedMsg.ConsumerRecord <- consumerRecord:
+					case <-ctx.Done():
+						return
+					}
 
-						if msg.Offset == readingOffsets.end && rd.StartPoint != Live {
-							return
-						}
+					if shouldClose {
+						cancelFunc() // Cancel the context to stop other goroutines
+						return
 					}
 				}
 			}(partition)
@@ -275,6 +436,10 @@ func (ka *SaramaKafkaAdmin) doReadRecords(
 
 	go func() {
 		wg.Wait()
+		pool.close()
+		closeReadClient()
+		close(reading)
+		<-reported
 		closeOnce.Do(func() {
 			close(startedMsg.ConsumerRecord)
 			close(startedMsg.Err)
@@ -282,6 +447,143 @@ func (ka *SaramaKafkaAdmin) doReadRecords(
 	}()
 }
 
+// submitPartition submits the consumed messages of a partition to the pool, until the
+// end offset is reached, and queues their pending records in the order they were consumed.
+func (ka *SaramaKafkaAdmin) submitPartition(
+	ctx context.Context,
+	rd ReadDetails,
+	partition int,
+	consumer sarama.PartitionConsumer,
+	readingOffsets readingOffsets,
+	pool *deserializationPool,
+	pending chan<- pendingRecord,
+	errChan chan<- error,
+) {
+	defer close(pending)
+
+	sendErr := func(err error) {
+		select {
+		case errChan <- err:
+		case <-ctx.Done():
+		}
+	}
+
+	// the consumer never delivers the end offset when transaction markers or aborted records come last,
+	// when it is idle it is checked whether committed records are left
+	var idle <-chan time.Time
+	checkIdle := rd.IsolationLevel == ReadCommitted && rd.StartPoint != Live
+	if checkIdle {
+		idle = time.After(ka.config.Consumer.MaxWaitTime)
+	}
+	next := readingOffsets.start
+
+	msgChan := consumer.Messages()
+	for {
+		select {
+		case err := <-consumer.Errors():
+			sendErr(err)
+			return
+		case <-ctx.Done():
+			return
+		case <-idle:
+			left, err := ka.committedRecordsLeft(ctx, rd.TopicName, int32(partition), next, readingOffsets.end)
+			if err != nil {
+				sendErr(err)
+				return
+			}
+			if !left {
+				return
+			}
+			idle = time.After(ka.config.Consumer.MaxWaitTime)
+		case msg := <-msgChan:
+			next = msg.Offset + 1
+			if checkIdle {
+				idle = time.After(ka.config.Consumer.MaxWaitTime)
+			}
+			result, ok := pool.submit(ctx, msg)
+			if !ok {
+				return
+			}
+
+			select {
+			case pending <- result:
+			case <-ctx.Done():
+				return
+			}
+
+			if msg.Offset == readingOffsets.end && rd.StartPoint != Live {
+				return
+			}
+		}
+	}
+}
+
+// toConsumerRecord deserializes the key and value of the consumed message.
+func (ka *SaramaKafkaAdmin) toConsumerRecord(rd ReadDetails, msg *sarama.ConsumerMessage) ConsumerRecord {
+	var headers []Header
+	for _, h := range msg.Headers {
+		headers = append(headers, Header{
+			string(h.Key),
+			HeaderValue{h.Value},
+		})
+	}
+
+	keyData, keyErr := ka.deserializeKey(msg.Topic, rd.KeyFormat, msg.Key)
+	var (
+		desData serdes.DesData
+		err     error
+	)
+	// there is nothing to deserialize in a tombstone
+	if msg.Value != nil {
+		desData, err = ka.deserialize(msg.Topic, rd.ValueFormat, msg.Value)
+	}
+	if keyErr != nil {
+		err = errors.Join(fmt.Errorf("key: %w", keyErr), err)
+	}
+
+	return ConsumerRecord{
+		Key:            keyData.Value,
+		KeySchema:      keyData.Schema,
+		KeySchemaId:    keyData.SchemaId,
+		Payload:        desData,
+		RawKey:         msg.Key,
+		RawValue:       msg.Value,
+		Err:            err,
+		Partition:      int64(msg.Partition),
+		Offset:         msg.Offset,
+		Headers:        headers,
+		Timestamp:      msg.Timestamp,
+		BlockTimestamp: msg.BlockTimestamp,
+		Tombstone:      msg.Value == nil,
+	}
+}
+
+// topicTimestampType determines the timestamp type from the message.timestamp.type config of the topic,
+// as the consumed messages do not tell.
+func (ka *SaramaKafkaAdmin) topicTimestampType(topic string) TimestampType {
+	entries, err := ka.admin.DescribeConfig(sarama.ConfigResource{
+		Type:        TopicResourceType,
+		Name:        topic,
+		ConfigNames: []string{"message.timestamp.type"},
+	})
+	if err != nil {
+		log.Error("Unable to determine the timestamp type", "topic", topic, "err", err)
+		return UnknownTimestampType
+	}
+	for _, entry := range entries {
+		if entry.Name != "message.timestamp.type" {
+			continue
+		}
+		switch entry.Value {
+		case "CreateTime":
+			return CreateTime
+		case "LogAppendTime":
+			return LogAppendTime
+		}
+	}
+	return UnknownTimestampType
+}
+
 func (ka *SaramaKafkaAdmin) matchesFilter(key, value string, filterDetails *Filter) bool {
 	if filterDetails == nil {
 		return true
@@ -298,11 +600,61 @@ func (ka *SaramaKafkaAdmin) matchesFilter(key, value string, filterDetails *Filt
 	return true
 }
 
-func (ka *SaramaKafkaAdmin) deserialize(
-	msg *sarama.ConsumerMessage,
-) (serdes.DesData, error) {
-	deserializer := serdes.NewAvroDeserializer(ka.sra)
-	return deserializer.Deserialize(msg.Value)
+func (ka *SaramaKafkaAdmin) deserialize(topic string, format serdes.Format, data []byte) (serdes.DesData, error) {
+	return ka.deserializeWith(topic, false, format, data)
+}
+
+// deserializeKey deserializes the key using a local schema or the Schema Registry when it is schema based.
+// Auto-detected keys that cannot be deserialized, for example plain numeric keys that look like they
+// are prefixed with a schema id, fall back to their string representation. Keys of an explicitly
+// chosen format that cannot be deserialized are shown as a string as well, but with the error.
+func (ka *SaramaKafkaAdmin) deserializeKey(topic string, format serdes.Format, key []byte) (serdes.DesData, error) {
+	if key == nil {
+		return serdes.DesData{}, nil
+	}
+	keyData, err := ka.deserializeWith(topic, true, format, key)
+	if err != nil {
+		if format != serdes.AutoFormat {
+			return serdes.DesData{Value: string(key)}, err
+		}
+		log.Debug("unable to deserialize key, using its string representation", "err", err)
+		return serdes.DesData{Value: string(key)}, nil
+	}
+	return keyData, nil
+}
+
+// deserializeWith deserializes the data using the explicitly chosen format. Otherwise using the local
+// schema the topic is mapped to, falling back to the Schema Registry when the topic is not mapped.
+func (ka *SaramaKafkaAdmin) deserializeWith(topic string, key bool, format serdes.Format, data []byte) (serdes.DesData, error) {
+	if format != serdes.AutoFormat {
+		return ka.deserializer(format).Deserialize(data)
+	}
+	if ka.localSchemas != nil {
+		if deserializer, mapped, err := ka.localSchemas.Deserializer(topic, key); mapped {
+			if err != nil {
+				return serdes.DesData{}, err
+			}
+			return deserializer.Deserialize(data)
+		}
+	}
+	return ka.deserializer(serdes.AutoFormat).Deserialize(data)
+}
+
+// deserializer returns the Deserializer of the format, shared by all reads
+// so schemas and codecs are cached across records.
+func (ka *SaramaKafkaAdmin) deserializer(format serdes.Format) serdes.Deserializer {
+	ka.deserializersMu.Lock()
+	defer ka.deserializersMu.Unlock()
+
+	if ka.deserializers == nil {
+		ka.deserializers = make(map[serdes.Format]serdes.Deserializer)
+	}
+	deserializer, ok := ka.deserializers[format]
+	if !ok {
+		deserializer = serdes.NewFormatDeserializer(format, ka.sra)
+		ka.deserializers[format] = deserializer
+	}
+	return deserializer
 }
 
 type readingOffsets struct {
@@ -315,6 +667,10 @@ func (ka *SaramaKafkaAdmin) determineReadingOffsets(
 	offsets offsets,
 ) readingOffsets {
 
+	if rd.ConsumerGroup != "" && offsets.committed >= 0 {
+		return ka.determineCommittedOffsets(rd, offsets)
+	}
+
 	if rd.StartPoint == Live {
 		return readingOffsets{
 			start: offsets.firstAvailable,
@@ -346,6 +702,27 @@ func (ka *SaramaKafkaAdmin) determineReadingOffsets(
 	}
 }
 
+// determineCommittedOffsets continues reading where the consumer group left off,
+// or at the oldest offset when the committed offset is no longer available.
+func (ka *SaramaKafkaAdmin) determineCommittedOffsets(
+	rd ReadDetails,
+	offsets offsets,
+) readingOffsets {
+	startOffset := max(offsets.committed, offsets.oldest)
+	if rd.StartPoint == Live {
+		return readingOffsets{
+			start: startOffset,
+			end:   -1,
+		}
+	}
+
+	numberOfRecordsPerPart := int64(float64(int64(rd.Limit)) / float64(len(rd.PartitionToRead)))
+	return readingOffsets{
+		start: startOffset,
+		end:   min(startOffset+numberOfRecordsPerPart-1, offsets.newest()),
+	}
+}
+
 func (ka *SaramaKafkaAdmin) determineMostRecentOffsets(
 	startOffset int64,
 	offsets offsets,
@@ -375,7 +752,18 @@ func (ka *SaramaKafkaAdmin) determineOffsetsFromBeginning(
 	return startOffset, endOffset
 }
 
+// readCommittedClient creates a client of its own to read committed records only, the isolation level
+// is part of the config of a client.
+func (ka *SaramaKafkaAdmin) readCommittedClient() (sarama.Client, error) {
+	cfg := *ka.config
+	cfg.Consumer.IsolationLevel = sarama.ReadCommitted
+	return sarama.NewClient(ka.addrs, &cfg)
+}
+
+// fetchOffsets fetches the offsets of the partitions using the client, the newest offsets of a read committed
+// client are the last stable offsets.
 func (ka *SaramaKafkaAdmin) fetchOffsets(
+	client sarama.Client,
 	partitions []int,
 	topicName string,
 ) (map[int]offsets, error) {
@@ -392,7 +780,7 @@ func (ka *SaramaKafkaAdmin) fetchOffsets(
 		go func(partition int) {
 			defer wg.Done()
 
-			firstAvailableOffset, err := ka.client.GetOffset(
+			firstAvailableOffset, err := client.GetOffset(
 				topicName,
 				int32(partition),
 				sarama.OffsetNewest,
@@ -402,7 +790,7 @@ func (ka *SaramaKafkaAdmin) fetchOffsets(
 				return
 			}
 
-			oldestOffset, err := ka.client.GetOffset(
+			oldestOffset, err := client.GetOffset(
 				topicName,
 				int32(partition),
 				sarama.OffsetOldest,
@@ -414,8 +802,8 @@ func (ka *SaramaKafkaAdmin) fetchOffsets(
 
 			mu.Lock()
 			offsetsByPartition[partition] = offsets{
-				oldestOffset,
-				firstAvailableOffset,
+				oldest:         oldestOffset,
+				firstAvailable: firstAvailableOffset,
 			}
 			mu.Unlock()
 		}(partition)
@@ -2,9 +2,15 @@ package kadmin
 
 import (
 	"context"
+	"github.com/IBM/sarama"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/linkedin/goavro/v2"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/exp/slices"
+	"ktea/serdes"
+	"ktea/sradmin"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 )
@@ -389,6 +395,330 @@ func TestReadRecords(t *testing.T) {
 		ka.DeleteTopic(topic)
 	})
 
+	t.Run("Read as consumer group", func(t *testing.T) {
+		topic := topicName()
+		group := "ktea-" + topic
+		// given
+		msg := ka.CreateTopic(TopicCreationDetails{
+			Name:              topic,
+			NumPartitions:     1,
+			ReplicationFactor: 1,
+		}).(TopicCreationStartedMsg)
+
+		switch msg.AwaitCompletion().(type) {
+		case TopicCreatedMsg:
+		case TopicCreationErrMsg:
+			t.Fatal("Unable to create topic", msg.Err)
+		}
+
+		for i := 0; i < 10; i++ {
+			psm := ka.PublishRecord(&ProducerRecord{
+				Topic: topic,
+				Key:   strconv.Itoa(i),
+				Value: []byte("{\"id\":\"123\"}"),
+			})
+
+			select {
+			case err := <-psm.Err:
+				t.Fatal("Unable to publish", err)
+			case p := <-psm.Published:
+				assert.True(t, p)
+			}
+		}
+
+		committedOffset := func(group string) int64 {
+			response, err := ka.(*SaramaKafkaAdmin).admin.ListConsumerGroupOffsets(group, map[string][]int32{topic: {0}})
+			if err != nil {
+				return -1
+			}
+			if block := response.GetBlock(topic, 0); block != nil {
+				return block.Offset
+			}
+			return -1
+		}
+
+		readKeys := func(rd ReadDetails) []int {
+			readCtx, stopReading := context.WithCancel(context.Background())
+			rsm := ka.ReadRecords(readCtx, rd).(ReadingStartedMsg)
+			last := int64(-1)
+			defer func() {
+				// the marked offsets are committed once the group is left
+				stopReading()
+				if rd.CommitOffsets && last >= 0 {
+					assert.Eventually(t, func() bool {
+						return committedOffset(rd.ConsumerGroup) == last+1
+					}, 10*time.Second, 100*time.Millisecond)
+				}
+			}()
+			var keys []int
+			for {
+				select {
+				case r, ok := <-rsm.ConsumerRecord:
+					if !ok {
+						return keys
+					}
+					// as the consumption page does once the record is displayed
+					rsm.MarkRead(r)
+					last = r.Offset
+					key, _ := strconv.Atoi(r.Key)
+					keys = append(keys, key)
+				case <-rsm.EmptyTopic:
+					return keys
+				case <-time.After(10 * time.Second):
+					t.Fatal("timed out reading records")
+				}
+			}
+		}
+
+		t.Run("without a committed offset from the start point and commit", func(t *testing.T) {
+			keys := readKeys(ReadDetails{
+				TopicName:       topic,
+				PartitionToRead: []int{0},
+				StartPoint:      Beginning,
+				Limit:           4,
+				ConsumerGroup:   group,
+				CommitOffsets:   true,
+			})
+
+			assert.Equal(t, []int{0, 1, 2, 3}, keys)
+		})
+
+		t.Run("from the committed offset without committing", func(t *testing.T) {
+			rd := ReadDetails{
+				TopicName:       topic,
+				PartitionToRead: []int{0},
+				StartPoint:      Beginning,
+				Limit:           3,
+				ConsumerGroup:   group,
+			}
+
+			assert.Equal(t, []int{4, 5, 6}, readKeys(rd))
+			assert.Equal(t, []int{4, 5, 6}, readKeys(rd))
+		})
+
+		t.Run("commit the offsets of filtered records as well", func(t *testing.T) {
+			keys := readKeys(ReadDetails{
+				TopicName:       topic,
+				PartitionToRead: []int{0},
+				StartPoint:      Beginning,
+				Limit:           1,
+				ConsumerGroup:   group,
+				CommitOffsets:   true,
+				Filter:          &Filter{KeyFilter: StartsWithFilterType, KeySearchTerm: "6"},
+			})
+			assert.Equal(t, []int{6}, keys)
+
+			assert.Equal(t, []int{7, 8}, readKeys(ReadDetails{
+				TopicName:       topic,
+				PartitionToRead: []int{0},
+				StartPoint:      Beginning,
+				Limit:           2,
+				ConsumerGroup:   group,
+			}))
+		})
+
+		t.Run("report that the group can not be joined while it has active members", func(t *testing.T) {
+			activeGroup := group + "-active"
+			config := sarama.NewConfig()
+			config.Consumer.Offsets.Initial = sarama.OffsetOldest
+			consumerGroup, err := sarama.NewConsumerGroup(brokers, activeGroup, config)
+			if err != nil {
+				t.Fatal("Unable to create consumer group", err)
+			}
+			joined := make(chan struct{})
+			groupCtx, leave := context.WithCancel(context.Background())
+			go consumerGroup.Consume(groupCtx, []string{topic}, &idleGroupHandler{joined: joined})
+			defer func() {
+				leave()
+				consumerGroup.Close()
+			}()
+			select {
+			case <-joined:
+			case <-time.After(30 * time.Second):
+				t.Fatal("timed out joining the consumer group")
+			}
+
+			readCtx, stopReading := context.WithCancel(context.Background())
+			defer stopReading()
+			rsm := ka.ReadRecords(readCtx, ReadDetails{
+				TopicName:       topic,
+				PartitionToRead: []int{0},
+				StartPoint:      Beginning,
+				Limit:           2,
+				ConsumerGroup:   activeGroup,
+				CommitOffsets:   true,
+			}).(ReadingStartedMsg)
+
+			var commitErr OffsetCommitError
+			for commitErr.Err == nil {
+				select {
+				case <-rsm.ConsumerRecord:
+				case err := <-rsm.Err:
+					assert.ErrorAs(t, err, &commitErr)
+				case <-time.After(10 * time.Second):
+					t.Fatal("timed out waiting for joining the group to fail")
+				}
+			}
+			assert.Equal(t, activeGroup, commitErr.Group)
+			assert.ErrorIs(t, commitErr, sarama.ErrInconsistentGroupProtocol)
+		})
+
+		// clean up
+		ka.DeleteTopic(topic)
+	})
+
+	t.Run("Read transactional records", func(t *testing.T) {
+		topic := topicName()
+		// given
+		msg := ka.CreateTopic(TopicCreationDetails{
+			Name:              topic,
+			NumPartitions:     1,
+			ReplicationFactor: 1,
+		}).(TopicCreationStartedMsg)
+
+		switch msg.AwaitCompletion().(type) {
+		case TopicCreatedMsg:
+		case TopicCreationErrMsg:
+			t.Fatal("Unable to create topic", msg.Err)
+		}
+
+		transactionalProducer := func(id string) sarama.SyncProducer {
+			config := sarama.NewConfig()
+			config.Producer.Return.Successes = true
+			config.Producer.RequiredAcks = sarama.WaitForAll
+			config.Producer.Idempotent = true
+			config.Producer.Transaction.ID = id
+			config.Net.MaxOpenRequests = 1
+			producer, err := sarama.NewSyncProducer(brokers, config)
+			if err != nil {
+				t.Fatal("Unable to create transactional producer", err)
+			}
+			return producer
+		}
+		producer := transactionalProducer("ktea-" + topic)
+		defer producer.Close()
+		openProducer := transactionalProducer("ktea-open-" + topic)
+		defer openProducer.Close()
+
+		send := func(producer sarama.SyncProducer, keys ...string) {
+			assert.NoError(t, producer.BeginTxn())
+			for _, key := range keys {
+				_, _, err := producer.SendMessage(&sarama.ProducerMessage{
+					Topic: topic,
+					Key:   sarama.StringEncoder(key),
+					Value: sarama.StringEncoder("{}"),
+				})
+				assert.NoError(t, err)
+			}
+		}
+		produce := func(commit bool, keys ...string) {
+			send(producer, keys...)
+			if commit {
+				assert.NoError(t, producer.CommitTxn())
+			} else {
+				assert.NoError(t, producer.AbortTxn())
+			}
+		}
+		produce(true, "1", "2")
+		produce(false, "3")
+		produce(true, "4")
+		// transactions completed after an open transaction are beyond the last stable offset
+		send(openProducer, "5")
+		produce(false, "6")
+		produce(true, "7")
+		defer openProducer.AbortTxn()
+
+		read := func(rd ReadDetails) []ConsumerRecord {
+			rsm := ka.ReadRecords(context.Background(), rd).(ReadingStartedMsg)
+			var records []ConsumerRecord
+			for {
+				select {
+				case r, ok := <-rsm.ConsumerRecord:
+					if !ok {
+						return records
+					}
+					records = append(records, r)
+				case err := <-rsm.Err:
+					t.Fatal("Unable to read records", err)
+				case <-time.After(10 * time.Second):
+					t.Fatal("timed out reading records")
+				}
+			}
+		}
+
+		t.Run("skip aborted records when reading committed records", func(t *testing.T) {
+			records := read(ReadDetails{
+				TopicName:       topic,
+				PartitionToRead: []int{0},
+				StartPoint:      Beginning,
+				Limit:           50,
+				IsolationLevel:  ReadCommitted,
+			})
+
+			var keys []string
+			for _, r := range records {
+				keys = append(keys, r.Key)
+			}
+			assert.Equal(t, []string{"1", "2", "4"}, keys)
+		})
+
+		t.Run("show transaction markers and aborted records", func(t *testing.T) {
+			records := read(ReadDetails{
+				TopicName:        topic,
+				PartitionToRead:  []int{0},
+				StartPoint:       Beginning,
+				Limit:            50,
+				ShowTransactions: true,
+			})
+
+			var states []TransactionState
+			for _, r := range records {
+				states = append(states, r.Transaction)
+			}
+			assert.Equal(t, []TransactionState{
+				CommittedTransaction,
+				CommittedTransaction,
+				CommitMarker,
+				AbortedTransaction,
+				AbortMarker,
+				CommittedTransaction,
+				CommitMarker,
+				OpenTransaction,
+				AbortedTransaction,
+				AbortMarker,
+				CommittedTransaction,
+				CommitMarker,
+			}, states)
+		})
+
+		t.Run("show transaction markers up to the last stable offset when reading committed records", func(t *testing.T) {
+			records := read(ReadDetails{
+				TopicName:        topic,
+				PartitionToRead:  []int{0},
+				StartPoint:       Beginning,
+				Limit:            50,
+				IsolationLevel:   ReadCommitted,
+				ShowTransactions: true,
+			})
+
+			var states []TransactionState
+			for _, r := range records {
+				states = append(states, r.Transaction)
+			}
+			assert.Equal(t, []TransactionState{
+				CommittedTransaction,
+				CommittedTransaction,
+				CommitMarker,
+				AbortMarker,
+				CommittedTransaction,
+				CommitMarker,
+			}, states)
+		})
+
+		// clean up
+		ka.DeleteTopic(topic)
+	})
+
 	t.Run("Read filtered", func(t *testing.T) {
 		t.Run("with key filter", func(t *testing.T) {
 			t.Run("containing", func(t *testing.T) {
@@ -654,6 +984,86 @@ func TestDetermineStartingOffset(t *testing.T) {
 				end:   290,
 			},
 		},
+		{
+			name: "consumer group continues at the committed offset",
+			readDetails: ReadDetails{
+				TopicName:       "test-topic",
+				PartitionToRead: []int{0},
+				StartPoint:      MostRecent,
+				Limit:           50,
+				ConsumerGroup:   "stuck-group",
+			},
+			offsets: offsets{
+				oldest:         1,
+				firstAvailable: 291,
+				committed:      100,
+			},
+
+			want: want{
+				start: 100,
+				end:   149,
+			},
+		},
+		{
+			name: "consumer group with less records left than the limit",
+			readDetails: ReadDetails{
+				TopicName:       "test-topic",
+				PartitionToRead: []int{0},
+				StartPoint:      Beginning,
+				Limit:           50,
+				ConsumerGroup:   "stuck-group",
+			},
+			offsets: offsets{
+				oldest:         1,
+				firstAvailable: 291,
+				committed:      280,
+			},
+
+			want: want{
+				start: 280,
+				end:   290,
+			},
+		},
+		{
+			name: "consumer group with a committed offset that is no longer available",
+			readDetails: ReadDetails{
+				TopicName:       "test-topic",
+				PartitionToRead: []int{0},
+				StartPoint:      Beginning,
+				Limit:           50,
+				ConsumerGroup:   "stuck-group",
+			},
+			offsets: offsets{
+				oldest:         200,
+				firstAvailable: 291,
+				committed:      100,
+			},
+
+			want: want{
+				start: 200,
+				end:   249,
+			},
+		},
+		{
+			name: "consumer group without a committed offset reads from the start point",
+			readDetails: ReadDetails{
+				TopicName:       "test-topic",
+				PartitionToRead: []int{0},
+				StartPoint:      Beginning,
+				Limit:           50,
+				ConsumerGroup:   "new-group",
+			},
+			offsets: offsets{
+				oldest:         1,
+				firstAvailable: 291,
+				committed:      -1,
+			},
+
+			want: want{
+				start: 1,
+				end:   50,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -667,3 +1077,95 @@ func TestDetermineStartingOffset(t *testing.T) {
 		})
 	}
 }
+
+func TestDeserializeKey(t *testing.T) {
+	schema := `{"type": "record", "name": "Key", "fields": [{"name": "id", "type": "int"}]}`
+
+	t.Run("Schema based key", func(t *testing.T) {
+		sraMock := sradmin.NewMock()
+		sraMock.GetSchemaByIdFunc = func(id int) tea.Msg {
+			return sradmin.SchemaByIdReceived{Schema: sradmin.Schema{Value: schema}}
+		}
+		admin := &SaramaKafkaAdmin{sra: sraMock}
+
+		codec, _ := goavro.NewCodec(schema)
+		data, _ := codec.BinaryFromNative([]byte{0, 0, 0, 0, 1}, map[string]interface{}{"id": 7})
+
+		keyData, err := admin.deserializeKey("topic", serdes.AutoFormat, data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{"id":7}`, keyData.Value)
+		assert.Equal(t, schema, keyData.Schema)
+	})
+
+	t.Run("Plain key", func(t *testing.T) {
+		admin := &SaramaKafkaAdmin{sra: sradmin.NewMock()}
+
+		keyData, err := admin.deserializeKey("topic", serdes.AutoFormat, []byte("key-1"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, serdes.DesData{Value: "key-1"}, keyData)
+	})
+
+	t.Run("Fall back to string when deserialization fails", func(t *testing.T) {
+		admin := &SaramaKafkaAdmin{}
+		key := []byte{0, 0, 0, 0, 0, 0, 0, 5}
+
+		keyData, err := admin.deserializeKey("topic", serdes.AutoFormat, key)
+
+		assert.NoError(t, err)
+		assert.Equal(t, serdes.DesData{Value: string(key)}, keyData)
+	})
+
+	t.Run("Explicit key format", func(t *testing.T) {
+		admin := &SaramaKafkaAdmin{sra: sradmin.NewMock()}
+
+		keyData, err := admin.deserializeKey("topic", serdes.Int64Format, []byte{0, 0, 0, 0, 0, 0, 0, 5})
+
+		assert.NoError(t, err)
+		assert.Equal(t, serdes.DesData{Value: "5"}, keyData)
+	})
+
+	t.Run("Report keys not matching the explicit key format", func(t *testing.T) {
+		admin := &SaramaKafkaAdmin{sra: sradmin.NewMock()}
+
+		record := admin.toConsumerRecord(ReadDetails{KeyFormat: serdes.Int64Format}, &sarama.ConsumerMessage{
+			Topic: "topic",
+			Key:   []byte("key-1"),
+			Value: []byte("value"),
+		})
+
+		assert.Equal(t, "key-1", record.Key)
+		assert.ErrorContains(t, record.Err, "key: ")
+		assert.Equal(t, "value", record.Payload.Value)
+	})
+
+	t.Run("Null keys have nothing to deserialize", func(t *testing.T) {
+		admin := &SaramaKafkaAdmin{sra: sradmin.NewMock()}
+
+		keyData, err := admin.deserializeKey("topic", serdes.Int64Format, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, serdes.DesData{}, keyData)
+	})
+}
+
+// idleGroupHandler keeps a member in the group without consuming anything.
+type idleGroupHandler struct {
+	joined chan struct{}
+	once   sync.Once
+}
+
+func (h *idleGroupHandler) Setup(sarama.ConsumerGroupSession) error {
+	h.once.Do(func() { close(h.joined) })
+	return nil
+}
+
+func (h *idleGroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *idleGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, _ sarama.ConsumerGroupClaim) error {
+	<-session.Context().Done()
+	return nil
+}
@@ -0,0 +1,186 @@
+package kadmin
+
+import (
+	"context"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"ktea/config"
+	"strconv"
+)
+
+// Provenance headers added to replayed records when requested.
+const (
+	SourceTopicHeader     = "ktea.source.topic"
+	SourcePartitionHeader = "ktea.source.partition"
+	SourceOffsetHeader    = "ktea.source.offset"
+)
+
+type RecordReplayer interface {
+	ReplayRecords(ctx context.Context, rd ReplayDetails) tea.Msg
+}
+
+type ReplayDetails struct {
+	// Records are the records to replay, when nil
+	// the records are read from the topic using ReadDetails.
+	Records     []ConsumerRecord
+	ReadDetails *ReadDetails
+	SourceTopic string
+	TargetTopic string
+	// TargetCluster is the cluster to publish to, when nil
+	// the records are published to the current cluster.
+	TargetCluster *config.Cluster
+	// PreservePartitions publishes every record to the partition it was read from
+	// instead of using the key based partitioner.
+	PreservePartitions bool
+	// AddProvenanceHeaders adds the source topic, partition and offset as headers,
+	// replacing the provenance headers the record already has.
+	AddProvenanceHeaders bool
+}
+
+type ReplayStartedMsg struct {
+	Replayed chan int
+	Err      chan error
+	topic    string
+}
+
+type RecordsReplayedMsg struct {
+	Count int
+	Topic string
+}
+
+type RecordsReplayErrMsg struct {
+	Err error
+}
+
+func (msg *ReplayStartedMsg) AwaitCompletion() tea.Msg {
+	select {
+	case count := <-msg.Replayed:
+		return RecordsReplayedMsg{Count: count, Topic: msg.topic}
+	case err := <-msg.Err:
+		return RecordsReplayErrMsg{Err: err}
+	}
+}
+
+// replayWriter is a RecordWriter that publishes records, one at a time to retain their order,
+// to the target topic.
+type replayWriter struct {
+	publisher Publisher
+	rd        ReplayDetails
+	count     int
+}
+
+// Write publishes the headers as they are, in their order and with repeated keys.
+func (w *replayWriter) Write(record ConsumerRecord) error {
+	headers := make([]Header, 0, len(record.Headers)+3)
+	for _, header := range record.Headers {
+		if w.rd.AddProvenanceHeaders && isProvenanceHeader(header.Key) {
+			continue
+		}
+		headers = append(headers, header)
+	}
+
+	if w.rd.AddProvenanceHeaders {
+		headers = append(headers,
+			Header{SourceTopicHeader, NewHeaderValue(w.rd.SourceTopic)},
+			Header{SourcePartitionHeader, NewHeaderValue(strconv.FormatInt(record.Partition, 10))},
+			Header{SourceOffsetHeader, NewHeaderValue(strconv.FormatInt(record.Offset, 10))},
+		)
+	}
+
+	producerRecord := &ProducerRecord{
+		Key:            string(record.RawKey),
+		NullKey:        record.RawKey == nil,
+		Value:          record.RawValue,
+		Topic:          w.rd.TargetTopic,
+		OrderedHeaders: headers,
+	}
+	if w.rd.PreservePartitions {
+		partition := int(record.Partition)
+		producerRecord.Partition = &partition
+	}
+
+	publication := w.publisher.PublishRecord(producerRecord)
+	if failed, ok := publication.AwaitCompletion().(PublicationFailed); ok {
+		return fmt.Errorf(
+			"replaying offset %d of partition %d failed after %d records: %w",
+			record.Offset,
+			record.Partition,
+			w.count,
+			failed.Err,
+		)
+	}
+
+	w.count++
+	return nil
+}
+
+func isProvenanceHeader(key string) bool {
+	return key == SourceTopicHeader || key == SourcePartitionHeader || key == SourceOffsetHeader
+}
+
+func (w *replayWriter) Close() error {
+	return nil
+}
+
+func (ka *SaramaKafkaAdmin) ReplayRecords(ctx context.Context, rd ReplayDetails) tea.Msg {
+	// buffered, so the replay finishes when the page no longer awaits it
+	replayedChan := make(chan int, 1)
+	errChan := make(chan error, 1)
+
+	go ka.doReplayRecords(ctx, rd, replayedChan, errChan)
+
+	return ReplayStartedMsg{
+		Replayed: replayedChan,
+		Err:      errChan,
+		topic:    rd.TargetTopic,
+	}
+}
+
+func (ka *SaramaKafkaAdmin) doReplayRecords(
+	ctx context.Context,
+	rd ReplayDetails,
+	replayedChan chan int,
+	errChan chan error,
+) {
+	MaybeIntroduceLatency()
+
+	var publisher Publisher = ka
+	if rd.TargetCluster != nil {
+		target, err := NewSaramaKadmin(ToConnectionDetails(rd.TargetCluster))
+		if err != nil {
+			errChan <- fmt.Errorf("unable to connect to %s: %w", rd.TargetCluster.Name, err)
+			return
+		}
+		defer target.(*SaramaKafkaAdmin).close()
+		publisher = target
+	}
+
+	var (
+		count int
+		err   error
+	)
+	if rd.Records != nil || rd.ReadDetails == nil {
+		count, err = replayRecords(rd.Records, publisher, rd)
+	} else if rd.ReadDetails.Unbounded() {
+		err = ErrUnboundedRead
+	} else {
+		msg := ka.ReadRecords(ctx, *rd.ReadDetails)
+		if rsm, ok := msg.(ReadingStartedMsg); ok {
+			count, err = StreamRecords(rsm, &replayWriter{publisher: publisher, rd: rd})
+		} else {
+			err = fmt.Errorf("unable to read records: unexpected %T", msg)
+		}
+	}
+	if err != nil {
+		errChan <- err
+		return
+	}
+
+	replayedChan <- count
+}
+
+func replayRecords(records []ConsumerRecord, publisher Publisher, rd ReplayDetails) (int, error) {
+	rw := &replayWriter{publisher: publisher, rd: rd}
+	err := WriteRecords(records, rw)
+	return rw.count, err
+}
@@ -0,0 +1,131 @@
+package kadmin
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"ktea/serdes"
+	"testing"
+)
+
+func TestReplayRecords(t *testing.T) {
+	records := []ConsumerRecord{
+		{
+			Key:       "1",
+			RawKey:    []byte("1"),
+			Payload:   serdes.DesData{Value: `{"id":1}`},
+			RawValue:  []byte{0, 0, 0, 0, 1, 2},
+			Partition: 2,
+			Offset:    10,
+			Headers: []Header{
+				{"h1", NewHeaderValue("v1")},
+				{"h1", NewBinaryHeaderValue([]byte{0, 1})},
+				{SourceTopicHeader, NewHeaderValue("orders")},
+			},
+		},
+		{
+			Key:       "fail",
+			RawKey:    []byte("fail"),
+			RawValue:  []byte("b"),
+			Partition: 0,
+			Offset:    11,
+		},
+		{
+			Key:       "3",
+			RawKey:    []byte("3"),
+			RawValue:  []byte("c"),
+			Partition: 1,
+			Offset:    12,
+		},
+	}
+
+	t.Run("Preserve key, raw value and headers in order with repeated keys", func(t *testing.T) {
+		publisher := &capturingPublisher{}
+
+		count, err := replayRecords(records[:1], publisher, ReplayDetails{
+			SourceTopic: "dlq",
+			TargetTopic: "orders",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, &ProducerRecord{
+			Key:   "1",
+			Value: []byte{0, 0, 0, 0, 1, 2},
+			Topic: "orders",
+			OrderedHeaders: []Header{
+				{"h1", NewHeaderValue("v1")},
+				{"h1", NewBinaryHeaderValue([]byte{0, 1})},
+				{SourceTopicHeader, NewHeaderValue("orders")},
+			},
+		}, publisher.records[0])
+	})
+
+	t.Run("Add provenance headers, replacing the existing ones", func(t *testing.T) {
+		publisher := &capturingPublisher{}
+
+		_, err := replayRecords(records[:1], publisher, ReplayDetails{
+			SourceTopic:          "dlq",
+			TargetTopic:          "orders",
+			AddProvenanceHeaders: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Header{
+			{"h1", NewHeaderValue("v1")},
+			{"h1", NewBinaryHeaderValue([]byte{0, 1})},
+			{SourceTopicHeader, NewHeaderValue("dlq")},
+			{SourcePartitionHeader, NewHeaderValue("2")},
+			{SourceOffsetHeader, NewHeaderValue("10")},
+		}, publisher.records[0].OrderedHeaders)
+	})
+
+	t.Run("Preserve partitions", func(t *testing.T) {
+		publisher := &capturingPublisher{}
+
+		_, err := replayRecords(records[2:], publisher, ReplayDetails{
+			TargetTopic:        "orders",
+			PreservePartitions: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, *publisher.records[0].Partition)
+	})
+
+	t.Run("Preserve null keys", func(t *testing.T) {
+		publisher := &capturingPublisher{failKey: "fail"}
+
+		_, err := replayRecords([]ConsumerRecord{{RawValue: []byte("d")}}, publisher, ReplayDetails{
+			TargetTopic: "orders",
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, publisher.records[0].NullKey)
+	})
+
+	t.Run("Stop at the first failure", func(t *testing.T) {
+		publisher := &capturingPublisher{failKey: "fail"}
+
+		count, err := replayRecords(records, publisher, ReplayDetails{
+			TargetTopic: "orders",
+		})
+
+		assert.Equal(t, 1, count)
+		assert.EqualError(t, err, "replaying offset 11 of partition 0 failed after 1 records: broker unavailable")
+		assert.Len(t, publisher.records, 1)
+	})
+
+	t.Run("Refuse reading a live consumption", func(t *testing.T) {
+		rsm := (&SaramaKafkaAdmin{}).ReplayRecords(context.Background(), ReplayDetails{
+			SourceTopic: "dlq",
+			TargetTopic: "orders",
+			ReadDetails: &ReadDetails{
+				TopicName:       "dlq",
+				PartitionToRead: []int{0},
+				StartPoint:      Live,
+			},
+		}).(ReplayStartedMsg)
+
+		replayErr := rsm.AwaitCompletion().(RecordsReplayErrMsg)
+		assert.ErrorIs(t, replayErr.Err, ErrUnboundedRead)
+	})
+}
@@ -6,7 +6,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
 	"ktea/config"
+	"ktea/serdes"
 	"ktea/sradmin"
+	"sync"
 	"time"
 )
 
@@ -17,6 +19,13 @@ type SaramaKafkaAdmin struct {
 	config   *sarama.Config
 	producer sarama.SyncProducer
 	sra      sradmin.SrAdmin
+	// localSchemas decode the records of topics mapped to local schema files.
+	localSchemas    *serdes.LocalSchemas
+	deserializersMu sync.Mutex
+	deserializers   map[serdes.Format]serdes.Deserializer
+	// producers publish with other than the default ProducerSettings.
+	producersMu sync.Mutex
+	producers   map[ProducerSettings]sarama.SyncProducer
 }
 
 type ConnCheckStartedMsg struct {
@@ -63,6 +72,7 @@ func ToConnectionDetails(cluster *config.Cluster) ConnectionDetails {
 		BootstrapServers: cluster.BootstrapServers,
 		SASLConfig:       saslConfig,
 		SSLEnabled:       cluster.SSLEnabled,
+		LocalSchemas:     cluster.LocalSchemas,
 	}
 	return connDetails
 }
@@ -71,7 +81,7 @@ func NewSaramaKadmin(cd ConnectionDetails) (Kadmin, error) {
 	cfg := sarama.NewConfig()
 	cfg.Producer.Return.Successes = true
 	cfg.Producer.RequiredAcks = sarama.WaitForAll
-	cfg.Producer.Partitioner = sarama.NewRoundRobinPartitioner
+	cfg.Producer.Partitioner = newRecordPartitioner
 	cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
 
 	cfg.Net.TLS.Enable = cd.SSLEnabled
@@ -99,14 +109,34 @@ func NewSaramaKadmin(cd ConnectionDetails) (Kadmin, error) {
 	}
 
 	return &SaramaKafkaAdmin{
-		client:   client,
-		admin:    admin,
-		addrs:    cd.BootstrapServers,
-		producer: producer,
-		config:   cfg,
+		client:       client,
+		admin:        admin,
+		addrs:        cd.BootstrapServers,
+		producer:     producer,
+		config:       cfg,
+		localSchemas: serdes.NewLocalSchemas(cd.LocalSchemas),
 	}, nil
 }
 
+// close releases the connections of a SaramaKafkaAdmin
+// that was only created for a single operation.
+func (ka *SaramaKafkaAdmin) close() {
+	if err := ka.producer.Close(); err != nil {
+		log.Error("Unable to close producer", err)
+	}
+	for _, producer := range ka.producers {
+		if err := producer.Close(); err != nil {
+			log.Error("Unable to close producer", err)
+		}
+	}
+	if err := ka.admin.Close(); err != nil {
+		log.Error("Unable to close cluster admin", err)
+	}
+	if err := ka.client.Close(); err != nil && err != sarama.ErrClosedClient {
+		log.Error("Unable to close client", err)
+	}
+}
+
 func CheckKafkaConnectivity(cluster *config.Cluster) tea.Msg {
 	connectedChan := make(chan bool)
 	errChan := make(chan error)
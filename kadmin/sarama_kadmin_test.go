@@ -30,6 +30,9 @@ func TestToConnectionDetails(t *testing.T) {
 					},
 					SSLEnabled:     true,
 					SchemaRegistry: nil,
+					LocalSchemas: []config.LocalSchema{
+						{Topic: "orders", File: "orders.avsc"},
+					},
 				},
 			},
 			want: ConnectionDetails{
@@ -40,6 +43,9 @@ func TestToConnectionDetails(t *testing.T) {
 					Protocol: PlainText,
 				},
 				SSLEnabled: true,
+				LocalSchemas: []config.LocalSchema{
+					{Topic: "orders", File: "orders.avsc"},
+				},
 			},
 		},
 	}
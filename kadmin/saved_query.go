@@ -0,0 +1,81 @@
+package kadmin
+
+import (
+	"ktea/config"
+	"ktea/serdes"
+)
+
+var startPointNames = map[StartPoint]string{
+	Beginning:  "beginning",
+	MostRecent: "most-recent",
+	Live:       "live",
+}
+
+// NewConsumptionQuery names the read details so they can be saved and read again,
+// the partitions are omitted when all partitions of the topic are read.
+func NewConsumptionQuery(name string, rd ReadDetails, topic *ListedTopic) config.ConsumptionQuery {
+	query := config.ConsumptionQuery{
+		Name:             name,
+		Topic:            rd.TopicName,
+		StartPoint:       startPointNames[rd.StartPoint],
+		Limit:            rd.Limit,
+		KeyFormat:        string(rd.KeyFormat),
+		ValueFormat:      string(rd.ValueFormat),
+		ConsumerGroup:    rd.ConsumerGroup,
+		CommitOffsets:    rd.CommitOffsets,
+		ReadCommitted:    rd.IsolationLevel == ReadCommitted,
+		ShowTransactions: rd.ShowTransactions,
+	}
+	if len(rd.PartitionToRead) != topic.PartitionCount {
+		query.Partitions = rd.PartitionToRead
+	}
+	if rd.Filter != nil {
+		if rd.Filter.KeyFilter != NoFilterType && rd.Filter.KeyFilter != "" {
+			query.KeyFilter = string(rd.Filter.KeyFilter)
+			query.KeyFilterTerm = rd.Filter.KeySearchTerm
+		}
+		if rd.Filter.ValueFilter != NoFilterType && rd.Filter.ValueFilter != "" {
+			query.ValueFilter = string(rd.Filter.ValueFilter)
+			query.ValueFilterTerm = rd.Filter.ValueSearchTerm
+		}
+	}
+	return query
+}
+
+// NewReadDetails creates the read details of the saved query.
+func NewReadDetails(query config.ConsumptionQuery, topic *ListedTopic) ReadDetails {
+	rd := ReadDetails{
+		TopicName:        topic.Name,
+		PartitionToRead:  query.Partitions,
+		Limit:            query.Limit,
+		KeyFormat:        serdes.Format(query.KeyFormat),
+		ValueFormat:      serdes.Format(query.ValueFormat),
+		ConsumerGroup:    query.ConsumerGroup,
+		CommitOffsets:    query.CommitOffsets,
+		ShowTransactions: query.ShowTransactions,
+		Filter: &Filter{
+			KeyFilter:       NoFilterType,
+			KeySearchTerm:   query.KeyFilterTerm,
+			ValueFilter:     NoFilterType,
+			ValueSearchTerm: query.ValueFilterTerm,
+		},
+	}
+	if len(rd.PartitionToRead) == 0 {
+		rd.PartitionToRead = topic.Partitions()
+	}
+	for startPoint, name := range startPointNames {
+		if name == query.StartPoint {
+			rd.StartPoint = startPoint
+		}
+	}
+	if query.ReadCommitted {
+		rd.IsolationLevel = ReadCommitted
+	}
+	if query.KeyFilter != "" {
+		rd.Filter.KeyFilter = FilterType(query.KeyFilter)
+	}
+	if query.ValueFilter != "" {
+		rd.Filter.ValueFilter = FilterType(query.ValueFilter)
+	}
+	return rd
+}
@@ -0,0 +1,55 @@
+package kadmin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"ktea/config"
+	"ktea/serdes"
+	"testing"
+)
+
+func TestSavedQuery(t *testing.T) {
+	topic := &ListedTopic{Name: "orders", PartitionCount: 3}
+
+	t.Run("Read the details of a saved query", func(t *testing.T) {
+		rd := ReadDetails{
+			TopicName:       "orders",
+			PartitionToRead: []int{1},
+			StartPoint:      MostRecent,
+			Limit:           50,
+			Filter: &Filter{
+				KeyFilter:       NoFilterType,
+				ValueFilter:     ContainsFilterType,
+				ValueSearchTerm: "FAILED",
+			},
+			ValueFormat:    serdes.StringFormat,
+			ConsumerGroup:  "order-processor",
+			IsolationLevel: ReadCommitted,
+		}
+
+		query := NewConsumptionQuery("failed", rd, topic)
+
+		assert.Equal(t, config.ConsumptionQuery{
+			Name:            "failed",
+			Topic:           "orders",
+			Partitions:      []int{1},
+			StartPoint:      "most-recent",
+			Limit:           50,
+			ValueFilter:     "contains",
+			ValueFilterTerm: "FAILED",
+			ValueFormat:     "string",
+			ConsumerGroup:   "order-processor",
+			ReadCommitted:   true,
+		}, query)
+		assert.Equal(t, rd, NewReadDetails(query, topic))
+	})
+
+	t.Run("Omit the partitions when all partitions are read", func(t *testing.T) {
+		query := NewConsumptionQuery("all", ReadDetails{
+			TopicName:       "orders",
+			PartitionToRead: []int{0, 1, 2},
+		}, topic)
+
+		assert.Nil(t, query.Partitions)
+		assert.Equal(t, []int{0, 1, 2}, NewReadDetails(query, topic).PartitionToRead)
+	})
+}
@@ -9,5 +9,10 @@ type SraSetter interface {
 }
 
 func (ka *SaramaKafkaAdmin) SetSra(sra sradmin.SrAdmin) {
+	ka.deserializersMu.Lock()
+	defer ka.deserializersMu.Unlock()
+
 	ka.sra = sra
+	// the cached deserializers use the previous Schema Registry
+	ka.deserializers = nil
 }
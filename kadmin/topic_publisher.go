@@ -4,6 +4,8 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/burdiyan/kafkautil"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rcrowley/go-metrics"
+	"time"
 )
 
 type Publisher interface {
@@ -11,11 +13,86 @@ type Publisher interface {
 }
 
 type ProducerRecord struct {
-	Key       string
+	Key string
+	// NullKey publishes the record without a key, Key is ignored.
+	NullKey bool
+	// Value of the record, a nil Value publishes a tombstone.
 	Value     []byte
 	Topic     string
 	Partition *int
 	Headers   map[string]string
+	// OrderedHeaders are published after Headers, in their order and with repeated keys as read from a topic.
+	OrderedHeaders []Header
+	// Timestamp of the record, when zero the time of publication is used.
+	Timestamp time.Time
+	// Settings of the producer the record is published with.
+	Settings ProducerSettings
+}
+
+// Acks is the number of acknowledgements the leader has to receive before a record is published.
+type Acks int
+
+const (
+	// AcksAll waits for all in-sync replicas.
+	AcksAll Acks = iota
+	// AcksLeader waits for the leader only.
+	AcksLeader
+	// AcksNone does not wait for any acknowledgement.
+	AcksNone
+)
+
+func (a Acks) requiredAcks() sarama.RequiredAcks {
+	switch a {
+	case AcksLeader:
+		return sarama.WaitForLocal
+	case AcksNone:
+		return sarama.NoResponse
+	default:
+		return sarama.WaitForAll
+	}
+}
+
+type Compression int
+
+const (
+	CompressionNone Compression = iota
+	CompressionGzip
+	CompressionSnappy
+	CompressionLz4
+	CompressionZstd
+)
+
+func (c Compression) codec() sarama.CompressionCodec {
+	switch c {
+	case CompressionGzip:
+		return sarama.CompressionGZIP
+	case CompressionSnappy:
+		return sarama.CompressionSnappy
+	case CompressionLz4:
+		return sarama.CompressionLZ4
+	case CompressionZstd:
+		return sarama.CompressionZSTD
+	default:
+		return sarama.CompressionNone
+	}
+}
+
+// ProducerSettings of a single publication, the zero value publishes with the shared producer:
+// all acks, no compression and not idempotent.
+type ProducerSettings struct {
+	Acks        Acks
+	Compression Compression
+	// Idempotent requires AcksAll.
+	Idempotent bool
+}
+
+func (s ProducerSettings) apply(cfg *sarama.Config) {
+	cfg.Producer.RequiredAcks = s.Acks.requiredAcks()
+	cfg.Producer.Compression = s.Compression.codec()
+	cfg.Producer.Idempotent = s.Idempotent
+	if s.Idempotent {
+		cfg.Net.MaxOpenRequests = 1
+	}
 }
 
 type PublicationStartedMsg struct {
@@ -39,6 +116,17 @@ func (p *PublicationStartedMsg) AwaitCompletion() tea.Msg {
 	}
 }
 
+// JVMPartition returns the partition the default partitioner of the JVM clients assigns the key to,
+// -1 for a null key as those are not assigned by key.
+func JVMPartition(key []byte, partitionCount int) int {
+	if key == nil || partitionCount <= 0 {
+		return -1
+	}
+	hasher := kafkautil.MurmurHasher()
+	hasher.Write(key)
+	return int(hasher.Sum32() % uint32(partitionCount))
+}
+
 func (ka *SaramaKafkaAdmin) PublishRecord(p *ProducerRecord) PublicationStartedMsg {
 	errChan := make(chan error)
 	published := make(chan bool)
@@ -57,12 +145,10 @@ func (ka *SaramaKafkaAdmin) doPublishRecord(
 	published chan bool,
 ) {
 	MaybeIntroduceLatency()
-	var partition int32
-	if p.Partition == nil {
-		ka.config.Producer.Partitioner = kafkautil.NewJVMCompatiblePartitioner
-	} else {
-		partition = int32(*p.Partition)
-		ka.config.Producer.Partitioner = sarama.NewManualPartitioner
+	producer, err := ka.producerFor(p.Settings)
+	if err != nil {
+		errChan <- err
+		return
 	}
 
 	var headers []sarama.RecordHeader
@@ -72,17 +158,90 @@ func (ka *SaramaKafkaAdmin) doPublishRecord(
 			Value: []byte(value),
 		})
 	}
+	for _, header := range p.OrderedHeaders {
+		headers = append(headers, sarama.RecordHeader{
+			Key:   []byte(header.Key),
+			Value: header.Value.data,
+		})
+	}
 
-	_, _, err := ka.producer.SendMessage(&sarama.ProducerMessage{
+	// a nil encoder is sent as a null value
+	var value sarama.Encoder
+	if p.Value != nil {
+		value = sarama.ByteEncoder(p.Value)
+	}
+
+	msg := &sarama.ProducerMessage{
 		Topic:     p.Topic,
-		Key:       sarama.StringEncoder(p.Key),
-		Value:     sarama.ByteEncoder(p.Value),
-		Partition: partition,
+		Value:     value,
 		Headers:   headers,
-	})
-	if err != nil {
+		Timestamp: p.Timestamp,
+	}
+	if !p.NullKey {
+		msg.Key = sarama.StringEncoder(p.Key)
+	}
+	if p.Partition != nil {
+		msg.Partition = int32(*p.Partition)
+		msg.Metadata = manualPartition{}
+	}
+
+	if _, _, err := producer.SendMessage(msg); err != nil {
 		errChan <- err
 		return
 	}
 	published <- true
 }
+
+// producerFor returns the shared producer for the default settings, other settings get a producer,
+// with its own connections, that is created on first use and kept until the admin is closed.
+func (ka *SaramaKafkaAdmin) producerFor(settings ProducerSettings) (sarama.SyncProducer, error) {
+	if settings == (ProducerSettings{}) {
+		return ka.producer, nil
+	}
+
+	ka.producersMu.Lock()
+	defer ka.producersMu.Unlock()
+	if producer, ok := ka.producers[settings]; ok {
+		return producer, nil
+	}
+
+	cfg := *ka.config
+	cfg.MetricRegistry = metrics.NewRegistry()
+	settings.apply(&cfg)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	producer, err := sarama.NewSyncProducer(ka.addrs, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	if ka.producers == nil {
+		ka.producers = make(map[ProducerSettings]sarama.SyncProducer)
+	}
+	ka.producers[settings] = producer
+	return producer, nil
+}
+
+// manualPartition marks messages that are published to the partition they were given.
+type manualPartition struct{}
+
+// recordPartitioner publishes records to the partition they were given, or else to the partition
+// the JVM clients assign their key to. It is configured once as the partitioner is created per topic.
+type recordPartitioner struct {
+	byKey sarama.Partitioner
+}
+
+func newRecordPartitioner(topic string) sarama.Partitioner {
+	return &recordPartitioner{byKey: kafkautil.NewJVMCompatiblePartitioner(topic)}
+}
+
+func (p *recordPartitioner) Partition(msg *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+	if _, ok := msg.Metadata.(manualPartition); ok {
+		return msg.Partition, nil
+	}
+	return p.byKey.Partition(msg, numPartitions)
+}
+
+func (p *recordPartitioner) RequiresConsistency() bool {
+	return true
+}
@@ -2,6 +2,7 @@ package kadmin
 
 import (
 	"context"
+	"github.com/IBM/sarama"
 	kgo "github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -165,4 +166,118 @@ func TestPublish(t *testing.T) {
 		// clean up
 		ka.DeleteTopic(topic)
 	})
+
+	t.Run("Publish a null key with producer settings", func(t *testing.T) {
+		topic := topicName()
+		// given
+		createTopic(t, []kgo.TopicConfig{
+			{
+				Topic:             topic,
+				NumPartitions:     1,
+				ReplicationFactor: 1,
+			},
+		})
+		timestamp := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+
+		// when
+		assert.EventuallyWithT(t, func(c *assert.CollectT) {
+			psm := ka.PublishRecord(&ProducerRecord{
+				Topic:     topic,
+				NullKey:   true,
+				Value:     []byte{0xde, 0xad, 0xbe, 0xef},
+				Timestamp: timestamp,
+				Settings: ProducerSettings{
+					Acks:        AcksAll,
+					Compression: CompressionZstd,
+					Idempotent:  true,
+				},
+			})
+
+			select {
+			case err := <-psm.Err:
+				t.Fatal(c, "Unable to publish", err)
+			case p := <-psm.Published:
+				assert.True(c, p)
+			}
+		}, 10*time.Second, 10*time.Millisecond)
+
+		// then
+		ctx, cancel := context.WithCancel(context.Background())
+		rsm := ka.ReadRecords(ctx, ReadDetails{
+			TopicName:       topic,
+			PartitionToRead: []int{0},
+			StartPoint:      Beginning,
+			Limit:           1,
+		}).(ReadingStartedMsg)
+		assert.EventuallyWithT(t, func(c *assert.CollectT) {
+			record := <-rsm.ConsumerRecord
+			assert.Nil(c, record.RawKey)
+			assert.Equal(c, []byte{0xde, 0xad, 0xbe, 0xef}, record.RawValue)
+			assert.True(c, timestamp.Equal(record.Timestamp))
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// clean up
+		cancel()
+		ka.DeleteTopic(topic)
+	})
+}
+
+func TestRecordPartitioner(t *testing.T) {
+	partitioner := newRecordPartitioner("topic")
+
+	t.Run("Partition by key like the JVM clients", func(t *testing.T) {
+		partition, err := partitioner.Partition(&sarama.ProducerMessage{
+			Key: sarama.StringEncoder("foobar"),
+		}, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(6), partition)
+	})
+
+	t.Run("Keep the given partition", func(t *testing.T) {
+		partition, err := partitioner.Partition(&sarama.ProducerMessage{
+			Key:       sarama.StringEncoder("foobar"),
+			Partition: 0,
+			Metadata:  manualPartition{},
+		}, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), partition)
+	})
+}
+
+func TestProducerSettings(t *testing.T) {
+	t.Run("Apply to the producer config", func(t *testing.T) {
+		cfg := sarama.NewConfig()
+
+		ProducerSettings{
+			Acks:        AcksLeader,
+			Compression: CompressionGzip,
+		}.apply(cfg)
+
+		assert.Equal(t, sarama.WaitForLocal, cfg.Producer.RequiredAcks)
+		assert.Equal(t, sarama.CompressionGZIP, cfg.Producer.Compression)
+		assert.False(t, cfg.Producer.Idempotent)
+	})
+
+	t.Run("Idempotence requires all acks", func(t *testing.T) {
+		admin := &SaramaKafkaAdmin{config: sarama.NewConfig()}
+
+		_, err := admin.producerFor(ProducerSettings{Acks: AcksNone, Idempotent: true})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestJVMPartition(t *testing.T) {
+	// expected partitions are derived from hashes generated with the JVM client
+	t.Run("Partition by the murmur2 hash of the key", func(t *testing.T) {
+		assert.Equal(t, 0, JVMPartition([]byte("21"), 10))
+		assert.Equal(t, 6, JVMPartition([]byte("foobar"), 10))
+		assert.Equal(t, 7, JVMPartition([]byte("abc"), 10))
+	})
+
+	t.Run("Null keys are not partitioned by key", func(t *testing.T) {
+		assert.Equal(t, -1, JVMPartition(nil, 10))
+	})
 }
@@ -4,88 +4,32 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/json"
-	"fmt"
 	"github.com/linkedin/goavro/v2"
 	"github.com/pkg/errors"
 	"ktea/sradmin"
 )
 
-type GoAvroDeserializer struct {
-	sra sradmin.SrAdmin
-}
-
 type DesData struct {
-	Value  string
-	Schema string
+	Value    string
+	Schema   string
+	SchemaId int
+	// Violations of the schema the value was validated against.
+	Violations []string
 }
 
 var ErrNoSchemaRegistry = errors.New("no schema registry configured")
 
-func (d *GoAvroDeserializer) Deserialize(data []byte) (DesData, error) {
-	if data == nil || len(data) == 0 {
-		return DesData{}, nil
-	}
-
-	schemaId, isAvro := isAvroWithSchemaID(data)
-
-	if isAvro {
-
-		if d.sra == nil {
-			return DesData{}, fmt.Errorf("avro deserialization failed: %w", ErrNoSchemaRegistry)
-		}
-
-		if schema, err := d.getSchema(schemaId); err != nil {
-			return DesData{}, err
-		} else {
-			var codec *goavro.Codec
-			if codec, err = goavro.NewCodec(schema.Value); err != nil {
-				return DesData{}, err
-			}
-
-			deserData, _, err := codec.NativeFromBinary(data[5:])
-			if err != nil {
-				return DesData{}, err
-			}
-
-			jsonData, err := json.Marshal(deserData)
-			if err != nil {
-				return DesData{}, err
-			}
-			return DesData{string(jsonData), schema.Value}, nil
-		}
-	} else {
-		return DesData{Value: string(data)}, nil
+func deserializeAvro(codec *goavro.Codec, schema sradmin.Schema, schemaId int, data []byte) (DesData, error) {
+	deserData, _, err := codec.NativeFromBinary(data)
+	if err != nil {
+		return DesData{}, err
 	}
 
-}
-
-func (d *GoAvroDeserializer) getSchema(schemaId int) (sradmin.Schema, error) {
-	var schema sradmin.Schema
-
-	switch msg := d.sra.GetSchemaById(schemaId).(type) {
-
-	case sradmin.GettingSchemaByIdMsg:
-		{
-			switch msg := msg.AwaitCompletion().(type) {
-
-			case sradmin.SchemaByIdReceived:
-				{
-					schema = msg.Schema
-				}
-			case sradmin.FailedToFetchLatestSchemaBySubject:
-				{
-					return sradmin.Schema{}, msg.Err
-				}
-			}
-		}
-
-	case sradmin.SchemaByIdReceived:
-		{
-			schema = msg.Schema
-		}
+	jsonData, err := json.Marshal(deserData)
+	if err != nil {
+		return DesData{}, err
 	}
-
-	return schema, nil
+	return DesData{Value: string(jsonData), Schema: schema.Value, SchemaId: schemaId}, nil
 }
 
 func isAvroWithSchemaID(data []byte) (int, bool) {
@@ -107,7 +51,3 @@ func isAvroWithSchemaID(data []byte) (int, bool) {
 
 	return int(schemaId), true
 }
-
-func NewAvroDeserializer(sra sradmin.SrAdmin) Deserializer {
-	return &GoAvroDeserializer{sra: sra}
-}
@@ -0,0 +1,246 @@
+package serdes
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/linkedin/goavro/v2"
+)
+
+type avroGenerator struct {
+	codec    *goavro.Codec
+	schema   any
+	walker   avroWalker
+	schemaId int
+}
+
+func newAvroGenerator(schema string, schemaId int) (*avroGenerator, error) {
+	codec, parsedSchema, walker, err := parseAvroSchema(schema, randomValues{})
+	if err != nil {
+		return nil, err
+	}
+	return &avroGenerator{codec: codec, schema: parsedSchema, walker: walker, schemaId: schemaId}, nil
+}
+
+// Generate encodes a random value, which is generated in the textual form consumed Avro records are shown in.
+func (g *avroGenerator) Generate() ([]byte, error) {
+	textual, err := json.Marshal(g.walker.value(g.schema, "", 0))
+	if err != nil {
+		return nil, err
+	}
+	native, _, err := g.codec.NativeFromTextual(textual)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := g.codec.BinaryFromNative(nil, native)
+	if err != nil {
+		return nil, err
+	}
+	return wireFormat(g.schemaId, encoded), nil
+}
+
+// avroSkeleton is a value of the Avro schema in the textual form consumed Avro records are shown in.
+func avroSkeleton(schema string) (any, error) {
+	_, parsedSchema, walker, err := parseAvroSchema(schema, placeholderValues{})
+	if err != nil {
+		return nil, err
+	}
+	return walker.value(parsedSchema, "", 0), nil
+}
+
+func parseAvroSchema(schema string, values valueStrategy) (*goavro.Codec, any, avroWalker, error) {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, nil, avroWalker{}, err
+	}
+	var parsedSchema any
+	if err := json.Unmarshal([]byte(schema), &parsedSchema); err != nil {
+		return nil, nil, avroWalker{}, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	walker := avroWalker{names: avroValidator{named: make(map[string]any)}, values: values}
+	walker.names.collect(parsedSchema, "")
+	return codec, parsedSchema, walker, nil
+}
+
+// avroWalker builds values of a schema with the values of its strategy. Records keep the order of their fields.
+type avroWalker struct {
+	// names resolves named types and union branches like they are when validating
+	names  avroValidator
+	values valueStrategy
+}
+
+func (w avroWalker) value(schema any, namespace string, depth int) any {
+	switch schema := schema.(type) {
+	case string:
+		return w.named(schema, namespace, depth)
+	case []any:
+		return w.union(schema, namespace, depth)
+	case map[string]any:
+		return w.complex(schema, namespace, depth)
+	}
+	return nil
+}
+
+func (w avroWalker) named(typeName string, namespace string, depth int) any {
+	switch typeName {
+	case "null":
+		return nil
+	case "boolean":
+		return w.values.boolean()
+	case "int", "long":
+		return w.values.integer(0, 999)
+	case "float", "double":
+		return float64(w.values.integer(0, 99999)) / 100
+	case "bytes", "string":
+		return w.values.text(0, 8)
+	default:
+		return w.value(w.resolve(typeName, namespace), namespace, depth)
+	}
+}
+
+func (w avroWalker) resolve(typeName string, namespace string) any {
+	named, ok := w.names.named[fullName(typeName, namespace)]
+	if !ok {
+		named = w.names.named[typeName]
+	}
+	return named
+}
+
+func (w avroWalker) union(branches []any, namespace string, depth int) any {
+	if len(branches) == 0 {
+		return nil
+	}
+	branch := branches[w.values.pick(len(branches))]
+	if depth >= maxGeneratedDepth {
+		// stop recursive schemas with the null branch
+		for _, b := range branches {
+			if b == "null" {
+				return nil
+			}
+		}
+	}
+	name := w.names.branchName(branch, namespace)
+	if name == "null" {
+		return nil
+	}
+	return skeletonObject{{name, w.value(branch, namespace, depth)}}
+}
+
+func (w avroWalker) complex(schema map[string]any, namespace string, depth int) any {
+	switch schema["logicalType"] {
+	case "timestamp-millis":
+		return w.values.now().UnixMilli()
+	case "timestamp-micros":
+		return w.values.now().UnixMicro()
+	case "date":
+		return w.values.now().Unix() / (24 * 60 * 60)
+	case "uuid":
+		return w.values.uuid()
+	}
+
+	typeName, _ := schema["type"].(string)
+	switch typeName {
+	case "record":
+		namespace = typeNamespace(schema, namespace)
+		fields, _ := schema["fields"].([]any)
+		record := make(skeletonObject, 0, len(fields))
+		for _, f := range fields {
+			field, _ := f.(map[string]any)
+			name, _ := field["name"].(string)
+			record = append(record, skeletonField{name, w.field(field, namespace, depth+1)})
+		}
+		return record
+	case "enum":
+		if symbol, ok := schema["default"].(string); ok && w.values.defaults() {
+			return symbol
+		}
+		symbols, _ := schema["symbols"].([]any)
+		if len(symbols) == 0 {
+			return nil
+		}
+		return symbols[w.values.pick(len(symbols))]
+	case "fixed":
+		size, _ := schema["size"].(float64)
+		return w.values.text(int(size), int(size))
+	case "array":
+		items := make([]any, w.values.count(depth))
+		for i := range items {
+			items[i] = w.value(schema["items"], namespace, depth+1)
+		}
+		return items
+	case "map":
+		entries := make(skeletonObject, 0)
+		for i := w.values.count(depth); i > 0; i-- {
+			entries = append(entries, skeletonField{w.values.key(), w.value(schema["values"], namespace, depth+1)})
+		}
+		return entries
+	default:
+		return w.value(schema["type"], namespace, depth)
+	}
+}
+
+// field is the default of the field when the strategy uses defaults, or else a value of its type.
+func (w avroWalker) field(field map[string]any, namespace string, depth int) any {
+	if def, ok := field["default"]; ok && w.values.defaults() {
+		return w.fromDefault(field["type"], namespace, def, depth)
+	}
+	return w.value(field["type"], namespace, depth)
+}
+
+// fromDefault converts a default to the textual form, defaults of unions are values of their first branch
+// which are written as {"<type>": value}.
+func (w avroWalker) fromDefault(schema any, namespace string, def any, depth int) any {
+	switch schema := schema.(type) {
+	case string:
+		if named := w.resolve(schema, namespace); named != nil {
+			return w.fromDefault(named, namespace, def, depth)
+		}
+	case []any:
+		if len(schema) == 0 || def == nil {
+			return nil
+		}
+		name := w.names.branchName(schema[0], namespace)
+		if name == "null" {
+			return nil
+		}
+		return skeletonObject{{name, w.fromDefault(schema[0], namespace, def, depth)}}
+	case map[string]any:
+		typeName, _ := schema["type"].(string)
+		switch typeName {
+		case "record":
+			values, _ := def.(map[string]any)
+			namespace = typeNamespace(schema, namespace)
+			fields, _ := schema["fields"].([]any)
+			record := make(skeletonObject, 0, len(fields))
+			for _, f := range fields {
+				field, _ := f.(map[string]any)
+				name, _ := field["name"].(string)
+				if value, ok := values[name]; ok {
+					record = append(record, skeletonField{name, w.fromDefault(field["type"], namespace, value, depth+1)})
+				} else {
+					record = append(record, skeletonField{name, w.field(field, namespace, depth+1)})
+				}
+			}
+			return record
+		case "array":
+			items, _ := def.([]any)
+			converted := make([]any, len(items))
+			for i, item := range items {
+				converted[i] = w.fromDefault(schema["items"], namespace, item, depth+1)
+			}
+			return converted
+		case "map":
+			entries, _ := def.(map[string]any)
+			converted := make(skeletonObject, 0, len(entries))
+			for _, key := range sortedKeys(entries) {
+				converted = append(converted, skeletonField{key, w.fromDefault(schema["values"], namespace, entries[key], depth+1)})
+			}
+			return converted
+		case "enum", "fixed":
+			return def
+		default:
+			return w.fromDefault(schema["type"], namespace, def, depth)
+		}
+	}
+	return def
+}
@@ -0,0 +1,331 @@
+package serdes
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/linkedin/goavro/v2"
+	"math"
+	"sort"
+	"strings"
+)
+
+// serializeAvro encodes the JSON value with the Avro schema, unions are written as {"<type>": value} like
+// consumed Avro records are shown. The value is validated first so mismatches are reported per field.
+func serializeAvro(schema string, value string) ([]byte, error) {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations, err := validateAvro(schema, value); err != nil {
+		return nil, err
+	} else if len(violations) > 0 {
+		return nil, FieldErrors(violations)
+	}
+
+	native, _, err := codec.NativeFromTextual([]byte(value))
+	if err != nil {
+		return nil, err
+	}
+	return codec.BinaryFromNative(nil, native)
+}
+
+// validateAvro reports the fields of the JSON value that do not match the Avro schema.
+func validateAvro(schema string, value string) ([]string, error) {
+	var parsedSchema any
+	if err := json.Unmarshal([]byte(schema), &parsedSchema); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	// numbers are kept as is to tell integers from decimals
+	decoder.UseNumber()
+	var parsedValue any
+	if err := decoder.Decode(&parsedValue); err != nil {
+		return nil, fmt.Errorf("value is no valid JSON: %w", err)
+	}
+
+	v := avroValidator{named: make(map[string]any)}
+	v.collect(parsedSchema, "")
+	v.validate(parsedSchema, "", parsedValue, "$")
+	return v.violations, nil
+}
+
+type avroValidator struct {
+	// named types by their full and short name
+	named      map[string]any
+	violations []string
+}
+
+func (v *avroValidator) violation(path string, format string, args ...any) {
+	v.violations = append(v.violations, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *avroValidator) validate(schema any, namespace string, value any, path string) {
+	switch schema := schema.(type) {
+	case string:
+		v.validateNamed(schema, namespace, value, path)
+	case []any:
+		v.validateUnion(schema, namespace, value, path)
+	case map[string]any:
+		v.validateComplex(schema, namespace, value, path)
+	}
+}
+
+func (v *avroValidator) validateNamed(typeName string, namespace string, value any, path string) {
+	switch typeName {
+	case "null":
+		if value != nil {
+			v.violation(path, "expected null, got %s", jsonType(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.violation(path, "expected boolean, got %s", jsonType(value))
+		}
+	case "int":
+		v.validateInteger(value, path, "int", math.MinInt32, math.MaxInt32)
+	case "long":
+		v.validateInteger(value, path, "long", math.MinInt64, math.MaxInt64)
+	case "float", "double":
+		if _, ok := value.(json.Number); !ok {
+			v.violation(path, "expected %s, got %s", typeName, jsonType(value))
+		}
+	case "bytes", "string":
+		if _, ok := value.(string); !ok {
+			v.violation(path, "expected %s, got %s", typeName, jsonType(value))
+		}
+	default:
+		named, ok := v.named[fullName(typeName, namespace)]
+		if !ok {
+			named, ok = v.named[typeName]
+		}
+		if !ok {
+			v.violation(path, "unknown type %s", typeName)
+			return
+		}
+		v.validate(named, namespace, value, path)
+	}
+}
+
+func (v *avroValidator) validateInteger(value any, path string, typeName string, min int64, max int64) {
+	number, ok := value.(json.Number)
+	if !ok {
+		v.violation(path, "expected %s, got %s", typeName, jsonType(value))
+		return
+	}
+	integer, err := number.Int64()
+	if err != nil {
+		v.violation(path, "expected %s, got %s", typeName, number)
+		return
+	}
+	if integer < min || integer > max {
+		v.violation(path, "%s is out of range for %s", number, typeName)
+	}
+}
+
+func (v *avroValidator) validateUnion(branches []any, namespace string, value any, path string) {
+	names := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		names = append(names, v.branchName(branch, namespace))
+	}
+
+	if value == nil {
+		for _, name := range names {
+			if name == "null" {
+				return
+			}
+		}
+		v.violation(path, "null is not allowed, expected one of %s", strings.Join(names, ", "))
+		return
+	}
+
+	wrapped, ok := value.(map[string]any)
+	if !ok || len(wrapped) != 1 {
+		v.violation(path, "union values are written as {\"<type>\": value}, with type one of %s", strings.Join(names, ", "))
+		return
+	}
+	for name, inner := range wrapped {
+		for i, branchName := range names {
+			if name == branchName {
+				v.validate(branches[i], namespace, inner, path)
+				return
+			}
+		}
+		v.violation(path, "%s is not part of the union, expected one of %s", name, strings.Join(names, ", "))
+	}
+}
+
+// branchName is the name a union branch is selected by, the full name for named types.
+func (v *avroValidator) branchName(branch any, namespace string) string {
+	switch branch := branch.(type) {
+	case string:
+		if _, ok := v.named[fullName(branch, namespace)]; ok {
+			return fullName(branch, namespace)
+		}
+		return branch
+	case map[string]any:
+		typeName, _ := branch["type"].(string)
+		switch typeName {
+		case "record", "enum", "fixed":
+			name, _ := branch["name"].(string)
+			return fullName(name, typeNamespace(branch, namespace))
+		case "array", "map":
+			return typeName
+		default:
+			return v.branchName(typeName, namespace)
+		}
+	}
+	return ""
+}
+
+func (v *avroValidator) validateComplex(schema map[string]any, namespace string, value any, path string) {
+	typeName, _ := schema["type"].(string)
+	switch typeName {
+	case "record":
+		v.validateRecord(schema, typeNamespace(schema, namespace), value, path)
+	case "enum":
+		symbol, ok := value.(string)
+		if !ok {
+			v.violation(path, "expected enum symbol, got %s", jsonType(value))
+			return
+		}
+		symbols, _ := schema["symbols"].([]any)
+		for _, s := range symbols {
+			if s == symbol {
+				return
+			}
+		}
+		v.violation(path, "%q is not a symbol of the enum", symbol)
+	case "fixed":
+		if _, ok := value.(string); !ok {
+			v.violation(path, "expected fixed, got %s", jsonType(value))
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			v.violation(path, "expected array, got %s", jsonType(value))
+			return
+		}
+		for i, item := range items {
+			v.validate(schema["items"], namespace, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case "map":
+		entries, ok := value.(map[string]any)
+		if !ok {
+			v.violation(path, "expected map, got %s", jsonType(value))
+			return
+		}
+		for _, key := range sortedKeys(entries) {
+			v.validate(schema["values"], namespace, entries[key], path+"."+key)
+		}
+	default:
+		// primitives can be annotated, like with a logical type
+		v.validate(schema["type"], namespace, value, path)
+	}
+}
+
+func (v *avroValidator) validateRecord(schema map[string]any, namespace string, value any, path string) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		v.violation(path, "expected record, got %s", jsonType(value))
+		return
+	}
+
+	fields, _ := schema["fields"].([]any)
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		field, _ := f.(map[string]any)
+		name, _ := field["name"].(string)
+		known[name] = true
+
+		fieldValue, present := object[name]
+		if !present {
+			if _, hasDefault := field["default"]; !hasDefault {
+				v.violation(path+"."+name, "missing, the field has no default")
+			}
+			continue
+		}
+		v.validate(field["type"], namespace, fieldValue, path+"."+name)
+	}
+
+	for _, name := range sortedKeys(object) {
+		if !known[name] {
+			v.violation(path+"."+name, "unknown field")
+		}
+	}
+}
+
+// collect registers the named types of the schema, so they can be referred to before the definition is validated.
+func (v *avroValidator) collect(schema any, namespace string) {
+	switch schema := schema.(type) {
+	case []any:
+		for _, branch := range schema {
+			v.collect(branch, namespace)
+		}
+	case map[string]any:
+		switch typeName, _ := schema["type"].(string); typeName {
+		case "record", "enum", "fixed":
+			name, _ := schema["name"].(string)
+			namespace = typeNamespace(schema, namespace)
+			v.named[fullName(name, namespace)] = schema
+			v.named[name[strings.LastIndex(name, ".")+1:]] = schema
+
+			fields, _ := schema["fields"].([]any)
+			for _, f := range fields {
+				if field, ok := f.(map[string]any); ok {
+					v.collect(field["type"], namespace)
+				}
+			}
+		case "array":
+			v.collect(schema["items"], namespace)
+		case "map":
+			v.collect(schema["values"], namespace)
+		default:
+			v.collect(schema["type"], namespace)
+		}
+	}
+}
+
+func typeNamespace(schema map[string]any, enclosing string) string {
+	name, _ := schema["name"].(string)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i]
+	}
+	if namespace, ok := schema["namespace"].(string); ok {
+		return namespace
+	}
+	return enclosing
+}
+
+func fullName(name string, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
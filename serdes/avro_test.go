@@ -24,7 +24,7 @@ func TestAvroDeserializer(t *testing.T) {
 `
 
 	t.Run("no data deserializes to empty string", func(t *testing.T) {
-		deserializer := NewAvroDeserializer(sradmin.NewMock())
+		deserializer := NewDeserializer(sradmin.NewMock())
 
 		res, err := deserializer.Deserialize(nil)
 
@@ -50,7 +50,7 @@ func TestAvroDeserializer(t *testing.T) {
 				},
 			}
 		}
-		deserializer := NewAvroDeserializer(sraMock)
+		deserializer := NewDeserializer(sraMock)
 
 		codec, err := goavro.NewCodec(schema)
 		if err != nil {
@@ -95,7 +95,7 @@ func TestAvroDeserializer(t *testing.T) {
 					},
 				}
 			}
-			deserializer := NewAvroDeserializer(sraMock)
+			deserializer := NewDeserializer(sraMock)
 
 			codec, err := goavro.NewCodec(schema)
 			if err != nil {
@@ -125,3 +125,82 @@ func TestAvroDeserializer(t *testing.T) {
 		})
 	})
 }
+
+const benchmarkSchema = `{
+  "type": "record",
+  "name": "Order",
+  "fields": [
+    {"name": "id", "type": "string"},
+    {"name": "customer", "type": "string"},
+    {"name": "amount", "type": "double"},
+    {"name": "lines", "type": {"type": "array", "items": {
+      "type": "record",
+      "name": "Line",
+      "fields": [
+        {"name": "sku", "type": "string"},
+        {"name": "quantity", "type": "int"}
+      ]
+    }}}
+  ]
+}`
+
+// BenchmarkAvroDeserialization compares deserializing Avro records with a new deserializer, and thus
+// a new codec, per record with deserializing them using the cached codecs of a shared deserializer,
+// sequentially and concurrently as the deserialization pool of the record reader does.
+func BenchmarkAvroDeserialization(b *testing.B) {
+	codec, err := goavro.NewCodec(benchmarkSchema)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data, err := codec.BinaryFromNative([]byte{0, 0, 0, 0, 1}, map[string]interface{}{
+		"id":       "order-1",
+		"customer": "John",
+		"amount":   12.5,
+		"lines": []interface{}{
+			map[string]interface{}{"sku": "tea", "quantity": 2},
+			map[string]interface{}{"sku": "cup", "quantity": 1},
+		},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	sraMock := sradmin.NewMock()
+	sraMock.GetSchemaByIdFunc = func(id int) tea.Msg {
+		return sradmin.SchemaByIdReceived{Schema: sradmin.Schema{Value: benchmarkSchema}}
+	}
+	reportRecordsPerSecond := func(b *testing.B) {
+		b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "records/s")
+	}
+
+	b.Run("new deserializer per record", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := NewDeserializer(sraMock).Deserialize(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+		reportRecordsPerSecond(b)
+	})
+
+	b.Run("cached codecs", func(b *testing.B) {
+		deserializer := NewDeserializer(sraMock)
+		for i := 0; i < b.N; i++ {
+			if _, err := deserializer.Deserialize(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+		reportRecordsPerSecond(b)
+	})
+
+	b.Run("cached codecs concurrently", func(b *testing.B) {
+		deserializer := NewDeserializer(sraMock)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := deserializer.Deserialize(data); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+		reportRecordsPerSecond(b)
+	})
+}
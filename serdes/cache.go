@@ -0,0 +1,36 @@
+package serdes
+
+import (
+	"sync"
+)
+
+// schemaCache holds a value, like a schema or a codec, per schema id.
+// It is safe for concurrent use, failed loads are not cached.
+type schemaCache[V any] struct {
+	mu     sync.RWMutex
+	values map[int]V
+}
+
+// get returns the value cached for the schema id, or loads and caches it.
+func (c *schemaCache[V]) get(schemaId int, load func() (V, error)) (V, error) {
+	c.mu.RLock()
+	value, ok := c.values[schemaId]
+	c.mu.RUnlock()
+	if ok {
+		return value, nil
+	}
+
+	// loading happens outside the lock, concurrent loads of the same id are harmless
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		c.values = make(map[int]V)
+	}
+	c.values[schemaId] = value
+	return value, nil
+}
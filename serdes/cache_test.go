@@ -0,0 +1,56 @@
+package serdes
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"ktea/sradmin"
+	"testing"
+)
+
+func TestSchemaCache(t *testing.T) {
+	t.Run("Fetch a schema once per schema id", func(t *testing.T) {
+		schema := `{"type": "record", "name": "Order", "fields": [{"name": "id", "type": "int"}]}`
+		var fetched []int
+		sraMock := sradmin.NewMock()
+		sraMock.GetSchemaByIdFunc = func(id int) tea.Msg {
+			fetched = append(fetched, id)
+			return sradmin.SchemaByIdReceived{Schema: sradmin.Schema{Value: schema}}
+		}
+		codec, _ := goavro.NewCodec(schema)
+		deserializer := NewDeserializer(sraMock)
+
+		for _, schemaId := range []byte{1, 1, 2, 1} {
+			data, _ := codec.BinaryFromNative([]byte{0, 0, 0, 0, schemaId}, map[string]interface{}{"id": 7})
+			res, err := deserializer.Deserialize(data)
+
+			assert.NoError(t, err)
+			assert.Equal(t, `{"id":7}`, res.Value)
+		}
+
+		assert.Equal(t, []int{1, 2}, fetched)
+	})
+
+	t.Run("Do not cache failures", func(t *testing.T) {
+		var cache schemaCache[string]
+		loads := 0
+		load := func() (string, error) {
+			loads++
+			if loads == 1 {
+				return "", assert.AnError
+			}
+			return "schema", nil
+		}
+
+		_, err := cache.get(1, load)
+		assert.ErrorIs(t, err, assert.AnError)
+
+		value, err := cache.get(1, load)
+		assert.NoError(t, err)
+		assert.Equal(t, "schema", value)
+
+		value, _ = cache.get(1, load)
+		assert.Equal(t, "schema", value)
+		assert.Equal(t, 2, loads)
+	})
+}
@@ -0,0 +1,136 @@
+package serdes
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"ktea/sradmin"
+	"math"
+	"strconv"
+)
+
+// Format is the format the key or value of a record is deserialized with.
+type Format string
+
+const (
+	// AutoFormat deserializes data framed with a schema id using the Schema Registry
+	// and returns all other data as is.
+	AutoFormat           Format = ""
+	StringFormat         Format = "string"
+	SchemaRegistryFormat Format = "schema-registry"
+	Int32Format          Format = "int32"
+	Int64Format          Format = "int64"
+	Float64Format        Format = "float64"
+	UuidFormat           Format = "uuid"
+	Base64Format         Format = "base64"
+	HexFormat            Format = "hex"
+)
+
+// Formats lists all formats in the order they are presented.
+var Formats = []Format{
+	AutoFormat,
+	StringFormat,
+	SchemaRegistryFormat,
+	Int32Format,
+	Int64Format,
+	Float64Format,
+	UuidFormat,
+	Base64Format,
+	HexFormat,
+}
+
+var ErrNoSchemaId = errors.New("data is not framed with a schema id")
+
+func (f Format) String() string {
+	switch f {
+	case AutoFormat:
+		return "Auto"
+	case StringFormat:
+		return "String"
+	case SchemaRegistryFormat:
+		return "Schema Registry"
+	case Int32Format:
+		return "Int32"
+	case Int64Format:
+		return "Int64"
+	case Float64Format:
+		return "Float64"
+	case UuidFormat:
+		return "UUID"
+	case Base64Format:
+		return "Base64"
+	case HexFormat:
+		return "Hex"
+	default:
+		return string(f)
+	}
+}
+
+// FormatDeserializer deserializes data using an explicitly chosen Format.
+type FormatDeserializer struct {
+	format Format
+	sr     *SrDeserializer
+}
+
+func (d *FormatDeserializer) Deserialize(data []byte) (DesData, error) {
+	if len(data) == 0 {
+		return DesData{}, nil
+	}
+
+	switch d.format {
+	case StringFormat:
+		return DesData{Value: string(data)}, nil
+	case SchemaRegistryFormat:
+		if _, hasSchemaId := isAvroWithSchemaID(data); !hasSchemaId {
+			return DesData{}, ErrNoSchemaId
+		}
+		return d.sr.Deserialize(data)
+	case Int32Format:
+		if err := expectLength(d.format, data, 4); err != nil {
+			return DesData{}, err
+		}
+		return DesData{Value: strconv.FormatInt(int64(int32(binary.BigEndian.Uint32(data))), 10)}, nil
+	case Int64Format:
+		if err := expectLength(d.format, data, 8); err != nil {
+			return DesData{}, err
+		}
+		return DesData{Value: strconv.FormatInt(int64(binary.BigEndian.Uint64(data)), 10)}, nil
+	case Float64Format:
+		if err := expectLength(d.format, data, 8); err != nil {
+			return DesData{}, err
+		}
+		value := math.Float64frombits(binary.BigEndian.Uint64(data))
+		return DesData{Value: strconv.FormatFloat(value, 'g', -1, 64)}, nil
+	case UuidFormat:
+		id, err := uuid.FromBytes(data)
+		if err != nil {
+			return DesData{}, expectLength(d.format, data, 16)
+		}
+		return DesData{Value: id.String()}, nil
+	case Base64Format:
+		return DesData{Value: base64.StdEncoding.EncodeToString(data)}, nil
+	case HexFormat:
+		return DesData{Value: hex.EncodeToString(data)}, nil
+	default:
+		return d.sr.Deserialize(data)
+	}
+}
+
+func expectLength(format Format, data []byte, length int) error {
+	if len(data) != length {
+		return fmt.Errorf("%s deserialization failed: expected %d bytes but got %d", format, length, len(data))
+	}
+	return nil
+}
+
+// NewFormatDeserializer creates a Deserializer for the format,
+// AutoFormat behaves like the Deserializer created by NewDeserializer.
+func NewFormatDeserializer(format Format, sra sradmin.SrAdmin) Deserializer {
+	return &FormatDeserializer{
+		format: format,
+		sr:     newSrDeserializer(sra),
+	}
+}
@@ -0,0 +1,56 @@
+package serdes
+
+import (
+	"github.com/stretchr/testify/assert"
+	"ktea/sradmin"
+	"testing"
+)
+
+func TestFormatDeserializer(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   []byte
+		want   string
+	}{
+		{"string", StringFormat, []byte("key-1"), "key-1"},
+		{"string of a schema id framed value", StringFormat, []byte{0, 0, 0, 0, 1, 'a'}, "\x00\x00\x00\x00\x01a"},
+		{"int32", Int32Format, []byte{0xff, 0xff, 0xff, 0xfe}, "-2"},
+		{"int64", Int64Format, []byte{0, 0, 0, 0, 0, 0, 0x01, 0x00}, "256"},
+		{"float64", Float64Format, []byte{0x40, 0x09, 0x21, 0xfb, 0x54, 0x44, 0x2d, 0x18}, "3.141592653589793"},
+		{"uuid", UuidFormat, []byte{
+			0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3,
+			0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00,
+		}, "123e4567-e89b-12d3-a456-426614174000"},
+		{"base64", Base64Format, []byte{0xde, 0xad, 0xbe, 0xef}, "3q2+7w=="},
+		{"hex", HexFormat, []byte{0xde, 0xad, 0xbe, 0xef}, "deadbeef"},
+		{"auto", AutoFormat, []byte("value"), "value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := NewFormatDeserializer(tt.format, sradmin.NewMock()).Deserialize(tt.data)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, res.Value)
+		})
+	}
+
+	t.Run("unexpected number of bytes", func(t *testing.T) {
+		_, err := NewFormatDeserializer(Int64Format, sradmin.NewMock()).Deserialize([]byte{0, 1, 2, 3})
+
+		assert.EqualError(t, err, "Int64 deserialization failed: expected 8 bytes but got 4")
+	})
+
+	t.Run("schema registry format requires a schema id", func(t *testing.T) {
+		_, err := NewFormatDeserializer(SchemaRegistryFormat, sradmin.NewMock()).Deserialize([]byte("value"))
+
+		assert.ErrorIs(t, err, ErrNoSchemaId)
+	})
+
+	t.Run("empty data", func(t *testing.T) {
+		res, err := NewFormatDeserializer(Int32Format, sradmin.NewMock()).Deserialize(nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, DesData{}, res)
+	})
+}
@@ -0,0 +1,168 @@
+package serdes
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"ktea/sradmin"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxGeneratedDepth limits the nesting of generated values, recursive schemas are cut off beyond it
+// by choosing null, empty collections or leaving messages empty.
+const maxGeneratedDepth = 5
+
+// Generator generates random values that match a registered schema, serialized in the Schema Registry
+// wire format like the Serializer does.
+type Generator interface {
+	Generate() ([]byte, error)
+}
+
+// NewGenerator fetches and compiles the selected schema once, so it can generate many values.
+func NewGenerator(sra sradmin.SrAdmin, selection SchemaSelection) (Generator, error) {
+	if sra == nil {
+		return nil, fmt.Errorf("generation failed: %w", ErrNoSchemaRegistry)
+	}
+
+	schema, err := fetchSubjectSchema(sra, selection.Subject, selection.Version)
+	if err != nil {
+		return nil, err
+	}
+	schemaId, err := strconv.Atoi(schema.Id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema id %q: %w", schema.Id, err)
+	}
+
+	switch schema.Type {
+	case sradmin.AvroSchemaType, "":
+		return newAvroGenerator(schema.Value, schemaId)
+	case sradmin.ProtobufSchemaType:
+		fd, err := (&ProtobufDeserializer{sra: sra}).compile(schema, schemaId)
+		if err != nil {
+			return nil, err
+		}
+		md, indexes, err := messageIndexes(fd, selection.Message)
+		if err != nil {
+			return nil, err
+		}
+		return &protobufGenerator{md: md, indexes: indexes, schemaId: schemaId}, nil
+	case sradmin.JsonSchemaType:
+		compiled, err := (&JsonSchemaDeserializer{sra: sra}).compile(schema, schemaId)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchemaGenerator{schema: compiled, schemaId: schemaId}, nil
+	default:
+		return nil, fmt.Errorf("generating %s values is not supported", schema.Type)
+	}
+}
+
+// valueStrategy chooses the values the schemas are walked with: random values to generate records with,
+// or the placeholders of a skeleton to fill in.
+type valueStrategy interface {
+	// pick is the index of one of n alternatives, like union branches, enum symbols or types.
+	pick(n int) int
+	// count is the number of items of a collection, none beyond maxGeneratedDepth.
+	count(depth int) int
+	boolean() bool
+	// integer is within low and high, both inclusive.
+	integer(low, high int64) int64
+	// float is within low and high, inBounds reports whether a value respects exclusive bounds.
+	float(low, high float64, inBounds func(float64) bool) float64
+	// text has at least minimum and at most length characters.
+	text(minimum, length int) string
+	key() string
+	now() time.Time
+	uuid() string
+	// defaults reports whether the defaults of the schema are used.
+	defaults() bool
+}
+
+type randomValues struct{}
+
+func (randomValues) pick(n int) int { return rand.IntN(n) }
+
+func (randomValues) count(depth int) int { return randomCount(depth) }
+
+func (randomValues) boolean() bool { return rand.IntN(2) == 1 }
+
+func (randomValues) integer(low, high int64) int64 {
+	if high < low {
+		return low
+	}
+	return low + rand.Int64N(high-low+1)
+}
+
+func (randomValues) float(low, high float64, inBounds func(float64) bool) float64 {
+	// rounding can end up on an exclusive bound
+	for i := 0; i < 10; i++ {
+		if value := low + rand.Float64()*(high-low); inBounds(value) {
+			return value
+		}
+	}
+	return low + (high-low)/2
+}
+
+func (randomValues) text(minimum, length int) string { return randomString(length) }
+
+func (randomValues) key() string { return randomString(4) }
+
+func (randomValues) now() time.Time { return time.Now() }
+
+func (randomValues) uuid() string { return uuid.NewString() }
+
+func (randomValues) defaults() bool { return false }
+
+// placeholderValues are the zero value of each type, or the value closest to it within the bounds,
+// and the defaults of the schema.
+type placeholderValues struct{}
+
+func (placeholderValues) pick(n int) int { return 0 }
+
+func (placeholderValues) count(depth int) int {
+	if depth >= maxGeneratedDepth {
+		return 0
+	}
+	return 1
+}
+
+func (placeholderValues) boolean() bool { return false }
+
+func (placeholderValues) integer(low, high int64) int64 { return max(low, min(0, high)) }
+
+func (placeholderValues) float(low, high float64, inBounds func(float64) bool) float64 {
+	if value := max(low, min(0, high)); inBounds(value) {
+		return value
+	}
+	return low + (high-low)/2
+}
+
+func (placeholderValues) text(minimum, length int) string { return strings.Repeat("0", minimum) }
+
+func (placeholderValues) key() string { return "key" }
+
+func (placeholderValues) now() time.Time { return time.Unix(0, 0).UTC() }
+
+func (placeholderValues) uuid() string { return uuid.Nil.String() }
+
+func (placeholderValues) defaults() bool { return true }
+
+const randomLetters = "abcdefghijklmnopqrstuvwxyz"
+
+func randomString(length int) string {
+	letters := make([]byte, length)
+	for i := range letters {
+		letters[i] = randomLetters[rand.IntN(len(randomLetters))]
+	}
+	return string(letters)
+}
+
+// randomCount is the number of items of a generated collection, none beyond maxGeneratedDepth.
+func randomCount(depth int) int {
+	if depth >= maxGeneratedDepth {
+		return 0
+	}
+	return 1 + rand.IntN(3)
+}
@@ -0,0 +1,137 @@
+package serdes
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"ktea/sradmin"
+	"testing"
+)
+
+func TestGenerator(t *testing.T) {
+	t.Run("generate Avro values with recursive and logical types", func(t *testing.T) {
+		schema := `
+{
+   "type" : "record",
+   "namespace" : "ktea.test",
+   "name" : "Node",
+   "fields" : [
+      { "name" : "Id" , "type" : { "type": "string", "logicalType": "uuid" } },
+      { "name" : "CreatedAt" , "type" : { "type": "long", "logicalType": "timestamp-millis" } },
+      { "name" : "Status" , "type" : { "type": "enum", "name": "Status", "symbols": ["ACTIVE", "INACTIVE"] } },
+      { "name" : "Labels" , "type" : { "type": "map", "values": "string" } },
+      { "name" : "Children" , "type" : { "type": "array", "items": "Node" } },
+      { "name" : "Parent" , "type" : ["null", "Node"], "default": null }
+   ]
+}
+`
+		sraMock := sradmin.NewMock()
+		sraMock.GetLatestSchemaBySubjectFunc = func(subject string) tea.Msg {
+			return sradmin.LatestSchemaBySubjectReceived{
+				Schema: sradmin.Schema{Id: "7", Value: schema, Type: sradmin.AvroSchemaType},
+			}
+		}
+
+		generator, err := NewGenerator(sraMock, SchemaSelection{Subject: "node-value"})
+		assert.NoError(t, err)
+
+		codec, _ := goavro.NewCodec(schema)
+		for i := 0; i < 20; i++ {
+			data, err := generator.Generate()
+			assert.NoError(t, err)
+			assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x07}, data[:5])
+			native, _, err := codec.NativeFromBinary(data[5:])
+			assert.NoError(t, err)
+			assert.Contains(t, []any{"ACTIVE", "INACTIVE"}, native.(map[string]any)["Status"])
+		}
+	})
+
+	t.Run("generate Protobuf messages", func(t *testing.T) {
+		sraMock := newProtobufSraMock()
+		sraMock.GetLatestSchemaBySubjectFunc = func(subject string) tea.Msg {
+			return sradmin.LatestSchemaBySubjectReceived{
+				Schema: sradmin.Schema{
+					Id:    "7",
+					Value: personSchema,
+					Type:  sradmin.ProtobufSchemaType,
+					References: []sradmin.SchemaReference{
+						{Name: "address.proto", Subject: "address-value", Version: 3},
+					},
+				},
+			}
+		}
+
+		generator, err := NewGenerator(sraMock, SchemaSelection{Subject: "person-value"})
+		assert.NoError(t, err)
+
+		data, err := generator.Generate()
+
+		assert.NoError(t, err)
+		res, err := NewDeserializer(sraMock).Deserialize(data)
+		assert.NoError(t, err)
+		assert.Regexp(t, `\{"name":"[a-z]{8}",("age":\d+,)?"address":\{"street":"[a-z]{8}"}}`, res.Value)
+	})
+
+	t.Run("generate JSON Schema values", func(t *testing.T) {
+		sraMock := newJsonSchemaSraMock()
+		sraMock.GetLatestSchemaBySubjectFunc = func(subject string) tea.Msg {
+			return sradmin.LatestSchemaBySubjectReceived{
+				Schema: sradmin.Schema{
+					Id:    "3",
+					Value: personJsonSchema,
+					Type:  sradmin.JsonSchemaType,
+					References: []sradmin.SchemaReference{
+						{Name: "address.json", Subject: "address", Version: 1},
+					},
+				},
+			}
+		}
+
+		generator, err := NewGenerator(sraMock, SchemaSelection{Subject: "person-value"})
+		assert.NoError(t, err)
+
+		for i := 0; i < 20; i++ {
+			data, err := generator.Generate()
+			assert.NoError(t, err)
+			res, err := NewDeserializer(sraMock).Deserialize(data)
+			assert.NoError(t, err)
+			assert.Empty(t, res.Violations)
+		}
+	})
+
+	t.Run("generate JSON Schema numbers within their bounds", func(t *testing.T) {
+		sraMock := sradmin.NewMock()
+		sraMock.GetLatestSchemaBySubjectFunc = func(subject string) tea.Msg {
+			return sradmin.LatestSchemaBySubjectReceived{
+				Schema: sradmin.Schema{
+					Id: "5",
+					Value: `{
+  "type": "object",
+  "properties": {
+    "price": {"type": "number", "minimum": 0.5, "exclusiveMaximum": 1},
+    "ratio": {"type": "number", "exclusiveMinimum": 0, "maximum": 0.01},
+    "discount": {"type": "number", "maximum": -2.5},
+    "quantity": {"type": "integer", "exclusiveMinimum": 1.5, "exclusiveMaximum": 3.5}
+  },
+  "required": ["price", "ratio", "discount", "quantity"]
+}`,
+					Type: sradmin.JsonSchemaType,
+				},
+			}
+		}
+
+		generator, err := NewGenerator(sraMock, SchemaSelection{Subject: "order-value"})
+		assert.NoError(t, err)
+
+		for i := 0; i < 200; i++ {
+			_, err := generator.Generate()
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("fail without Schema Registry", func(t *testing.T) {
+		_, err := NewGenerator(nil, SchemaSelection{Subject: "person-value"})
+
+		assert.ErrorIs(t, err, ErrNoSchemaRegistry)
+	})
+}
@@ -0,0 +1,163 @@
+package serdes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"io"
+	"ktea/sradmin"
+	"net/url"
+	"strings"
+)
+
+// schemaBaseUrl is the in-memory location JSON Schemas, and the schemas they reference, are registered under.
+const schemaBaseUrl = "mem://schema-registry/"
+
+// JsonSchemaDeserializer decodes JSON data framed with a JSON Schema id
+// and validates it against that schema.
+type JsonSchemaDeserializer struct {
+	sra      sradmin.SrAdmin
+	compiled schemaCache[*jsonschema.Schema]
+}
+
+func (d *JsonSchemaDeserializer) deserialize(schema sradmin.Schema, schemaId int, data []byte) (DesData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var instance interface{}
+	if err := decoder.Decode(&instance); err != nil {
+		return DesData{}, fmt.Errorf("json schema deserialization failed: %w", err)
+	}
+
+	compiled, err := d.compiled.get(schemaId, func() (*jsonschema.Schema, error) {
+		return d.compile(schema, schemaId)
+	})
+	if err != nil {
+		return DesData{}, err
+	}
+
+	desData := DesData{Value: string(data), Schema: schema.Value, SchemaId: schemaId}
+
+	if err := compiled.Validate(instance); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if !errors.As(err, &validationErr) {
+			return DesData{}, err
+		}
+		desData.Violations = violations(validationErr)
+	}
+
+	return desData, nil
+}
+
+// violations flattens a ValidationError into the violations that caused it.
+func violations(err *jsonschema.ValidationError) []string {
+	if len(err.Causes) == 0 {
+		location := err.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		return []string{location + ": " + err.Message}
+	}
+
+	var result []string
+	for _, cause := range err.Causes {
+		result = append(result, violations(cause)...)
+	}
+	return result
+}
+
+// compile compiles the schema, together with all the schemas it references.
+func (d *JsonSchemaDeserializer) compile(schema sradmin.Schema, schemaId int) (*jsonschema.Schema, error) {
+	path := fmt.Sprintf("schema-%d.json", schemaId)
+	sources := map[string]string{path: schema.Value}
+	if err := resolveReferences(d.sra, schema.References, sources); err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	// keep defaults, skeletons of the schema use them
+	compiler.ExtractAnnotations = true
+	// only resolve references registered in the Schema Registry
+	bases := schemaIds(sources)
+	compiler.LoadURL = func(s string) (io.ReadCloser, error) {
+		if source, ok := lookupReference(s, sources, bases); ok {
+			return io.NopCloser(strings.NewReader(source)), nil
+		}
+		return nil, fmt.Errorf("reference %s not found", s)
+	}
+	for name, source := range sources {
+		if err := compiler.AddResource(schemaUrl(name), strings.NewReader(source)); err != nil {
+			return nil, fmt.Errorf("unable to compile json schema: %w", err)
+		}
+	}
+
+	compiled, err := compiler.Compile(schemaUrl(path))
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile json schema: %w", err)
+	}
+	return compiled, nil
+}
+
+// schemaUrl resolves the name of a (referenced) schema to an absolute url.
+func schemaUrl(name string) string {
+	if u, err := url.Parse(name); err == nil && u.IsAbs() {
+		return name
+	}
+	return schemaBaseUrl + name
+}
+
+// lookupReference finds the referenced schema of a url that a relative $ref was resolved to, against
+// the $id of the schema it is in instead of the location the schema is registered under.
+func lookupReference(ref string, sources map[string]string, bases []*url.URL) (string, bool) {
+	for _, base := range bases {
+		for name, source := range sources {
+			relative, err := url.Parse(name)
+			if err != nil || relative.IsAbs() {
+				continue
+			}
+			if base.ResolveReference(relative).String() == ref {
+				return source, true
+			}
+		}
+	}
+	return "", false
+}
+
+// schemaIds collects the absolute $ids of the schemas and their subschemas, relative $ids are
+// resolved against the $id of the enclosing schema.
+func schemaIds(sources map[string]string) []*url.URL {
+	var ids []*url.URL
+	var collect func(schema any, base *url.URL)
+	collect = func(schema any, base *url.URL) {
+		switch schema := schema.(type) {
+		case map[string]any:
+			if id, ok := schema["$id"].(string); ok {
+				if parsed, err := url.Parse(id); err == nil {
+					if base != nil {
+						parsed = base.ResolveReference(parsed)
+					}
+					if parsed.IsAbs() {
+						base = parsed
+						ids = append(ids, parsed)
+					}
+				}
+			}
+			for _, value := range schema {
+				collect(value, base)
+			}
+		case []any:
+			for _, value := range schema {
+				collect(value, base)
+			}
+		}
+	}
+
+	for _, source := range sources {
+		var schema any
+		if err := json.Unmarshal([]byte(source), &schema); err == nil {
+			collect(schema, nil)
+		}
+	}
+	return ids
+}
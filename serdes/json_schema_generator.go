@@ -0,0 +1,225 @@
+package serdes
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"math/big"
+	"time"
+)
+
+type jsonSchemaGenerator struct {
+	schema   *jsonschema.Schema
+	schemaId int
+}
+
+// Generate validates the random value, as keywords like pattern are not taken into account when generating it.
+func (g *jsonSchemaGenerator) Generate() ([]byte, error) {
+	value := jsonSchemaValue(g.schema, randomValues{}, 0)
+	if err := g.schema.Validate(value); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if !errors.As(err, &validationErr) {
+			return nil, err
+		}
+		return nil, FieldErrors(violations(validationErr))
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return wireFormat(g.schemaId, encoded), nil
+}
+
+// jsonSchemaValue is a value of the schema with the values of the strategy. Objects have every property
+// of the schema, which are marshalled in alphabetical order as the compiled schema does not keep their order.
+func jsonSchemaValue(schema *jsonschema.Schema, values valueStrategy, depth int) any {
+	switch {
+	case schema.Ref != nil:
+		return jsonSchemaValue(schema.Ref, values, depth)
+	case schema.Default != nil && values.defaults():
+		return schema.Default
+	case len(schema.Constant) > 0:
+		return schema.Constant[0]
+	case len(schema.Enum) > 0:
+		return schema.Enum[values.pick(len(schema.Enum))]
+	case len(schema.OneOf) > 0:
+		return jsonSchemaValue(schema.OneOf[values.pick(len(schema.OneOf))], values, depth)
+	case len(schema.AnyOf) > 0:
+		return jsonSchemaValue(schema.AnyOf[values.pick(len(schema.AnyOf))], values, depth)
+	case len(schema.AllOf) > 0 && len(schema.Types) == 0 && len(schema.Properties) == 0:
+		return jsonSchemaAllOf(schema.AllOf, values, depth)
+	}
+
+	switch jsonSchemaType(schema, values, depth) {
+	case "object":
+		object := make(map[string]any, len(schema.Properties))
+		for name, property := range schema.Properties {
+			object[name] = jsonSchemaValue(property, values, depth+1)
+		}
+		return object
+	case "array":
+		items := make([]any, max(values.count(depth), schema.MinItems))
+		if schema.MaxItems >= 0 && len(items) > schema.MaxItems {
+			items = items[:schema.MaxItems]
+		}
+		for i := range items {
+			items[i] = jsonSchemaValue(jsonSchemaItems(schema, i), values, depth+1)
+		}
+		return items
+	case "string":
+		return jsonSchemaString(schema, values)
+	case "integer":
+		return float64(jsonSchemaInteger(schema, values))
+	case "number":
+		return jsonSchemaNumber(schema, values)
+	case "boolean":
+		return values.boolean()
+	default:
+		return nil
+	}
+}
+
+// jsonSchemaAllOf merges the objects of each of the schemas.
+func jsonSchemaAllOf(schemas []*jsonschema.Schema, values valueStrategy, depth int) any {
+	merged := make(map[string]any)
+	for _, schema := range schemas {
+		value := jsonSchemaValue(schema, values, depth)
+		object, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		for name, value := range object {
+			merged[name] = value
+		}
+	}
+	return merged
+}
+
+// jsonSchemaType picks one of the allowed types, null last unless it stops a recursive schema.
+func jsonSchemaType(schema *jsonschema.Schema, values valueStrategy, depth int) string {
+	switch {
+	case len(schema.Types) == 0 && len(schema.Properties) > 0:
+		return "object"
+	case len(schema.Types) == 0:
+		return "string"
+	}
+	types := make([]string, 0, len(schema.Types))
+	for _, t := range schema.Types {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	if len(types) < len(schema.Types) {
+		if depth >= maxGeneratedDepth {
+			return "null"
+		}
+		types = append(types, "null")
+	}
+	return types[values.pick(len(types))]
+}
+
+func jsonSchemaItems(schema *jsonschema.Schema, index int) *jsonschema.Schema {
+	if index < len(schema.PrefixItems) {
+		return schema.PrefixItems[index]
+	}
+	switch items := schema.Items.(type) {
+	case *jsonschema.Schema:
+		return items
+	case []*jsonschema.Schema:
+		if index < len(items) {
+			return items[index]
+		}
+	}
+	if schema.Items2020 != nil {
+		return schema.Items2020
+	}
+	// any value is allowed
+	return &jsonschema.Schema{Types: []string{"string"}, MinLength: -1, MaxLength: -1}
+}
+
+func jsonSchemaString(schema *jsonschema.Schema, values valueStrategy) string {
+	switch schema.Format {
+	case "date-time":
+		return values.now().Format(time.RFC3339)
+	case "date":
+		return values.now().Format(time.DateOnly)
+	case "time":
+		return values.now().Format("15:04:05Z07:00")
+	case "uuid":
+		return values.uuid()
+	case "email":
+		return values.text(1, 8) + "@example.com"
+	case "uri":
+		return "https://example.com/" + values.text(0, 8)
+	}
+	minimum := max(0, schema.MinLength)
+	length := max(8, minimum)
+	if schema.MaxLength >= 0 {
+		length = min(length, schema.MaxLength)
+	}
+	return values.text(minimum, length)
+}
+
+// jsonSchemaInteger is an integer within the minimum and maximum of the schema, between 0 and 1000 by default.
+func jsonSchemaInteger(schema *jsonschema.Schema, values valueStrategy) int64 {
+	low, high := int64(0), int64(1000)
+	if schema.Minimum != nil {
+		low = ratCeil(schema.Minimum)
+	} else if schema.ExclusiveMinimum != nil {
+		low = ratFloor(schema.ExclusiveMinimum) + 1
+	}
+	if schema.Maximum != nil {
+		high = ratFloor(schema.Maximum)
+	} else if schema.ExclusiveMaximum != nil {
+		high = ratCeil(schema.ExclusiveMaximum) - 1
+	}
+	if schema.Minimum != nil || schema.ExclusiveMinimum != nil {
+		if schema.Maximum == nil && schema.ExclusiveMaximum == nil {
+			high = low + 1000
+		}
+	} else if high < low {
+		low = high - 1000
+	}
+	return values.integer(low, high)
+}
+
+// jsonSchemaNumber is a number within the minimum and maximum of the schema, between 0 and 1000 by default.
+func jsonSchemaNumber(schema *jsonschema.Schema, values valueStrategy) float64 {
+	low, lowSet := ratFloat(schema.Minimum, schema.ExclusiveMinimum)
+	high, highSet := ratFloat(schema.Maximum, schema.ExclusiveMaximum)
+	switch {
+	case lowSet && !highSet:
+		high = low + 1000
+	case highSet && !lowSet:
+		low = high - 1000
+	case !lowSet && !highSet:
+		low, high = 0, 1000
+	}
+
+	return values.float(low, high, func(value float64) bool {
+		return (value > low || schema.ExclusiveMinimum == nil && value == low) &&
+			(value < high || schema.ExclusiveMaximum == nil && value == high)
+	})
+}
+
+// ratFloat is the inclusive bound, or else the exclusive bound, as a float.
+func ratFloat(inclusive *big.Rat, exclusive *big.Rat) (float64, bool) {
+	switch {
+	case inclusive != nil:
+		f, _ := inclusive.Float64()
+		return f, true
+	case exclusive != nil:
+		f, _ := exclusive.Float64()
+		return f, true
+	}
+	return 0, false
+}
+
+func ratFloor(r *big.Rat) int64 {
+	// Euclidean division rounds towards negative infinity for the positive denominator
+	return new(big.Int).Div(r.Num(), r.Denom()).Int64()
+}
+
+func ratCeil(r *big.Rat) int64 {
+	return -ratFloor(new(big.Rat).Neg(r))
+}
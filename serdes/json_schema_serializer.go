@@ -0,0 +1,42 @@
+package serdes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"ktea/sradmin"
+	"strings"
+)
+
+// serializeJsonSchema validates the JSON value against the JSON Schema, the value is sent compacted.
+func (s *SrSerializer) serializeJsonSchema(schema sradmin.Schema, schemaId int, value string) ([]byte, error) {
+	compiled, err := s.jsonSchema.compiled.get(schemaId, func() (*jsonschema.Schema, error) {
+		return s.jsonSchema.compile(schema, schemaId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var instance interface{}
+	if err := decoder.Decode(&instance); err != nil {
+		return nil, fmt.Errorf("value is no valid JSON: %w", err)
+	}
+
+	if err := compiled.Validate(instance); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if !errors.As(err, &validationErr) {
+			return nil, err
+		}
+		return nil, FieldErrors(violations(validationErr))
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, []byte(value)); err != nil {
+		return nil, err
+	}
+	return compacted.Bytes(), nil
+}
@@ -0,0 +1,128 @@
+package serdes
+
+import (
+	"encoding/binary"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"ktea/sradmin"
+	"testing"
+)
+
+const addressJsonSchema = `{
+  "type": "object",
+  "properties": {
+    "street": {"type": "string"}
+  },
+  "required": ["street"]
+}`
+
+const personJsonSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "properties": {
+    "name": {"type": "string"},
+    "age": {"type": "integer", "minimum": 0},
+    "address": {"$ref": "address.json"}
+  },
+  "required": ["name"]
+}`
+
+func frameJson(schemaId int32, json string) []byte {
+	data := []byte{0x00}
+	data = binary.BigEndian.AppendUint32(data, uint32(schemaId))
+	return append(data, json...)
+}
+
+func newJsonSchemaSraMock() *sradmin.MockSrAdmin {
+	sraMock := sradmin.NewMock()
+	sraMock.GetSchemaByIdFunc = func(id int) tea.Msg {
+		return sradmin.SchemaByIdReceived{
+			Schema: sradmin.Schema{
+				Value: personJsonSchema,
+				Type:  sradmin.JsonSchemaType,
+				References: []sradmin.SchemaReference{
+					{Name: "address.json", Subject: "address", Version: 1},
+				},
+			},
+		}
+	}
+	sraMock.ListVersionsFunc = func(subject string, versions []int) tea.Msg {
+		return sradmin.SchemasListed{Schemas: []sradmin.Schema{{Value: addressJsonSchema}}}
+	}
+	return sraMock
+}
+
+func TestJsonSchemaDeserializer(t *testing.T) {
+	t.Run("deserialize valid record", func(t *testing.T) {
+		json := `{"name":"John","age":21,"address":{"street":"Main Street"}}`
+
+		res, err := NewDeserializer(newJsonSchemaSraMock()).Deserialize(frameJson(3, json))
+
+		assert.NoError(t, err)
+		assert.Equal(t, DesData{
+			Value:    json,
+			Schema:   personJsonSchema,
+			SchemaId: 3,
+		}, res)
+	})
+
+	t.Run("report violations of the record and the referenced schema", func(t *testing.T) {
+		json := `{"age":-1,"address":{}}`
+
+		res, err := NewDeserializer(newJsonSchemaSraMock()).Deserialize(frameJson(3, json))
+
+		assert.NoError(t, err)
+		assert.Equal(t, json, res.Value)
+		assert.ElementsMatch(t, []string{
+			"/: missing properties: 'name'",
+			"/age: must be >= 0 but found -1",
+			"/address: missing properties: 'street'",
+		}, res.Violations)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := NewDeserializer(newJsonSchemaSraMock()).Deserialize(frameJson(3, `{"name":`))
+
+		assert.ErrorContains(t, err, "json schema deserialization failed")
+	})
+
+	t.Run("resolve references relative to the $id of the schema", func(t *testing.T) {
+		schema := `{
+  "$id": "https://example.com/schemas/person.json",
+  "type": "object",
+  "properties": {
+    "address": {"$ref": "address.json"}
+  }
+}`
+		sraMock := newJsonSchemaSraMock()
+		sraMock.GetSchemaByIdFunc = func(id int) tea.Msg {
+			return sradmin.SchemaByIdReceived{
+				Schema: sradmin.Schema{
+					Value: schema,
+					Type:  sradmin.JsonSchemaType,
+					References: []sradmin.SchemaReference{
+						{Name: "address.json", Subject: "address", Version: 1},
+					},
+				},
+			}
+		}
+
+		res, err := NewDeserializer(sraMock).Deserialize(frameJson(4, `{"address":{}}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/address: missing properties: 'street'"}, res.Violations)
+	})
+
+	t.Run("unknown reference", func(t *testing.T) {
+		sraMock := newJsonSchemaSraMock()
+		sraMock.GetSchemaByIdFunc = func(id int) tea.Msg {
+			return sradmin.SchemaByIdReceived{
+				Schema: sradmin.Schema{Value: personJsonSchema, Type: sradmin.JsonSchemaType},
+			}
+		}
+
+		_, err := NewDeserializer(sraMock).Deserialize(frameJson(3, `{"name":"John"}`))
+
+		assert.ErrorContains(t, err, "unable to compile json schema")
+	})
+}
@@ -0,0 +1,208 @@
+package serdes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bufbuild/protocompile"
+	"github.com/linkedin/goavro/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"ktea/config"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// LocalSchemas decodes records of topics that are mapped to local schema files.
+// Schema files are loaded once, upon first use.
+type LocalSchemas struct {
+	schemas []config.LocalSchema
+	mu      sync.Mutex
+	loaded  map[int]loadedSchema
+}
+
+type loadedSchema struct {
+	deserializer Deserializer
+	err          error
+}
+
+// Deserializer returns the Deserializer of the first local schema matching the topic,
+// or false when none matches.
+func (l *LocalSchemas) Deserializer(topic string, key bool) (Deserializer, bool, error) {
+	for i := range l.schemas {
+		if l.schemas[i].Matches(topic, key) {
+			loaded := l.load(i)
+			return loaded.deserializer, true, loaded.err
+		}
+	}
+	return nil, false, nil
+}
+
+func (l *LocalSchemas) load(i int) loadedSchema {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if loaded, ok := l.loaded[i]; ok {
+		return loaded
+	}
+
+	deserializer, err := NewLocalDeserializer(l.schemas[i])
+	if err != nil {
+		err = fmt.Errorf("unable to load local schema %s: %w", l.schemas[i].File, err)
+	}
+	l.loaded[i] = loadedSchema{deserializer, err}
+	return l.loaded[i]
+}
+
+func NewLocalSchemas(schemas []config.LocalSchema) *LocalSchemas {
+	return &LocalSchemas{
+		schemas: schemas,
+		loaded:  make(map[int]loadedSchema),
+	}
+}
+
+// LocalAvroDeserializer decodes schemaless Avro data.
+type LocalAvroDeserializer struct {
+	codec  *goavro.Codec
+	schema string
+}
+
+func (d *LocalAvroDeserializer) Deserialize(data []byte) (DesData, error) {
+	if len(data) == 0 {
+		return DesData{}, nil
+	}
+
+	native, _, err := d.codec.NativeFromBinary(data)
+	if err != nil {
+		return DesData{}, err
+	}
+
+	jsonData, err := json.Marshal(native)
+	if err != nil {
+		return DesData{}, err
+	}
+	return DesData{Value: string(jsonData), Schema: d.schema}, nil
+}
+
+// LocalProtobufDeserializer decodes Protobuf data that is not framed with a schema id.
+type LocalProtobufDeserializer struct {
+	md     protoreflect.MessageDescriptor
+	schema string
+}
+
+func (d *LocalProtobufDeserializer) Deserialize(data []byte) (DesData, error) {
+	if len(data) == 0 {
+		return DesData{}, nil
+	}
+
+	jsonData, err := protoToJson(d.md, data)
+	if err != nil {
+		return DesData{}, err
+	}
+	return DesData{Value: jsonData, Schema: d.schema}, nil
+}
+
+// NewLocalDeserializer creates a Deserializer for the local schema based on the extension of its file.
+func NewLocalDeserializer(schema config.LocalSchema) (Deserializer, error) {
+	switch filepath.Ext(schema.File) {
+	case ".avsc":
+		return newLocalAvroDeserializer(schema)
+	case ".proto":
+		return newLocalProtoDeserializer(schema)
+	case ".desc", ".pb", ".protoset":
+		return newDescriptorSetDeserializer(schema)
+	default:
+		return nil, fmt.Errorf("unsupported schema file %s, expected .avsc, .proto, .desc, .pb or .protoset", schema.File)
+	}
+}
+
+func newLocalAvroDeserializer(schema config.LocalSchema) (Deserializer, error) {
+	content, err := os.ReadFile(schema.File)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := goavro.NewCodec(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalAvroDeserializer{codec, string(content)}, nil
+}
+
+func newLocalProtoDeserializer(schema config.LocalSchema) (Deserializer, error) {
+	content, err := os.ReadFile(schema.File)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: append([]string{filepath.Dir(schema.File)}, schema.ImportPaths...),
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), filepath.Base(schema.File))
+	if err != nil {
+		return nil, err
+	}
+
+	var md protoreflect.MessageDescriptor
+	if schema.Message == "" {
+		if files[0].Messages().Len() == 0 {
+			return nil, errors.New("no message defined")
+		}
+		md = files[0].Messages().Get(0)
+	} else if md, err = findMessageByName(files.AsResolver(), schema.Message); err != nil {
+		return nil, err
+	}
+
+	return &LocalProtobufDeserializer{md, string(content)}, nil
+}
+
+func newDescriptorSetDeserializer(schema config.LocalSchema) (Deserializer, error) {
+	if schema.Message == "" {
+		return nil, errors.New("message is required for descriptor sets")
+	}
+
+	content, err := os.ReadFile(schema.File)
+	if err != nil {
+		return nil, err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(content, &set); err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+
+	md, err := findMessageByName(files, schema.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalProtobufDeserializer{md: md}, nil
+}
+
+type descriptorFinder interface {
+	FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error)
+}
+
+func findMessageByName(finder descriptorFinder, name string) (protoreflect.MessageDescriptor, error) {
+	descriptor, err := finder.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("message %s not found", name)
+	}
+	md, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message", name)
+	}
+	return md, nil
+}
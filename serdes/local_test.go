@@ -0,0 +1,129 @@
+package serdes
+
+import (
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"ktea/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const orderAvroSchema = `{"type": "record", "name": "Order", "fields": [{"name": "id", "type": "int"}]}`
+
+func writeSchemaFile(t *testing.T, dir string, name string, content []byte) string {
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func marshalPerson(t *testing.T) []byte {
+	md := compileForTest(t).Messages().ByName("Person")
+	person := dynamicpb.NewMessage(md)
+	person.Set(md.Fields().ByName("name"), protoreflect.ValueOfString("John"))
+	data, err := proto.Marshal(person)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestLocalSchemas(t *testing.T) {
+	t.Run("Avro schema file", func(t *testing.T) {
+		file := writeSchemaFile(t, t.TempDir(), "order.avsc", []byte(orderAvroSchema))
+		codec, _ := goavro.NewCodec(orderAvroSchema)
+		data, _ := codec.BinaryFromNative(nil, map[string]interface{}{"id": 7})
+
+		deserializer, mapped, err := NewLocalSchemas([]config.LocalSchema{
+			{Topic: "orders-*", File: file},
+		}).Deserializer("orders-eu", false)
+
+		assert.True(t, mapped)
+		assert.NoError(t, err)
+		res, err := deserializer.Deserialize(data)
+		assert.NoError(t, err)
+		assert.Equal(t, DesData{Value: `{"id":7}`, Schema: orderAvroSchema}, res)
+	})
+
+	t.Run("Protobuf schema file with imports", func(t *testing.T) {
+		dir := t.TempDir()
+		writeSchemaFile(t, dir, "address.proto", []byte(addressSchema))
+		file := writeSchemaFile(t, dir, "person.proto", []byte(personSchema))
+
+		deserializer, mapped, err := NewLocalSchemas([]config.LocalSchema{
+			{Topic: "persons", File: file},
+		}).Deserializer("persons", false)
+
+		assert.True(t, mapped)
+		assert.NoError(t, err)
+		res, err := deserializer.Deserialize(marshalPerson(t))
+		assert.NoError(t, err)
+		assert.Equal(t, DesData{Value: `{"name":"John"}`, Schema: personSchema}, res)
+	})
+
+	t.Run("Protobuf descriptor set", func(t *testing.T) {
+		fd := compileForTest(t)
+		set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(fd.Imports().Get(0).FileDescriptor),
+			protodesc.ToFileDescriptorProto(fd),
+		}}
+		content, _ := proto.Marshal(set)
+		file := writeSchemaFile(t, t.TempDir(), "persons.desc", content)
+
+		deserializer, mapped, err := NewLocalSchemas([]config.LocalSchema{
+			{Topic: "persons", File: file, Message: "ktea.test.Person"},
+		}).Deserializer("persons", false)
+
+		assert.True(t, mapped)
+		assert.NoError(t, err)
+		res, err := deserializer.Deserialize(marshalPerson(t))
+		assert.NoError(t, err)
+		assert.Equal(t, DesData{Value: `{"name":"John"}`}, res)
+	})
+
+	t.Run("Descriptor set without message", func(t *testing.T) {
+		_, mapped, err := NewLocalSchemas([]config.LocalSchema{
+			{Topic: "persons", File: "persons.desc"},
+		}).Deserializer("persons", false)
+
+		assert.True(t, mapped)
+		assert.ErrorContains(t, err, "message is required for descriptor sets")
+	})
+
+	t.Run("Unknown message", func(t *testing.T) {
+		dir := t.TempDir()
+		writeSchemaFile(t, dir, "address.proto", []byte(addressSchema))
+		file := writeSchemaFile(t, dir, "person.proto", []byte(personSchema))
+
+		_, _, err := NewLocalSchemas([]config.LocalSchema{
+			{Topic: "persons", File: file, Message: "ktea.test.Unknown"},
+		}).Deserializer("persons", false)
+
+		assert.ErrorContains(t, err, "message ktea.test.Unknown not found")
+	})
+
+	t.Run("Unsupported schema file", func(t *testing.T) {
+		_, _, err := NewLocalSchemas([]config.LocalSchema{
+			{Topic: "orders", File: "orders.xsd"},
+		}).Deserializer("orders", false)
+
+		assert.ErrorContains(t, err, "unsupported schema file orders.xsd")
+	})
+
+	t.Run("Topic not mapped", func(t *testing.T) {
+		_, mapped, err := NewLocalSchemas([]config.LocalSchema{
+			{Topic: "orders", File: "orders.avsc"},
+			{Topic: "persons", File: "person-key.avsc", Key: true},
+		}).Deserializer("persons", false)
+
+		assert.False(t, mapped)
+		assert.NoError(t, err)
+	})
+}
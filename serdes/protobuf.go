@@ -0,0 +1,134 @@
+package serdes
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"ktea/sradmin"
+)
+
+// ProtobufDeserializer deserializes Protobuf data using the Confluent wire format:
+// a magic byte, the schema id and the indexes of the message within the .proto schema,
+// followed by the Protobuf encoded message.
+type ProtobufDeserializer struct {
+	sra   sradmin.SrAdmin
+	files schemaCache[protoreflect.FileDescriptor]
+}
+
+func (d *ProtobufDeserializer) deserialize(schema sradmin.Schema, schemaId int, data []byte) (DesData, error) {
+	indexes, payload, err := readMessageIndexes(data)
+	if err != nil {
+		return DesData{}, err
+	}
+
+	fd, err := d.files.get(schemaId, func() (protoreflect.FileDescriptor, error) {
+		return d.compile(schema, schemaId)
+	})
+	if err != nil {
+		return DesData{}, err
+	}
+
+	md, err := findMessage(fd, indexes)
+	if err != nil {
+		return DesData{}, err
+	}
+
+	jsonData, err := protoToJson(md, payload)
+	if err != nil {
+		return DesData{}, err
+	}
+
+	return DesData{Value: jsonData, Schema: schema.Value, SchemaId: schemaId}, nil
+}
+
+// protoToJson decodes the Protobuf encoded message, described by md, as JSON.
+func protoToJson(md protoreflect.MessageDescriptor, data []byte) (string, error) {
+	msg := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return "", fmt.Errorf("protobuf deserialization failed: %w", err)
+	}
+
+	jsonData, err := protojson.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	// protojson deliberately produces unstable whitespace
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, jsonData); err != nil {
+		return "", err
+	}
+
+	return compacted.String(), nil
+}
+
+// readMessageIndexes reads the zigzag encoded message indexes that precede the message.
+// A single 0 is a shorthand for the first message in the schema.
+func readMessageIndexes(data []byte) ([]int, []byte, error) {
+	reader := bytes.NewReader(data)
+
+	count, err := binary.ReadVarint(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read protobuf message indexes: %w", err)
+	}
+	if count == 0 {
+		return []int{0}, data[len(data)-reader.Len():], nil
+	}
+	if count < 0 || count > int64(len(data)) {
+		return nil, nil, fmt.Errorf("invalid number of protobuf message indexes: %d", count)
+	}
+
+	indexes := make([]int, count)
+	for i := range indexes {
+		index, err := binary.ReadVarint(reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to read protobuf message indexes: %w", err)
+		}
+		indexes[i] = int(index)
+	}
+
+	return indexes, data[len(data)-reader.Len():], nil
+}
+
+func findMessage(fd protoreflect.FileDescriptor, indexes []int) (protoreflect.MessageDescriptor, error) {
+	var (
+		md       protoreflect.MessageDescriptor
+		messages = fd.Messages()
+	)
+	for _, index := range indexes {
+		if index < 0 || index >= messages.Len() {
+			return nil, fmt.Errorf("message index %d not found in %s", index, fd.Path())
+		}
+		md = messages.Get(index)
+		messages = md.Messages()
+	}
+	return md, nil
+}
+
+// compile compiles the schema, together with all the schemas it references.
+func (d *ProtobufDeserializer) compile(schema sradmin.Schema, schemaId int) (protoreflect.FileDescriptor, error) {
+	path := fmt.Sprintf("schema-%d.proto", schemaId)
+	sources := map[string]string{path: schema.Value}
+	if err := resolveReferences(d.sra, schema.References, sources); err != nil {
+		return nil, err
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile protobuf schema: %w", err)
+	}
+
+	return files[0], nil
+}
@@ -0,0 +1,137 @@
+package serdes
+
+import (
+	"encoding/json"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+type protobufGenerator struct {
+	md       protoreflect.MessageDescriptor
+	indexes  []int
+	schemaId int
+}
+
+func (g *protobufGenerator) Generate() ([]byte, error) {
+	encoded, err := proto.Marshal(protobufMessage(g.md, randomValues{}, 0))
+	if err != nil {
+		return nil, err
+	}
+	return wireFormat(g.schemaId, append(writeMessageIndexes(g.indexes), encoded...)), nil
+}
+
+// protobufSkeleton is the message in the JSON form protojson reads, with the fields in the order of the message.
+func protobufSkeleton(md protoreflect.MessageDescriptor) (any, error) {
+	encoded, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(protobufMessage(md, placeholderValues{}, 0))
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(encoded), nil
+}
+
+// protobufMessage sets all fields, but a single field of each oneof. Fields holding messages beyond
+// maxGeneratedDepth are left out, oneofs choose another field if they can.
+func protobufMessage(md protoreflect.MessageDescriptor, values valueStrategy, depth int) *dynamicpb.Message {
+	msg := dynamicpb.NewMessage(md)
+	if unresolvable(md) {
+		return msg
+	}
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if oneof := fd.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			// the first field of the oneof chooses one of its fields
+			if oneof.Fields().Get(0) != fd {
+				continue
+			}
+			fd = oneofField(oneof, values, depth)
+		}
+		if depth+1 >= maxGeneratedDepth && holdsMessages(fd) {
+			continue
+		}
+
+		switch {
+		case fd.IsMap():
+			entries := msg.Mutable(fd).Map()
+			for n := values.count(depth); n > 0; n-- {
+				key := protobufScalar(fd.MapKey(), values).MapKey()
+				if fd.MapKey().Kind() == protoreflect.StringKind {
+					key = protoreflect.ValueOfString(values.key()).MapKey()
+				}
+				entries.Set(key, protobufField(fd.MapValue(), values, depth))
+			}
+		case fd.IsList():
+			items := msg.Mutable(fd).List()
+			for n := values.count(depth); n > 0; n-- {
+				items.Append(protobufField(fd, values, depth))
+			}
+		default:
+			msg.Set(fd, protobufField(fd, values, depth))
+		}
+	}
+	return msg
+}
+
+func protobufField(fd protoreflect.FieldDescriptor, values valueStrategy, depth int) protoreflect.Value {
+	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+		return protoreflect.ValueOfMessage(protobufMessage(fd.Message(), values, depth+1))
+	}
+	return protobufScalar(fd, values)
+}
+
+func protobufScalar(fd protoreflect.FieldDescriptor, values valueStrategy) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(values.boolean())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(values.integer(0, 999)))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(values.integer(0, 999))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(values.integer(0, 999)))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(uint64(values.integer(0, 999)))
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(float32(values.integer(0, 99999)) / 100)
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(float64(values.integer(0, 99999)) / 100)
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(values.text(0, 8))
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(values.text(0, 8)))
+	case protoreflect.EnumKind:
+		enumValues := fd.Enum().Values()
+		return protoreflect.ValueOfEnum(enumValues.Get(values.pick(enumValues.Len())).Number())
+	default:
+		return fd.Default()
+	}
+}
+
+func oneofField(oneof protoreflect.OneofDescriptor, values valueStrategy, depth int) protoreflect.FieldDescriptor {
+	fields := oneof.Fields()
+	fd := fields.Get(values.pick(fields.Len()))
+	if depth+1 >= maxGeneratedDepth && holdsMessages(fd) {
+		for i := 0; i < fields.Len(); i++ {
+			if !holdsMessages(fields.Get(i)) {
+				return fields.Get(i)
+			}
+		}
+	}
+	return fd
+}
+
+func holdsMessages(fd protoreflect.FieldDescriptor) bool {
+	if fd.IsMap() {
+		fd = fd.MapValue()
+	}
+	return fd.Message() != nil
+}
+
+// unresolvable reports whether the JSON form of the message refers to paths or types that generated values
+// can not resolve, these are left empty.
+func unresolvable(md protoreflect.MessageDescriptor) bool {
+	return md.FullName() == "google.protobuf.FieldMask" || md.FullName() == "google.protobuf.Any"
+}
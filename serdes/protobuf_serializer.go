@@ -0,0 +1,81 @@
+package serdes
+
+import (
+	"encoding/binary"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"ktea/sradmin"
+)
+
+// serializeProtobuf encodes the JSON value as a message of the Protobuf schema, preceded by the indexes
+// of the message within the schema. The first message of the schema is used when no message name is given.
+func (s *SrSerializer) serializeProtobuf(schema sradmin.Schema, schemaId int, messageName string, value string) ([]byte, error) {
+	fd, err := s.protobuf.files.get(schemaId, func() (protoreflect.FileDescriptor, error) {
+		return s.protobuf.compile(schema, schemaId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	md, indexes, err := messageIndexes(fd, messageName)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := dynamicpb.NewMessage(md)
+	if err := protojson.Unmarshal([]byte(value), msg); err != nil {
+		return nil, FieldErrors{fmt.Sprintf("%s: %v", md.FullName(), err)}
+	}
+	encoded, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(writeMessageIndexes(indexes), encoded...), nil
+}
+
+// messageIndexes finds the message by its full or short name, together with the indexes leading to it.
+func messageIndexes(fd protoreflect.FileDescriptor, name string) (protoreflect.MessageDescriptor, []int, error) {
+	if name == "" {
+		if fd.Messages().Len() == 0 {
+			return nil, nil, fmt.Errorf("no message found in %s", fd.Path())
+		}
+		return fd.Messages().Get(0), []int{0}, nil
+	}
+
+	var find func(messages protoreflect.MessageDescriptors, indexes []int) (protoreflect.MessageDescriptor, []int)
+	find = func(messages protoreflect.MessageDescriptors, indexes []int) (protoreflect.MessageDescriptor, []int) {
+		for i := 0; i < messages.Len(); i++ {
+			md := messages.Get(i)
+			path := append(append([]int{}, indexes...), i)
+			if string(md.FullName()) == name || string(md.Name()) == name {
+				return md, path
+			}
+			if nested, nestedPath := find(md.Messages(), path); nested != nil {
+				return nested, nestedPath
+			}
+		}
+		return nil, nil
+	}
+
+	md, indexes := find(fd.Messages(), nil)
+	if md == nil {
+		return nil, nil, fmt.Errorf("message %s not found in the schema", name)
+	}
+	return md, indexes, nil
+}
+
+// writeMessageIndexes zigzag encodes the message indexes, a single 0 is the shorthand for the first message.
+func writeMessageIndexes(indexes []int) []byte {
+	if len(indexes) == 1 && indexes[0] == 0 {
+		return binary.AppendVarint(nil, 0)
+	}
+	data := binary.AppendVarint(nil, int64(len(indexes)))
+	for _, index := range indexes {
+		data = binary.AppendVarint(data, int64(index))
+	}
+	return data
+}
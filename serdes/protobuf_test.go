@@ -0,0 +1,149 @@
+package serdes
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/bufbuild/protocompile"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"ktea/sradmin"
+	"testing"
+)
+
+const addressSchema = `
+syntax = "proto3";
+package ktea.test;
+
+message Address {
+  string street = 1;
+}
+`
+
+const personSchema = `
+syntax = "proto3";
+package ktea.test;
+
+import "address.proto";
+
+message Person {
+  string name = 1;
+  int32 age = 2;
+  Address address = 3;
+}
+
+message Envelope {
+  message Event {
+    string type = 1;
+  }
+}
+`
+
+func compileForTest(t *testing.T) protoreflect.FileDescriptor {
+	compiler := protocompile.Compiler{
+		Resolver: &protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{
+				"person.proto":  personSchema,
+				"address.proto": addressSchema,
+			}),
+		},
+	}
+	files, err := compiler.Compile(context.Background(), "person.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files[0]
+}
+
+// frame prefixes the message with the magic byte, schema id and the zigzag encoded message indexes.
+func frame(t *testing.T, schemaId int32, indexes []int64, msg proto.Message) []byte {
+	data := []byte{0x00}
+	data = binary.BigEndian.AppendUint32(data, uint32(schemaId))
+	if len(indexes) == 1 && indexes[0] == 0 {
+		data = binary.AppendVarint(data, 0)
+	} else {
+		data = binary.AppendVarint(data, int64(len(indexes)))
+		for _, index := range indexes {
+			data = binary.AppendVarint(data, index)
+		}
+	}
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(data, payload...)
+}
+
+func newProtobufSraMock() *sradmin.MockSrAdmin {
+	sraMock := sradmin.NewMock()
+	sraMock.GetSchemaByIdFunc = func(id int) tea.Msg {
+		return sradmin.SchemaByIdReceived{
+			Schema: sradmin.Schema{
+				Value: personSchema,
+				Type:  sradmin.ProtobufSchemaType,
+				References: []sradmin.SchemaReference{
+					{Name: "address.proto", Subject: "address-value", Version: 3},
+				},
+			},
+		}
+	}
+	sraMock.ListVersionsFunc = func(subject string, versions []int) tea.Msg {
+		if subject == "address-value" && versions[0] == 3 {
+			return sradmin.SchemasListed{Schemas: []sradmin.Schema{{Value: addressSchema}}}
+		}
+		return sradmin.SchemasListed{}
+	}
+	return sraMock
+}
+
+func TestProtobufDeserializer(t *testing.T) {
+	fd := compileForTest(t)
+
+	t.Run("deserialize first message with a referenced schema", func(t *testing.T) {
+		person := dynamicpb.NewMessage(fd.Messages().ByName("Person"))
+		person.Set(person.Descriptor().Fields().ByName("name"), protoreflect.ValueOfString("John"))
+		person.Set(person.Descriptor().Fields().ByName("age"), protoreflect.ValueOfInt32(21))
+		address := dynamicpb.NewMessage(fd.Messages().ByName("Person").Fields().ByName("address").Message())
+		address.Set(address.Descriptor().Fields().ByName("street"), protoreflect.ValueOfString("Main Street"))
+		person.Set(person.Descriptor().Fields().ByName("address"), protoreflect.ValueOfMessage(address))
+
+		res, err := NewDeserializer(newProtobufSraMock()).Deserialize(frame(t, 7, []int64{0}, person))
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"John","age":21,"address":{"street":"Main Street"}}`, res.Value)
+		assert.Equal(t, personSchema, res.Schema)
+		assert.Equal(t, 7, res.SchemaId)
+	})
+
+	t.Run("deserialize nested message by index", func(t *testing.T) {
+		event := dynamicpb.NewMessage(fd.Messages().ByName("Envelope").Messages().ByName("Event"))
+		event.Set(event.Descriptor().Fields().ByName("type"), protoreflect.ValueOfString("created"))
+
+		res, err := NewDeserializer(newProtobufSraMock()).Deserialize(frame(t, 7, []int64{1, 0}, event))
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{"type":"created"}`, res.Value)
+	})
+
+	t.Run("unknown message index", func(t *testing.T) {
+		event := dynamicpb.NewMessage(fd.Messages().ByName("Envelope").Messages().ByName("Event"))
+
+		_, err := NewDeserializer(newProtobufSraMock()).Deserialize(frame(t, 7, []int64{5}, event))
+
+		assert.ErrorContains(t, err, "message index 5 not found")
+	})
+
+	t.Run("unresolvable reference", func(t *testing.T) {
+		sraMock := newProtobufSraMock()
+		sraMock.ListVersionsFunc = func(string, []int) tea.Msg {
+			return sradmin.SchemasListed{}
+		}
+		person := dynamicpb.NewMessage(fd.Messages().ByName("Person"))
+
+		_, err := NewDeserializer(sraMock).Deserialize(frame(t, 7, []int64{0}, person))
+
+		assert.EqualError(t, err, "unable to resolve reference address.proto: version 3 of subject address-value not found")
+	})
+}
@@ -1,5 +1,139 @@
 package serdes
 
+import (
+	"fmt"
+	"github.com/linkedin/goavro/v2"
+	"ktea/sradmin"
+)
+
 type Deserializer interface {
 	Deserialize(data []byte) (DesData, error)
 }
+
+// SrDeserializer deserializes data framed with a Schema Registry schema id
+// based on the type of the schema, data without a schema id is returned as is.
+// Schemas and codecs are cached per schema id, it is safe for concurrent use.
+type SrDeserializer struct {
+	sra        sradmin.SrAdmin
+	schemas    schemaCache[sradmin.Schema]
+	avroCodecs schemaCache[*goavro.Codec]
+	protobuf   *ProtobufDeserializer
+	jsonSchema *JsonSchemaDeserializer
+}
+
+func (d *SrDeserializer) Deserialize(data []byte) (DesData, error) {
+	if len(data) == 0 {
+		return DesData{}, nil
+	}
+
+	schemaId, hasSchemaId := isAvroWithSchemaID(data)
+	if !hasSchemaId {
+		return DesData{Value: string(data)}, nil
+	}
+
+	if d.sra == nil {
+		return DesData{}, fmt.Errorf("deserialization failed: %w", ErrNoSchemaRegistry)
+	}
+
+	schema, err := d.schemas.get(schemaId, func() (sradmin.Schema, error) {
+		return fetchSchema(d.sra, schemaId)
+	})
+	if err != nil {
+		return DesData{}, err
+	}
+
+	switch schema.Type {
+	case sradmin.ProtobufSchemaType:
+		return d.protobuf.deserialize(schema, schemaId, data[5:])
+	case sradmin.JsonSchemaType:
+		return d.jsonSchema.deserialize(schema, schemaId, data[5:])
+	default:
+		codec, err := d.avroCodecs.get(schemaId, func() (*goavro.Codec, error) {
+			return goavro.NewCodec(schema.Value)
+		})
+		if err != nil {
+			return DesData{}, err
+		}
+		return deserializeAvro(codec, schema, schemaId, data[5:])
+	}
+}
+
+func fetchSchema(sra sradmin.SrAdmin, schemaId int) (sradmin.Schema, error) {
+	var schema sradmin.Schema
+
+	switch msg := sra.GetSchemaById(schemaId).(type) {
+
+	case sradmin.GettingSchemaByIdMsg:
+		{
+			switch msg := msg.AwaitCompletion().(type) {
+
+			case sradmin.SchemaByIdReceived:
+				{
+					schema = msg.Schema
+				}
+			case sradmin.FailedToFetchLatestSchemaBySubject:
+				{
+					return sradmin.Schema{}, msg.Err
+				}
+			}
+		}
+
+	case sradmin.SchemaByIdReceived:
+		{
+			schema = msg.Schema
+		}
+	}
+
+	return schema, nil
+}
+
+// resolveReferences fetches all referenced schemas, recursively, into sources by the name they are referenced by.
+func resolveReferences(sra sradmin.SrAdmin, refs []sradmin.SchemaReference, sources map[string]string) error {
+	for _, ref := range refs {
+		if _, resolved := sources[ref.Name]; resolved {
+			continue
+		}
+
+		schema, err := fetchReference(sra, ref)
+		if err != nil {
+			return fmt.Errorf("unable to resolve reference %s: %w", ref.Name, err)
+		}
+		sources[ref.Name] = schema.Value
+
+		if err := resolveReferences(sra, schema.References, sources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fetchReference(sra sradmin.SrAdmin, ref sradmin.SchemaReference) (sradmin.Schema, error) {
+	var listed sradmin.SchemasListed
+
+	switch msg := sra.ListVersions(ref.Subject, []int{ref.Version}).(type) {
+	case sradmin.SchemaListingStarted:
+		listed, _ = msg.AwaitCompletion().(sradmin.SchemasListed)
+	case sradmin.SchemasListed:
+		listed = msg
+	}
+
+	if len(listed.Schemas) == 0 {
+		return sradmin.Schema{}, fmt.Errorf("version %d of subject %s not found", ref.Version, ref.Subject)
+	}
+	if listed.Schemas[0].Err != nil {
+		return sradmin.Schema{}, listed.Schemas[0].Err
+	}
+	return listed.Schemas[0], nil
+}
+
+func NewDeserializer(sra sradmin.SrAdmin) Deserializer {
+	return newSrDeserializer(sra)
+}
+
+func newSrDeserializer(sra sradmin.SrAdmin) *SrDeserializer {
+	return &SrDeserializer{
+		sra:        sra,
+		protobuf:   &ProtobufDeserializer{sra: sra},
+		jsonSchema: &JsonSchemaDeserializer{sra: sra},
+	}
+}
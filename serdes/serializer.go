@@ -0,0 +1,111 @@
+package serdes
+
+import (
+	"encoding/binary"
+	"fmt"
+	"ktea/sradmin"
+	"strconv"
+	"strings"
+)
+
+// LatestVersion serializes with the latest version of the subject.
+const LatestVersion = 0
+
+// SchemaSelection selects the registered schema a value is serialized with.
+type SchemaSelection struct {
+	Subject string
+	// Version of the subject, LatestVersion for the latest one
+	Version int
+	// Message is the full or short name of the Protobuf message, the first message of the schema when empty
+	Message string
+}
+
+// Serializer serializes a JSON value with the selected Avro, Protobuf or JSON Schema,
+// into the Schema Registry wire format: a magic byte, the 4 byte schema id and the encoded value.
+type Serializer interface {
+	Serialize(selection SchemaSelection, value string) ([]byte, error)
+	// Skeleton is a JSON value with every field of the selected schema, to fill in and serialize
+	Skeleton(selection SchemaSelection) (string, error)
+}
+
+// FieldErrors are the fields of a value that do not match the schema, prefixed with their JSON path.
+type FieldErrors []string
+
+func (e FieldErrors) Error() string {
+	return "value does not match the schema: " + strings.Join(e, ", ")
+}
+
+type SrSerializer struct {
+	sra sradmin.SrAdmin
+	// schemas are compiled, and cached, like they are when deserializing
+	protobuf   *ProtobufDeserializer
+	jsonSchema *JsonSchemaDeserializer
+}
+
+func (s *SrSerializer) Serialize(selection SchemaSelection, value string) ([]byte, error) {
+	if s.sra == nil {
+		return nil, fmt.Errorf("serialization failed: %w", ErrNoSchemaRegistry)
+	}
+
+	schema, err := fetchSubjectSchema(s.sra, selection.Subject, selection.Version)
+	if err != nil {
+		return nil, err
+	}
+	schemaId, err := strconv.Atoi(schema.Id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema id %q: %w", schema.Id, err)
+	}
+
+	var encoded []byte
+	switch schema.Type {
+	case sradmin.AvroSchemaType, "":
+		encoded, err = serializeAvro(schema.Value, value)
+	case sradmin.ProtobufSchemaType:
+		encoded, err = s.serializeProtobuf(schema, schemaId, selection.Message, value)
+	case sradmin.JsonSchemaType:
+		encoded, err = s.serializeJsonSchema(schema, schemaId, value)
+	default:
+		return nil, fmt.Errorf("serializing %s schemas is not supported", schema.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return wireFormat(schemaId, encoded), nil
+}
+
+// fetchSubjectSchema fetches a version of the schema of the subject, or the latest one.
+func fetchSubjectSchema(sra sradmin.SrAdmin, subject string, version int) (sradmin.Schema, error) {
+	if version != LatestVersion {
+		return fetchReference(sra, sradmin.SchemaReference{Subject: subject, Version: version})
+	}
+
+	switch msg := sra.GetLatestSchemaBySubject(subject).(type) {
+	case sradmin.FetchingLatestSchemaBySubjectMsg:
+		switch msg := msg.AwaitCompletion().(type) {
+		case sradmin.LatestSchemaBySubjectReceived:
+			return msg.Schema, nil
+		case sradmin.FailedToFetchLatestSchemaBySubject:
+			return sradmin.Schema{}, msg.Err
+		}
+	case sradmin.LatestSchemaBySubjectReceived:
+		return msg.Schema, nil
+	case sradmin.FailedToFetchLatestSchemaBySubject:
+		return sradmin.Schema{}, msg.Err
+	}
+	return sradmin.Schema{}, fmt.Errorf("no schema found for subject %s", subject)
+}
+
+// wireFormat prefixes the encoded value with the magic byte and the schema id.
+func wireFormat(schemaId int, encoded []byte) []byte {
+	framed := make([]byte, 5, 5+len(encoded))
+	binary.BigEndian.PutUint32(framed[1:5], uint32(schemaId))
+	return append(framed, encoded...)
+}
+
+func NewSerializer(sra sradmin.SrAdmin) Serializer {
+	return &SrSerializer{
+		sra:        sra,
+		protobuf:   &ProtobufDeserializer{sra: sra},
+		jsonSchema: &JsonSchemaDeserializer{sra: sra},
+	}
+}
@@ -0,0 +1,226 @@
+package serdes
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"ktea/sradmin"
+	"testing"
+)
+
+func TestSerializer(t *testing.T) {
+	schema := `
+{
+   "type" : "record",
+   "namespace" : "ktea.test",
+   "name" : "Person",
+   "fields" : [
+      { "name" : "Name" , "type" : "string" },
+      { "name" : "Age" , "type" : "int" },
+      { "name" : "Email" , "type" : ["null", "string"], "default": null },
+      { "name" : "Status" , "type" : { "type": "enum", "name": "Status", "symbols": ["ACTIVE", "INACTIVE"] } },
+      { "name" : "Address" , "type" : ["null", { "type": "record", "name": "Address", "fields": [
+         { "name": "City", "type": "string" }
+      ]}], "default": null },
+      { "name" : "Tags" , "type" : { "type": "array", "items": "string" }, "default": [] }
+   ]
+}
+`
+	newSraMock := func() *sradmin.MockSrAdmin {
+		sraMock := sradmin.NewMock()
+		sraMock.GetLatestSchemaBySubjectFunc = func(subject string) tea.Msg {
+			return sradmin.LatestSchemaBySubjectReceived{
+				Schema: sradmin.Schema{Id: "7", Value: schema, Version: 2, Type: sradmin.AvroSchemaType},
+			}
+		}
+		return sraMock
+	}
+
+	t.Run("serialize with the latest version of the subject", func(t *testing.T) {
+		var requestedSubject string
+		sraMock := newSraMock()
+		latest := sraMock.GetLatestSchemaBySubjectFunc
+		sraMock.GetLatestSchemaBySubjectFunc = func(subject string) tea.Msg {
+			requestedSubject = subject
+			return latest(subject)
+		}
+
+		data, err := NewSerializer(sraMock).Serialize(
+			SchemaSelection{Subject: "person-value"},
+			`{"Name":"John","Age":21,"Email":{"string":"john@ktea.io"},"Status":"ACTIVE","Address":{"ktea.test.Address":{"City":"Ghent"}}}`,
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "person-value", requestedSubject)
+		assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x07}, data[:5])
+
+		codec, _ := goavro.NewCodec(schema)
+		native, _, err := codec.NativeFromBinary(data[5:])
+		assert.NoError(t, err)
+		assert.Equal(t, "John", native.(map[string]any)["Name"])
+		assert.Equal(t, int32(21), native.(map[string]any)["Age"])
+	})
+
+	t.Run("serialize with a version of the subject", func(t *testing.T) {
+		var requestedVersions []int
+		sraMock := sradmin.NewMock()
+		sraMock.ListVersionsFunc = func(subject string, versions []int) tea.Msg {
+			requestedVersions = versions
+			return sradmin.SchemasListed{Schemas: []sradmin.Schema{{Id: "3", Value: schema}}}
+		}
+
+		data, err := NewSerializer(sraMock).Serialize(
+			SchemaSelection{Subject: "person-value", Version: 1},
+			`{"Name":"John","Age":21,"Status":"INACTIVE"}`,
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1}, requestedVersions)
+		assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x03}, data[:5])
+	})
+
+	t.Run("report the fields not matching the schema", func(t *testing.T) {
+		_, err := NewSerializer(newSraMock()).Serialize(
+			SchemaSelection{Subject: "person-value"},
+			`{"Name":1,"Age":2147483648,"Email":"john@ktea.io","Status":"GONE","Address":{"ktea.test.Address":{}},"Tags":["a",1],"Nmae":"John"}`,
+		)
+
+		var fieldErrors FieldErrors
+		assert.ErrorAs(t, err, &fieldErrors)
+		assert.Equal(t, FieldErrors{
+			"$.Name: expected string, got number",
+			"$.Age: 2147483648 is out of range for int",
+			`$.Email: union values are written as {"<type>": value}, with type one of null, string`,
+			`$.Status: "GONE" is not a symbol of the enum`,
+			"$.Address.City: missing, the field has no default",
+			"$.Tags[1]: expected string, got number",
+			"$.Nmae: unknown field",
+		}, fieldErrors)
+	})
+
+	t.Run("report invalid JSON", func(t *testing.T) {
+		_, err := NewSerializer(newSraMock()).Serialize(SchemaSelection{Subject: "person-value"}, `{"Name":`)
+
+		assert.ErrorContains(t, err, "value is no valid JSON")
+	})
+
+	t.Run("fail without Schema Registry", func(t *testing.T) {
+		_, err := NewSerializer(nil).Serialize(SchemaSelection{Subject: "person-value"}, `{}`)
+
+		assert.ErrorIs(t, err, ErrNoSchemaRegistry)
+	})
+}
+
+func TestProtobufSerializer(t *testing.T) {
+	newSraMock := func() *sradmin.MockSrAdmin {
+		sraMock := newProtobufSraMock()
+		sraMock.GetLatestSchemaBySubjectFunc = func(subject string) tea.Msg {
+			return sradmin.LatestSchemaBySubjectReceived{
+				Schema: sradmin.Schema{
+					Id:    "7",
+					Value: personSchema,
+					Type:  sradmin.ProtobufSchemaType,
+					References: []sradmin.SchemaReference{
+						{Name: "address.proto", Subject: "address-value", Version: 3},
+					},
+				},
+			}
+		}
+		return sraMock
+	}
+
+	t.Run("serialize the first message with a referenced schema", func(t *testing.T) {
+		sraMock := newSraMock()
+
+		data, err := NewSerializer(sraMock).Serialize(
+			SchemaSelection{Subject: "person-value"},
+			`{"name":"John","age":21,"address":{"street":"Main Street"}}`,
+		)
+
+		assert.NoError(t, err)
+		// magic byte, schema id and the shorthand index of the first message
+		assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x07, 0x00}, data[:6])
+		res, err := NewDeserializer(sraMock).Deserialize(data)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"John","age":21,"address":{"street":"Main Street"}}`, res.Value)
+	})
+
+	t.Run("serialize a nested message by name", func(t *testing.T) {
+		sraMock := newSraMock()
+
+		data, err := NewSerializer(sraMock).Serialize(
+			SchemaSelection{Subject: "person-value", Message: "ktea.test.Envelope.Event"},
+			`{"type":"created"}`,
+		)
+
+		assert.NoError(t, err)
+		// two indexes, 1 and 0, zigzag encoded
+		assert.Equal(t, []byte{0x04, 0x02, 0x00}, data[5:8])
+		res, err := NewDeserializer(sraMock).Deserialize(data)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"type":"created"}`, res.Value)
+	})
+
+	t.Run("report fields not matching the message", func(t *testing.T) {
+		_, err := NewSerializer(newSraMock()).Serialize(
+			SchemaSelection{Subject: "person-value"},
+			`{"name":"John","age":"twenty"}`,
+		)
+
+		var fieldErrors FieldErrors
+		assert.ErrorAs(t, err, &fieldErrors)
+		assert.Contains(t, fieldErrors[0], "ktea.test.Person")
+		assert.Contains(t, fieldErrors[0], "age")
+	})
+
+	t.Run("unknown message", func(t *testing.T) {
+		_, err := NewSerializer(newSraMock()).Serialize(
+			SchemaSelection{Subject: "person-value", Message: "Unknown"},
+			`{}`,
+		)
+
+		assert.EqualError(t, err, "message Unknown not found in the schema")
+	})
+}
+
+func TestJsonSchemaSerializer(t *testing.T) {
+	newSraMock := func() *sradmin.MockSrAdmin {
+		sraMock := newJsonSchemaSraMock()
+		sraMock.GetLatestSchemaBySubjectFunc = func(subject string) tea.Msg {
+			return sradmin.LatestSchemaBySubjectReceived{
+				Schema: sradmin.Schema{
+					Id:    "3",
+					Value: personJsonSchema,
+					Type:  sradmin.JsonSchemaType,
+					References: []sradmin.SchemaReference{
+						{Name: "address.json", Subject: "address", Version: 1},
+					},
+				},
+			}
+		}
+		return sraMock
+	}
+
+	t.Run("serialize a valid value compacted", func(t *testing.T) {
+		data, err := NewSerializer(newSraMock()).Serialize(
+			SchemaSelection{Subject: "person-value"},
+			"{\n  \"name\": \"John\",\n  \"address\": {\"street\": \"Main Street\"}\n}",
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, frameJson(3, `{"name":"John","address":{"street":"Main Street"}}`), data)
+	})
+
+	t.Run("report violations of the value and the referenced schema", func(t *testing.T) {
+		_, err := NewSerializer(newSraMock()).Serialize(
+			SchemaSelection{Subject: "person-value"},
+			`{"age":-1,"address":{}}`,
+		)
+
+		var fieldErrors FieldErrors
+		assert.ErrorAs(t, err, &fieldErrors)
+		assert.Len(t, fieldErrors, 3)
+		assert.Contains(t, err.Error(), "/age")
+		assert.Contains(t, err.Error(), "/address")
+	})
+}
@@ -0,0 +1,99 @@
+package serdes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"ktea/sradmin"
+	"strconv"
+)
+
+// Skeleton is a JSON payload with every field of the selected schema, set to its default or a typed placeholder,
+// to be filled in and serialized with Serialize.
+func (s *SrSerializer) Skeleton(selection SchemaSelection) (string, error) {
+	if s.sra == nil {
+		return "", fmt.Errorf("skeleton failed: %w", ErrNoSchemaRegistry)
+	}
+
+	schema, err := fetchSubjectSchema(s.sra, selection.Subject, selection.Version)
+	if err != nil {
+		return "", err
+	}
+	schemaId, err := strconv.Atoi(schema.Id)
+	if err != nil {
+		return "", fmt.Errorf("invalid schema id %q: %w", schema.Id, err)
+	}
+
+	var skeleton any
+	switch schema.Type {
+	case sradmin.AvroSchemaType, "":
+		skeleton, err = avroSkeleton(schema.Value)
+	case sradmin.ProtobufSchemaType:
+		fd, err := s.protobuf.files.get(schemaId, func() (protoreflect.FileDescriptor, error) {
+			return s.protobuf.compile(schema, schemaId)
+		})
+		if err != nil {
+			return "", err
+		}
+		md, _, err := messageIndexes(fd, selection.Message)
+		if err != nil {
+			return "", err
+		}
+		skeleton, err = protobufSkeleton(md)
+		if err != nil {
+			return "", err
+		}
+	case sradmin.JsonSchemaType:
+		compiled, err := s.jsonSchema.compiled.get(schemaId, func() (*jsonschema.Schema, error) {
+			return s.jsonSchema.compile(schema, schemaId)
+		})
+		if err != nil {
+			return "", err
+		}
+		skeleton = jsonSchemaValue(compiled, placeholderValues{}, 0)
+	default:
+		return "", fmt.Errorf("skeletons of %s schemas are not supported", schema.Type)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := json.MarshalIndent(skeleton, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// skeletonObject is a JSON object that keeps its fields in the order of the schema.
+type skeletonObject []skeletonField
+
+type skeletonField struct {
+	name  string
+	value any
+}
+
+func (o skeletonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
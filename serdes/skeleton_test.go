@@ -0,0 +1,239 @@
+package serdes
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"ktea/sradmin"
+	"testing"
+)
+
+func TestSkeleton(t *testing.T) {
+	latest := func(sraMock *sradmin.MockSrAdmin, schema sradmin.Schema) *sradmin.MockSrAdmin {
+		sraMock.GetLatestSchemaBySubjectFunc = func(subject string) tea.Msg {
+			return sradmin.LatestSchemaBySubjectReceived{Schema: schema}
+		}
+		return sraMock
+	}
+
+	t.Run("Avro fields with defaults and first union branches", func(t *testing.T) {
+		schema := `
+{
+   "type" : "record",
+   "namespace" : "ktea.test",
+   "name" : "Order",
+   "fields" : [
+      { "name" : "Id" , "type" : { "type": "string", "logicalType": "uuid" } },
+      { "name" : "Amount" , "type" : "double" },
+      { "name" : "Paid" , "type" : "boolean", "default": true },
+      { "name" : "Status" , "type" : { "type": "enum", "name": "Status", "symbols": ["NEW", "PAID"] } },
+      { "name" : "Note" , "type" : ["null", "string"], "default": null },
+      { "name" : "Channel" , "type" : ["string", "null"], "default": "web" },
+      { "name" : "Customer" , "type" : [{ "type": "record", "name": "Customer", "fields": [
+         { "name": "Name", "type": "string" }
+      ]}, "null"] },
+      { "name" : "Lines" , "type" : { "type": "array", "items": { "type": "record", "name": "Line", "fields": [
+         { "name": "Sku", "type": { "type": "fixed", "name": "Sku", "size": 4 } },
+         { "name": "Quantity", "type": "int", "default": 1 }
+      ]}}},
+      { "name" : "Labels" , "type" : { "type": "map", "values": "string" } },
+      { "name" : "Parent" , "type" : ["null", "Order"], "default": null }
+   ]
+}
+`
+		sraMock := latest(sradmin.NewMock(), sradmin.Schema{Id: "7", Value: schema, Type: sradmin.AvroSchemaType})
+		serializer := NewSerializer(sraMock)
+
+		skeleton, err := serializer.Skeleton(SchemaSelection{Subject: "order-value"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{
+  "Id": "00000000-0000-0000-0000-000000000000",
+  "Amount": 0,
+  "Paid": true,
+  "Status": "NEW",
+  "Note": null,
+  "Channel": {
+    "string": "web"
+  },
+  "Customer": {
+    "ktea.test.Customer": {
+      "Name": ""
+    }
+  },
+  "Lines": [
+    {
+      "Sku": "0000",
+      "Quantity": 1
+    }
+  ],
+  "Labels": {
+    "key": ""
+  },
+  "Parent": null
+}`, skeleton)
+		_, err = serializer.Serialize(SchemaSelection{Subject: "order-value"}, skeleton)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Protobuf fields in the order of the message", func(t *testing.T) {
+		schema := `
+syntax = "proto3";
+package ktea.test;
+
+import "google/protobuf/timestamp.proto";
+
+message Order {
+  enum Status {
+    NEW = 0;
+    PAID = 1;
+  }
+  string id = 1;
+  Status status = 2;
+  google.protobuf.Timestamp created_at = 3;
+  oneof payment {
+    string card = 4;
+    string iban = 5;
+  }
+  repeated Line lines = 6;
+  map<string, int64> labels = 7;
+  optional bytes note = 8;
+}
+
+message Line {
+  string sku = 1;
+  int32 quantity = 2;
+}
+`
+		sraMock := latest(sradmin.NewMock(), sradmin.Schema{Id: "9", Value: schema, Type: sradmin.ProtobufSchemaType})
+		serializer := NewSerializer(sraMock)
+
+		skeleton, err := serializer.Skeleton(SchemaSelection{Subject: "order-value"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{
+  "id": "",
+  "status": "NEW",
+  "createdAt": "1970-01-01T00:00:00Z",
+  "card": "",
+  "lines": [
+    {
+      "sku": "",
+      "quantity": 0
+    }
+  ],
+  "labels": {
+    "key": "0"
+  },
+  "note": ""
+}`, skeleton)
+		_, err = serializer.Serialize(SchemaSelection{Subject: "order-value"}, skeleton)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Protobuf well-known types and recursive messages", func(t *testing.T) {
+		schema := `
+syntax = "proto3";
+package ktea.test;
+
+import "google/protobuf/any.proto";
+import "google/protobuf/duration.proto";
+import "google/protobuf/field_mask.proto";
+import "google/protobuf/struct.proto";
+
+message Node {
+  google.protobuf.Duration ttl = 1;
+  google.protobuf.FieldMask mask = 2;
+  google.protobuf.Any detail = 3;
+  google.protobuf.Value value = 4;
+  Node child = 5;
+}
+`
+		sraMock := latest(sradmin.NewMock(), sradmin.Schema{Id: "5", Value: schema, Type: sradmin.ProtobufSchemaType})
+		serializer := NewSerializer(sraMock)
+
+		skeleton, err := serializer.Skeleton(SchemaSelection{Subject: "node-value"})
+
+		assert.NoError(t, err)
+		assert.Contains(t, skeleton, `"ttl": "0s",
+  "mask": "",
+  "detail": {},
+  "value": null,
+  "child": {`)
+		_, err = serializer.Serialize(SchemaSelection{Subject: "node-value"}, skeleton)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Protobuf message by name", func(t *testing.T) {
+		sraMock := latest(newProtobufSraMock(), sradmin.Schema{
+			Id:    "7",
+			Value: personSchema,
+			Type:  sradmin.ProtobufSchemaType,
+			References: []sradmin.SchemaReference{
+				{Name: "address.proto", Subject: "address-value", Version: 3},
+			},
+		})
+
+		skeleton, err := NewSerializer(sraMock).Skeleton(SchemaSelection{Subject: "person-value", Message: "Event"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"type\": \"\"\n}", skeleton)
+	})
+
+	t.Run("JSON Schema properties of referenced schemas", func(t *testing.T) {
+		sraMock := latest(newJsonSchemaSraMock(), sradmin.Schema{
+			Id:    "3",
+			Value: personJsonSchema,
+			Type:  sradmin.JsonSchemaType,
+			References: []sradmin.SchemaReference{
+				{Name: "address.json", Subject: "address", Version: 1},
+			},
+		})
+		serializer := NewSerializer(sraMock)
+
+		skeleton, err := serializer.Skeleton(SchemaSelection{Subject: "person-value"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{
+  "address": {
+    "street": ""
+  },
+  "age": 0,
+  "name": ""
+}`, skeleton)
+		_, err = serializer.Serialize(SchemaSelection{Subject: "person-value"}, skeleton)
+		assert.NoError(t, err)
+	})
+
+	t.Run("JSON Schema defaults, enums, formats and bounds", func(t *testing.T) {
+		schema := `{
+  "type": "object",
+  "properties": {
+    "id": {"type": "string", "format": "uuid"},
+    "status": {"enum": ["NEW", "PAID"]},
+    "currency": {"type": "string", "default": "EUR"},
+    "quantity": {"type": "integer", "minimum": 1},
+    "note": {"type": ["null", "string"]},
+    "tags": {"type": "array", "items": {"type": "string"}, "minItems": 2}
+  }
+}`
+		sraMock := latest(sradmin.NewMock(), sradmin.Schema{Id: "4", Value: schema, Type: sradmin.JsonSchemaType})
+
+		skeleton, err := NewSerializer(sraMock).Skeleton(SchemaSelection{Subject: "order-value"})
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+  "currency": "EUR",
+  "id": "00000000-0000-0000-0000-000000000000",
+  "note": "",
+  "quantity": 1,
+  "status": "NEW",
+  "tags": ["", ""]
+}`, skeleton)
+	})
+
+	t.Run("fail without Schema Registry", func(t *testing.T) {
+		_, err := NewSerializer(nil).Skeleton(SchemaSelection{Subject: "person-value"})
+
+		assert.ErrorIs(t, err, ErrNoSchemaRegistry)
+	})
+}
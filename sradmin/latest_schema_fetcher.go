@@ -2,7 +2,6 @@ package sradmin
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
-	"strconv"
 )
 
 type LatestSchemaBySubjectFetcher interface {
@@ -50,10 +49,5 @@ func (s *DefaultSrAdmin) doGetLatestSchema(subject string, schemaChan chan Schem
 		errChan <- err
 		return
 	}
-	schemaChan <- Schema{
-		Id:      strconv.Itoa(schema.ID()),
-		Value:   schema.Schema(),
-		Version: schema.Version(),
-		Err:     nil,
-	}
+	schemaChan <- toSchema(schema)
 }
@@ -6,7 +6,10 @@ import (
 )
 
 type MockSrAdmin struct {
-	GetSchemaByIdFunc func(id int) tea.Msg
+	GetSchemaByIdFunc            func(id int) tea.Msg
+	ListVersionsFunc             func(subject string, versions []int) tea.Msg
+	ListSchemaSubjectsFunc       func(schemaId int) tea.Msg
+	GetLatestSchemaBySubjectFunc func(subject string) tea.Msg
 }
 
 type MockConnectionCheckedMsg struct {
@@ -44,11 +47,24 @@ func (m *MockSrAdmin) CreateSchema(SubjectCreationDetails) tea.Msg {
 	return nil
 }
 
-func (m *MockSrAdmin) ListVersions(string, []int) tea.Msg {
+func (m *MockSrAdmin) ListVersions(subject string, versions []int) tea.Msg {
+	if m.ListVersionsFunc != nil {
+		return m.ListVersionsFunc(subject, versions)
+	}
+	return nil
+}
+
+func (m *MockSrAdmin) ListSchemaSubjects(schemaId int) tea.Msg {
+	if m.ListSchemaSubjectsFunc != nil {
+		return m.ListSchemaSubjectsFunc(schemaId)
+	}
 	return nil
 }
 
-func (m *MockSrAdmin) GetLatestSchemaBySubject(string) tea.Msg {
+func (m *MockSrAdmin) GetLatestSchemaBySubject(subject string) tea.Msg {
+	if m.GetLatestSchemaBySubjectFunc != nil {
+		return m.GetLatestSchemaBySubjectFunc(subject)
+	}
 	return nil
 }
 
@@ -2,7 +2,6 @@ package sradmin
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
-	"strconv"
 )
 
 type SchemaFetcher interface {
@@ -53,10 +52,5 @@ func (s *DefaultSrAdmin) doGetSchemaById(id int, schemaChan chan Schema, errChan
 		errChan <- err
 		return
 	}
-	schemaChan <- Schema{
-		Id:      strconv.Itoa(schema.ID()),
-		Value:   schema.Schema(),
-		Version: schema.Version(),
-		Err:     nil,
-	}
+	schemaChan <- toSchema(schema)
 }
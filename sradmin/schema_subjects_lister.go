@@ -0,0 +1,68 @@
+package sradmin
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SchemaSubjectsLister lists the subjects a schema is registered under.
+type SchemaSubjectsLister interface {
+	ListSchemaSubjects(schemaId int) tea.Msg
+}
+
+type SubjectVersion struct {
+	Subject string
+	Version int
+}
+
+type SchemaSubjectsListingStartedMsg struct {
+	schemaId int
+	subjects chan []SubjectVersion
+	err      chan error
+}
+
+type SchemaSubjectsListedMsg struct {
+	SchemaId int
+	Subjects []SubjectVersion
+}
+
+type SchemaSubjectsListingErrMsg struct {
+	SchemaId int
+	Err      error
+}
+
+// AwaitCompletion returns
+// a SchemaSubjectsListedMsg upon success
+// or SchemaSubjectsListingErrMsg upon failure.
+func (msg *SchemaSubjectsListingStartedMsg) AwaitCompletion() tea.Msg {
+	select {
+	case subjects := <-msg.subjects:
+		return SchemaSubjectsListedMsg{SchemaId: msg.schemaId, Subjects: subjects}
+	case err := <-msg.err:
+		return SchemaSubjectsListingErrMsg{SchemaId: msg.schemaId, Err: err}
+	}
+}
+
+func (s *DefaultSrAdmin) ListSchemaSubjects(schemaId int) tea.Msg {
+	subjectsChan := make(chan []SubjectVersion)
+	errChan := make(chan error)
+
+	go s.doListSchemaSubjects(schemaId, subjectsChan, errChan)
+
+	return SchemaSubjectsListingStartedMsg{schemaId, subjectsChan, errChan}
+}
+
+func (s *DefaultSrAdmin) doListSchemaSubjects(schemaId int, subjectsChan chan []SubjectVersion, errChan chan error) {
+	maybeIntroduceLatency()
+
+	response, err := s.client.GetSubjectVersionsById(schemaId)
+	if err != nil {
+		errChan <- err
+		return
+	}
+
+	var subjects []SubjectVersion
+	for _, pair := range response {
+		subjects = append(subjects, SubjectVersion{Subject: pair.Subject, Version: pair.Version})
+	}
+	subjectsChan <- subjects
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/riferrei/srclient"
 	"ktea/config"
 	"net/http"
+	"strconv"
 	"sync"
 )
 
@@ -25,6 +26,7 @@ type SrAdmin interface {
 	GlobalCompatibilityLister
 	LatestSchemaBySubjectFetcher
 	SchemaDeleter
+	SchemaSubjectsLister
 }
 
 type ConnCheckSucceededMsg struct{}
@@ -88,6 +90,31 @@ func (s *DefaultSrAdmin) doCreateSchema(details SubjectCreationDetails, createdC
 	createdChan <- true
 }
 
+func toSchema(schema *srclient.Schema) Schema {
+	// schemas without an explicit type are Avro schemas
+	schemaType := AvroSchemaType
+	if schema.SchemaType() != nil {
+		schemaType = SchemaType(*schema.SchemaType())
+	}
+
+	var references []SchemaReference
+	for _, ref := range schema.References() {
+		references = append(references, SchemaReference{
+			Name:    ref.Name,
+			Subject: ref.Subject,
+			Version: ref.Version,
+		})
+	}
+
+	return Schema{
+		Id:         strconv.Itoa(schema.ID()),
+		Value:      schema.Schema(),
+		Version:    schema.Version(),
+		Type:       schemaType,
+		References: references,
+	}
+}
+
 func createHttpClient(registry *config.SchemaRegistryConfig) *http.Client {
 	auth := registry.Username + ":" + registry.Password
 	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
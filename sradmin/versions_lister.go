@@ -2,15 +2,32 @@ package sradmin
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
-	"strconv"
 	"sync"
 )
 
-type Schema struct {
-	Id      string
-	Value   string
+type SchemaType string
+
+const (
+	AvroSchemaType     SchemaType = "AVRO"
+	ProtobufSchemaType SchemaType = "PROTOBUF"
+	JsonSchemaType     SchemaType = "JSON"
+)
+
+// SchemaReference refers to another schema, by the name used to import it
+// (a Protobuf import or a JSON Schema $ref), and the subject and version it is registered under.
+type SchemaReference struct {
+	Name    string
+	Subject string
 	Version int
-	Err     error
+}
+
+type Schema struct {
+	Id         string
+	Value      string
+	Version    int
+	Type       SchemaType
+	References []SchemaReference
+	Err        error
 }
 
 type SchemasListed struct {
@@ -51,11 +68,7 @@ func (s *DefaultSrAdmin) ListVersions(subject string, versions []int) tea.Msg {
 			defer wg.Done()
 			schema, err := s.client.GetSchemaByVersion(subject, version)
 			if err == nil {
-				schemaChan <- Schema{
-					Id:      strconv.Itoa(schema.ID()),
-					Value:   schema.Schema(),
-					Version: version,
-				}
+				schemaChan <- toSchema(schema)
 			} else {
 				schemaChan <- Schema{
 					Err:     err,
@@ -49,6 +49,7 @@ const ColorBlurBorder = "235"
 type NotifierStyle struct {
 	Spinner lipgloss.Style
 	Success lipgloss.Style
+	Warning lipgloss.Style
 	Error   lipgloss.Style
 }
 
@@ -403,6 +404,13 @@ func init() {
 			Height(1).
 			Foreground(lipgloss.Color(ColorGreen)).
 			Bold(true)
+		Notifier.Warning = lipgloss.NewStyle().
+			MarginTop(0).
+			MarginBottom(0).
+			MarginLeft(2).
+			Height(1).
+			Foreground(lipgloss.Color(ColorOrange)).
+			Bold(true)
 		Notifier.Error = lipgloss.NewStyle().
 			MarginTop(0).
 			MarginBottom(0).
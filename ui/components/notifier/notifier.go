@@ -20,6 +20,7 @@ const (
 	Err      state = 1
 	success  state = 2
 	Spinning state = 3
+	warned   state = 4
 )
 
 type Model struct {
@@ -47,6 +48,11 @@ func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
 			wordwrap.String(m.msg, ktx.WindowWidth),
 			styles.Notifier.Success,
 		)
+	} else if m.State == warned {
+		return renderer.RenderWithStyle(
+			wordwrap.String(m.msg, ktx.WindowWidth),
+			styles.Notifier.Warning,
+		)
 	} else if m.State == Err {
 		return renderer.RenderWithStyle(
 			wordwrap.String(m.msg, ktx.WindowWidth),
@@ -114,6 +120,14 @@ func (m *Model) ShowError(error error) tea.Cmd {
 	return nil
 }
 
+func (m *Model) ShowWarningMsg(msg string, warning string) tea.Cmd {
+	m.autoHide.Store(false)
+	m.State = warned
+	m.msg = "⚠️ " + styles.FG(styles.ColorOrange).Render(msg+": ") +
+		styles.FG(styles.ColorWhite).Render(warning)
+	return nil
+}
+
 func (m *Model) ShowSuccessMsg(msg string) tea.Cmd {
 	m.autoHide.Store(false)
 	m.State = success
@@ -3,10 +3,15 @@ package ui
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/formatters"
 	"github.com/alecthomas/chroma/v2/lexers"
 	chrome_styles "github.com/alecthomas/chroma/v2/styles"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -35,3 +40,222 @@ func PrettyPrintJson(text string) string {
 
 	return builder.String()
 }
+
+// JsonObject is a decoded JSON object that keeps its keys in the order they were written.
+type JsonObject struct {
+	Keys   []string
+	Values map[string]any
+}
+
+// DecodeJson decodes text holding a single JSON value. Objects are decoded as JsonObject and numbers
+// as json.Number, so they are rendered again in the order and the form in which they were written.
+func DecodeJson(text string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	value, err := decodeJsonValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+func decodeJsonValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	if delim == '[' {
+		array := []any{}
+		for decoder.More() {
+			value, err := decodeJsonValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err := decoder.Token()
+		return array, err
+	}
+
+	object := JsonObject{Values: make(map[string]any)}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+		value, err := decodeJsonValue(decoder)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := object.Values[key]; !ok {
+			object.Keys = append(object.Keys, key)
+		}
+		object.Values[key] = value
+	}
+	_, err = decoder.Token()
+	return object, err
+}
+
+// EncodeJson renders a value decoded by DecodeJson as compact JSON.
+func EncodeJson(value any) string {
+	switch value := value.(type) {
+	case JsonObject:
+		parts := make([]string, len(value.Keys))
+		for i, key := range value.Keys {
+			parts[i] = QuoteJson(key) + ":" + EncodeJson(value.Values[key])
+		}
+		return "{" + strings.Join(parts, ",") + "}"
+	case []any:
+		parts := make([]string, len(value))
+		for i, element := range value {
+			parts[i] = EncodeJson(element)
+		}
+		return "[" + strings.Join(parts, ",") + "]"
+	case string:
+		return QuoteJson(value)
+	case json.Number:
+		return value.String()
+	default:
+		encoded, _ := json.Marshal(value)
+		return string(encoded)
+	}
+}
+
+// QuoteJson quotes s as a JSON string, leaving <, > and & as they are.
+func QuoteJson(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	// strings always encode
+	_ = encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// identifier matches keys that can be written as .key in a JSON path.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// JsonFieldPath is the JSON path of the field key of the object at path, like $.order or $["order-id"].
+func JsonFieldPath(path, key string) string {
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "[" + QuoteJson(key) + "]"
+}
+
+// JsonIndexPath is the JSON path of the element at index of the array at path, like $.items[0].
+func JsonIndexPath(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
+
+// JsonPathSegment is a field of an object, or an index of an array when IsIndex.
+type JsonPathSegment struct {
+	Field   string
+	Index   int
+	IsIndex bool
+}
+
+// ParseJsonPath parses the paths JsonFieldPath and JsonIndexPath write, like $.order.items[0].status
+// or $["order-id"]. Fields in brackets can also be single quoted, like $['order-id'].
+func ParseJsonPath(path string) ([]JsonPathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid JSON path %q: expected $ at the start", path)
+	}
+	var segments []JsonPathSegment
+	rest := path[1:]
+	for rest != "" {
+		switch {
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			field := rest[1 : end+1]
+			if field == "" {
+				return nil, fmt.Errorf("invalid JSON path %q: empty field name", path)
+			}
+			segments = append(segments, JsonPathSegment{Field: field})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, `["`), strings.HasPrefix(rest, `['`):
+			field, n, err := quotedField(rest[1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON path %q: %w", path, err)
+			}
+			rest = rest[1+n:]
+			if !strings.HasPrefix(rest, "]") {
+				return nil, fmt.Errorf("invalid JSON path %q: missing ]", path)
+			}
+			segments = append(segments, JsonPathSegment{Field: field})
+			rest = rest[1:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid JSON path %q: missing ]", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid JSON path %q: invalid index %s", path, rest[1:end])
+			}
+			segments = append(segments, JsonPathSegment{Index: index, IsIndex: true})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid JSON path %q: expected . or [ but got %c", path, rest[0])
+		}
+	}
+	return segments, nil
+}
+
+// quotedField unquotes the field name that s starts with, double quoted names are JSON strings.
+// It also returns the length of the quoted name.
+func quotedField(s string) (string, int, error) {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			if quote == '\'' {
+				return s[1:i], i + 1, nil
+			}
+			var field string
+			if err := json.Unmarshal([]byte(s[:i+1]), &field); err != nil {
+				return "", 0, fmt.Errorf("invalid field name %s", s[:i+1])
+			}
+			return field, i + 1, nil
+		}
+	}
+	return "", 0, fmt.Errorf("missing closing %c", quote)
+}
+
+// JsonPathValue is the value at the path of a value decoded by DecodeJson.
+func JsonPathValue(value any, path []JsonPathSegment) (any, bool) {
+	for _, segment := range path {
+		if segment.IsIndex {
+			array, ok := value.([]any)
+			if !ok || segment.Index >= len(array) {
+				return nil, false
+			}
+			value = array[segment.Index]
+		} else {
+			object, ok := value.(JsonObject)
+			if !ok {
+				return nil, false
+			}
+			if value, ok = object.Values[segment.Field]; !ok {
+				return nil, false
+			}
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestJsonPath(t *testing.T) {
+	value, err := DecodeJson(`{"order":{"order-id":"o-1","items":[{"say \"hi\"":"y"}]}}`)
+	assert.NoError(t, err)
+
+	t.Run("Resolve the paths that are written", func(t *testing.T) {
+		for path, expected := range map[string]any{
+			JsonFieldPath(JsonFieldPath("$", "order"), "order-id"):                                           "o-1",
+			JsonFieldPath(JsonIndexPath(JsonFieldPath(JsonFieldPath("$", "order"), "items"), 0), `say "hi"`): "y",
+		} {
+			segments, err := ParseJsonPath(path)
+			assert.NoError(t, err)
+
+			resolved, ok := JsonPathValue(value, segments)
+
+			assert.True(t, ok, path)
+			assert.Equal(t, expected, resolved, path)
+		}
+	})
+
+	t.Run("Single quoted fields", func(t *testing.T) {
+		segments, err := ParseJsonPath(`$['order']['order-id']`)
+		assert.NoError(t, err)
+
+		resolved, _ := JsonPathValue(value, segments)
+
+		assert.Equal(t, "o-1", resolved)
+	})
+
+	t.Run("Nothing at missing paths", func(t *testing.T) {
+		segments, err := ParseJsonPath(`$.order.items[3]`)
+		assert.NoError(t, err)
+
+		_, ok := JsonPathValue(value, segments)
+
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,298 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"ktea/styles"
+	"strconv"
+	"strings"
+)
+
+type jsonKind int
+
+const (
+	jsonScalar jsonKind = iota
+	jsonObject
+	jsonArray
+)
+
+// JsonNode is a value within a JsonTree.
+type JsonNode struct {
+	key      string
+	index    int
+	parent   *JsonNode
+	kind     jsonKind
+	value    any
+	scalar   string
+	children []*JsonNode
+	expanded bool
+	depth    int
+}
+
+// Path returns the JSON path of the node, like $.order.items[0].
+func (n *JsonNode) Path() string {
+	if n.parent == nil {
+		return "$"
+	}
+	if n.parent.kind == jsonArray {
+		return JsonIndexPath(n.parent.Path(), n.index)
+	}
+	return JsonFieldPath(n.parent.Path(), n.key)
+}
+
+// Value returns the value of the node, strings are unquoted and objects and arrays are indented JSON.
+func (n *JsonNode) Value() string {
+	if text, ok := n.value.(string); ok {
+		return text
+	}
+	if n.kind == jsonScalar {
+		return n.scalar
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(EncodeJson(n.value)), "", "  "); err != nil {
+		return EncodeJson(n.value)
+	}
+	return indented.String()
+}
+
+// summary describes the size of objects and arrays.
+func (n *JsonNode) summary() string {
+	switch n.kind {
+	case jsonObject:
+		return fmt.Sprintf("{%d %s}", len(n.children), plural(len(n.children), "key", "keys"))
+	case jsonArray:
+		return fmt.Sprintf("[%d %s]", len(n.children), plural(len(n.children), "item", "items"))
+	default:
+		return n.scalar
+	}
+}
+
+func plural(count int, singular string, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+func (n *JsonNode) label() string {
+	if n.parent == nil {
+		return ""
+	}
+	if n.parent.kind == jsonArray {
+		return "[" + strconv.Itoa(n.index) + "]"
+	}
+	return n.key
+}
+
+func (n *JsonNode) matches(term string) bool {
+	term = strings.ToLower(term)
+	if n.parent != nil && n.parent.kind == jsonObject && strings.Contains(strings.ToLower(n.key), term) {
+		return true
+	}
+	return n.kind == jsonScalar && strings.Contains(strings.ToLower(n.scalar), term)
+}
+
+// JsonTree is a navigable tree of a JSON document whose objects and arrays can be collapsed.
+type JsonTree struct {
+	root    *JsonNode
+	visible []*JsonNode
+	cursor  int
+}
+
+// ParseJsonTree parses the JSON object or array into a fully expanded tree, keeping the order of object keys.
+func ParseJsonTree(text string) (*JsonTree, error) {
+	value, err := DecodeJson(text)
+	if err != nil {
+		return nil, err
+	}
+	root := newJsonNode(value, nil, 0)
+	if root.kind == jsonScalar {
+		return nil, errors.New("not a JSON object or array")
+	}
+
+	tree := &JsonTree{root: root}
+	tree.refresh()
+	return tree, nil
+}
+
+func newJsonNode(value any, parent *JsonNode, depth int) *JsonNode {
+	node := &JsonNode{parent: parent, depth: depth, value: value, expanded: true}
+	switch value := value.(type) {
+	case JsonObject:
+		node.kind = jsonObject
+		for i, key := range value.Keys {
+			child := newJsonNode(value.Values[key], node, depth+1)
+			child.key = key
+			child.index = i
+			node.children = append(node.children, child)
+		}
+	case []any:
+		node.kind = jsonArray
+		for i, element := range value {
+			child := newJsonNode(element, node, depth+1)
+			child.index = i
+			node.children = append(node.children, child)
+		}
+	default:
+		node.scalar = EncodeJson(value)
+	}
+	return node
+}
+
+// refresh determines the visible nodes, the ones without a collapsed ancestor.
+func (t *JsonTree) refresh() {
+	t.visible = t.visible[:0]
+	var visit func(node *JsonNode)
+	visit = func(node *JsonNode) {
+		t.visible = append(t.visible, node)
+		if node.expanded {
+			for _, child := range node.children {
+				visit(child)
+			}
+		}
+	}
+	visit(t.root)
+	t.cursor = min(t.cursor, len(t.visible)-1)
+}
+
+// Focused returns the node under the cursor.
+func (t *JsonTree) Focused() *JsonNode {
+	return t.visible[t.cursor]
+}
+
+// Cursor returns the line of the focused node.
+func (t *JsonTree) Cursor() int {
+	return t.cursor
+}
+
+func (t *JsonTree) MoveUp(lines int) {
+	t.cursor = max(t.cursor-lines, 0)
+}
+
+func (t *JsonTree) MoveDown(lines int) {
+	t.cursor = min(t.cursor+lines, len(t.visible)-1)
+}
+
+// Toggle expands or collapses the focused object or array.
+func (t *JsonTree) Toggle() {
+	node := t.Focused()
+	if node.kind == jsonScalar {
+		return
+	}
+	node.expanded = !node.expanded
+	t.refresh()
+}
+
+func (t *JsonTree) ExpandAll() {
+	focused := t.Focused()
+	t.walk(func(node *JsonNode) {
+		node.expanded = true
+	})
+	t.refresh()
+	t.focus(focused)
+}
+
+// CollapseAll collapses everything but the root.
+func (t *JsonTree) CollapseAll() {
+	t.walk(func(node *JsonNode) {
+		node.expanded = node.parent == nil
+	})
+	t.cursor = 0
+	t.refresh()
+}
+
+// Search focuses the next, or previous, node after the cursor whose key or value contains the term,
+// expanding its ancestors. It reports whether a node was found.
+func (t *JsonTree) Search(term string, forward bool) bool {
+	if term == "" {
+		return false
+	}
+
+	var nodes []*JsonNode
+	t.walk(func(node *JsonNode) {
+		nodes = append(nodes, node)
+	})
+
+	current := 0
+	for i, node := range nodes {
+		if node == t.Focused() {
+			current = i
+			break
+		}
+	}
+
+	for i := 1; i <= len(nodes); i++ {
+		idx := (current + i) % len(nodes)
+		if !forward {
+			idx = (current - i + len(nodes)) % len(nodes)
+		}
+		if nodes[idx].matches(term) {
+			for ancestor := nodes[idx].parent; ancestor != nil; ancestor = ancestor.parent {
+				ancestor.expanded = true
+			}
+			t.refresh()
+			t.focus(nodes[idx])
+			return true
+		}
+	}
+	return false
+}
+
+func (t *JsonTree) focus(node *JsonNode) {
+	for i, visible := range t.visible {
+		if visible == node {
+			t.cursor = i
+			return
+		}
+	}
+}
+
+// walk visits all nodes in document order.
+func (t *JsonTree) walk(visit func(node *JsonNode)) {
+	var walk func(node *JsonNode)
+	walk = func(node *JsonNode) {
+		visit(node)
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	walk(t.root)
+}
+
+// Render renders the visible nodes, one per line, highlighting the focused node.
+func (t *JsonTree) Render(width int) string {
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(styles.ColorPink))
+	summaryStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(styles.ColorGrey))
+	cursorStyle := lipgloss.NewStyle().Reverse(true)
+
+	lines := make([]string, len(t.visible))
+	for i, node := range t.visible {
+		line := strings.Repeat("  ", node.depth)
+		switch {
+		case node.kind == jsonScalar:
+			line += "  "
+		case node.expanded:
+			line += "▼ "
+		default:
+			line += "▶ "
+		}
+		if label := node.label(); label != "" {
+			line += keyStyle.Render(label) + ": "
+		}
+		if node.kind == jsonScalar {
+			line += node.scalar
+		} else {
+			line += summaryStyle.Render(node.summary())
+		}
+		line = ansi.Truncate(line, width, "…")
+		if i == t.cursor {
+			line = cursorStyle.Render(ansi.Strip(line))
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
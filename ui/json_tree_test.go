@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"encoding/json"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const order = `{"id":"o-1","order":{"status":"SHIPPED","total":12.50,"items":[{"sku":"a1"},{"sku":"b2"}]},"my key":null}`
+
+func TestJsonTree(t *testing.T) {
+	t.Run("Render objects and arrays with their size, keeping the key order", func(t *testing.T) {
+		tree, err := ParseJsonTree(order)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"▼ {3 keys}",
+			`    id: "o-1"`,
+			"  ▼ order: {3 keys}",
+			`      status: "SHIPPED"`,
+			"      total: 12.50",
+			"    ▼ items: [2 items]",
+			"      ▼ [0]: {1 key}",
+			`          sku: "a1"`,
+			"      ▼ [1]: {1 key}",
+			`          sku: "b2"`,
+			"    my key: null",
+		}, lines(tree))
+	})
+
+	t.Run("Collapse and expand", func(t *testing.T) {
+		tree, _ := ParseJsonTree(order)
+
+		tree.MoveDown(2)
+		tree.Toggle()
+
+		assert.Equal(t, []string{
+			"▼ {3 keys}",
+			`    id: "o-1"`,
+			"  ▶ order: {3 keys}",
+			"    my key: null",
+		}, lines(tree))
+
+		tree.CollapseAll()
+		assert.Equal(t, []string{"▼ {3 keys}", `    id: "o-1"`, "  ▶ order: {3 keys}", "    my key: null"}, lines(tree))
+
+		tree.ExpandAll()
+		assert.Len(t, lines(tree), 11)
+	})
+
+	t.Run("Search expands the matching node", func(t *testing.T) {
+		tree, _ := ParseJsonTree(order)
+		tree.CollapseAll()
+
+		assert.True(t, tree.Search("b2", true))
+		assert.Equal(t, "$.order.items[1].sku", tree.Focused().Path())
+
+		assert.True(t, tree.Search("sku", false))
+		assert.Equal(t, "$.order.items[0].sku", tree.Focused().Path())
+
+		assert.False(t, tree.Search("DELIVERED", true))
+	})
+
+	t.Run("Path and value of the focused node", func(t *testing.T) {
+		tree, _ := ParseJsonTree(order)
+
+		tree.MoveDown(3)
+		assert.Equal(t, "$.order.status", tree.Focused().Path())
+		assert.Equal(t, "SHIPPED", tree.Focused().Value())
+
+		tree.MoveDown(2)
+		assert.Equal(t, "$.order.items", tree.Focused().Path())
+		assert.Equal(t, "[\n  {\n    \"sku\": \"a1\"\n  },\n  {\n    \"sku\": \"b2\"\n  }\n]", tree.Focused().Value())
+
+		tree.MoveDown(100)
+		assert.Equal(t, `$["my key"]`, tree.Focused().Path())
+		assert.Equal(t, "null", tree.Focused().Value())
+	})
+
+	t.Run("Paths and values are valid JSON", func(t *testing.T) {
+		tree, err := ParseJsonTree(`{"a\u0001b":{"html":"<b>&</b>","tab":"\t"}}`)
+		assert.NoError(t, err)
+
+		tree.MoveDown(1)
+		assert.Equal(t, `$["a\u0001b"]`, tree.Focused().Path())
+		assert.Equal(t, "{\n  \"html\": \"<b>&</b>\",\n  \"tab\": \"\\t\"\n}", tree.Focused().Value())
+		assert.True(t, json.Valid([]byte(tree.Focused().Value())))
+	})
+
+	t.Run("Only objects and arrays are trees", func(t *testing.T) {
+		for _, text := range []string{`"text"`, "12", "no json", `{"a":1} {"b":2}`} {
+			_, err := ParseJsonTree(text)
+			assert.Error(t, err, text)
+		}
+	})
+}
+
+func lines(tree *JsonTree) []string {
+	return strings.Split(ansi.Strip(tree.Render(100)), "\n")
+}
@@ -4,13 +4,17 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+	"ktea/config"
 	"ktea/kadmin"
 	"ktea/kontext"
+	"ktea/serdes"
 	"ktea/styles"
 	"ktea/ui"
+	"ktea/ui/components/cmdbar"
 	"ktea/ui/components/statusbar"
 	"ktea/ui/pages/nav"
 	"strconv"
+	"strings"
 )
 
 type selectionState int
@@ -20,6 +24,18 @@ const (
 	selected
 )
 
+// transactions determines the isolation level of the consumption and whether transactions are shown.
+type transactions int
+
+const (
+	readUncommitted transactions = iota
+	readCommitted
+	showTransactions
+)
+
+// topicGroupFields is the number of fields of the topic group
+const topicGroupFields = 5
+
 type Model struct {
 	form                      *huh.Form
 	formValues                *formValues
@@ -29,6 +45,9 @@ type Model struct {
 	ktx                       *kontext.ProgramKtx
 	availableHeight           int
 	topic                     *kadmin.ListedTopic
+	// queryNameInput is set while the name of the query to save is entered
+	queryNameInput *huh.Input
+	savedQuery     string
 }
 
 type formValues struct {
@@ -39,9 +58,30 @@ type formValues struct {
 	keyFilterTerm   string
 	valueFilter     kadmin.FilterType
 	valueFilterTerm string
+	keyFormat       serdes.Format
+	valueFormat     serdes.Format
+	consumerGroup   string
+	commitOffsets   bool
+	transactions    transactions
 }
 
 func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
+	var queryView string
+	if m.queryNameInput != nil {
+		queryView = renderer.RenderWithStyle(
+			m.queryNameInput.View(),
+			styles.CmdBarWithWidth(ktx.WindowWidth-cmdbar.BorderedPadding).
+				BorderForeground(lipgloss.Color(styles.ColorFocusBorder)),
+		)
+	} else if m.savedQuery != "" {
+		queryView = renderer.RenderWithStyle(
+			lipgloss.NewStyle().
+				Foreground(lipgloss.Color(styles.ColorGreen)).
+				Render("Query "+m.savedQuery+" saved"),
+			styles.CmdBarWithWidth(ktx.WindowWidth-cmdbar.BorderedPadding),
+		)
+	}
+
 	if m.form == nil {
 		m.availableHeight = ktx.AvailableHeight
 		m.form = m.newForm(m.topic.PartitionCount, m.ktx)
@@ -53,7 +93,7 @@ func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
 		m.form = m.newForm(m.topic.PartitionCount, ktx)
 	}
 
-	return renderer.RenderWithStyle(m.form.View(), styles.Form)
+	return ui.JoinVertical(lipgloss.Top, queryView, renderer.RenderWithStyle(m.form.View(), styles.Form))
 }
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
@@ -61,6 +101,21 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		return nil
 	}
 
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if m.queryNameInput != nil {
+			return m.updateQueryName(msg)
+		}
+		if msg.String() == "ctrl+s" && m.ktx.Config != nil {
+			m.queryNameInput = huh.NewInput().
+				Title("Save query as: ").
+				Inline(true).
+				Value(&m.savedQuery)
+			m.queryNameInput.Init()
+			m.queryNameInput.Focus()
+			return nil
+		}
+	}
+
 	form, cmd := m.form.Update(msg)
 	if f, ok := form.(*huh.Form); ok {
 		m.form = f
@@ -70,13 +125,13 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		// if key filter type is selected and previously not selected
 		m.keyFilterSelectionState = selected
 		m.form = m.newForm(m.topic.PartitionCount, m.ktx)
-		m.NextField(3)
+		m.NextField(topicGroupFields)
 		m.form.NextGroup()
 	} else if m.formValues.keyFilter == kadmin.NoFilterType && m.keyFilterSelectionState == selected {
 		// if no key filter type is selected and previously selected
 		m.keyFilterSelectionState = notSelected
 		m.form = m.newForm(m.topic.PartitionCount, m.ktx)
-		m.NextField(3)
+		m.NextField(topicGroupFields)
 		m.form.NextGroup()
 	}
 
@@ -84,14 +139,14 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		// if value filter type is selected and previously not selected
 		m.valueFilterSelectionState = selected
 		m.form = m.newForm(m.topic.PartitionCount, m.ktx)
-		m.NextField(3)
+		m.NextField(topicGroupFields)
 		m.form.NextGroup()
 		m.NextField(1)
 	} else if m.formValues.valueFilter == kadmin.NoFilterType && m.valueFilterSelectionState == selected {
 		// if no key filter type is selected and previously selected
 		m.valueFilterSelectionState = notSelected
 		m.form = m.newForm(m.topic.PartitionCount, m.ktx)
-		m.NextField(3)
+		m.NextField(topicGroupFields)
 		m.form.NextGroup()
 		m.NextField(1)
 	}
@@ -109,22 +164,50 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		}
 	}
 
-	filter := kadmin.Filter{}
-	if m.formValues.keyFilter != kadmin.NoFilterType {
-		filter.KeySearchTerm = m.formValues.keyFilterTerm
-		filter.KeyFilter = m.formValues.keyFilter
-	}
-	if m.formValues.valueFilter != kadmin.NoFilterType {
-		filter.ValueSearchTerm = m.formValues.valueFilterTerm
-		filter.ValueFilter = m.formValues.valueFilter
-	}
 	if m.form.State == huh.StateCompleted {
-		return m.submit(filter)
+		return m.submit()
 	}
 	return cmd
 }
 
-func (m *Model) submit(filter kadmin.Filter) tea.Cmd {
+func (m *Model) updateQueryName(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.queryNameInput = nil
+		m.savedQuery = ""
+	case "enter":
+		m.queryNameInput = nil
+		m.savedQuery = strings.TrimSpace(m.savedQuery)
+		if m.savedQuery != "" {
+			m.saveQuery(m.savedQuery)
+		}
+	default:
+		input, cmd := m.queryNameInput.Update(msg)
+		if i, ok := input.(*huh.Input); ok {
+			m.queryNameInput = i
+		}
+		return cmd
+	}
+	return nil
+}
+
+// saveQuery saves the details entered so far as a query of the topic.
+func (m *Model) saveQuery(name string) {
+	if cluster := m.ktx.Config.ActiveCluster(); cluster != nil {
+		m.ktx.Config.SaveQuery(cluster.Name, kadmin.NewConsumptionQuery(name, m.readDetails(), m.topic))
+	}
+}
+
+func (m *Model) submit() tea.Cmd {
+	m.saveTopicFormat()
+
+	return ui.PublishMsg(nav.LoadConsumptionPageMsg{
+		Topic:       m.topic,
+		ReadDetails: m.readDetails(),
+	})
+}
+
+func (m *Model) readDetails() kadmin.ReadDetails {
 	var partToConsume []int
 	if m.noPartitionsSelected() {
 		// consume from all partitions
@@ -133,16 +216,49 @@ func (m *Model) submit(filter kadmin.Filter) tea.Cmd {
 		partToConsume = m.formValues.partitions
 	}
 
-	return ui.PublishMsg(nav.LoadConsumptionPageMsg{
-		Topic: m.topic,
-		ReadDetails: kadmin.ReadDetails{
-			TopicName:       m.topic.Name,
-			PartitionToRead: partToConsume,
-			StartPoint:      m.formValues.startPoint,
-			Limit:           m.formValues.limit,
-			Filter:          &filter,
-		},
-	})
+	filter := kadmin.Filter{}
+	if m.formValues.keyFilter != kadmin.NoFilterType {
+		filter.KeySearchTerm = m.formValues.keyFilterTerm
+		filter.KeyFilter = m.formValues.keyFilter
+	}
+	if m.formValues.valueFilter != kadmin.NoFilterType {
+		filter.ValueSearchTerm = m.formValues.valueFilterTerm
+		filter.ValueFilter = m.formValues.valueFilter
+	}
+
+	consumerGroup := strings.TrimSpace(m.formValues.consumerGroup)
+
+	isolationLevel := kadmin.ReadUncommitted
+	if m.formValues.transactions == readCommitted {
+		isolationLevel = kadmin.ReadCommitted
+	}
+
+	return kadmin.ReadDetails{
+		TopicName:        m.topic.Name,
+		PartitionToRead:  partToConsume,
+		StartPoint:       m.formValues.startPoint,
+		Limit:            m.formValues.limit,
+		Filter:           &filter,
+		KeyFormat:        m.formValues.keyFormat,
+		ValueFormat:      m.formValues.valueFormat,
+		ConsumerGroup:    consumerGroup,
+		CommitOffsets:    consumerGroup != "" && m.formValues.commitOffsets,
+		IsolationLevel:   isolationLevel,
+		ShowTransactions: m.formValues.transactions == showTransactions,
+	}
+}
+
+// saveTopicFormat remembers the chosen formats for the next consumption of the topic.
+func (m *Model) saveTopicFormat() {
+	if m.ktx.Config == nil {
+		return
+	}
+	if cluster := m.ktx.Config.ActiveCluster(); cluster != nil {
+		m.ktx.Config.SaveTopicFormat(cluster.Name, m.topic.Name, config.TopicFormat{
+			Key:   string(m.formValues.keyFormat),
+			Value: string(m.formValues.valueFormat),
+		})
+	}
 }
 
 func (m *Model) noPartitionsSelected() bool {
@@ -150,13 +266,22 @@ func (m *Model) noPartitionsSelected() bool {
 }
 
 func (m *Model) Shortcuts() []statusbar.Shortcut {
-	return []statusbar.Shortcut{
+	if m.queryNameInput != nil {
+		return []statusbar.Shortcut{
+			{"Save Query", "enter"},
+			{"Cancel", "esc"},
+		}
+	}
+	shortcuts := []statusbar.Shortcut{
 		{"Confirm", "enter"},
 		{"Next Field", "tab"},
 		{"Prev. Field", "s-tab"},
 		{"Select Partition", "space"},
-		{"Go Back", "esc"},
 	}
+	if m.ktx.Config != nil {
+		shortcuts = append(shortcuts, statusbar.Shortcut{"Save Query", "C-s"})
+	}
+	return append(shortcuts, statusbar.Shortcut{"Go Back", "esc"})
 }
 
 func (m *Model) Title() string {
@@ -168,7 +293,7 @@ func (m *Model) newForm(partitions int, ktx *kontext.ProgramKtx) *huh.Form {
 	for i := 0; i < partitions; i++ {
 		partOptions = append(partOptions, huh.NewOption[int](strconv.Itoa(i), i))
 	}
-	optionsHeight := 13 // 12 fixed height of form minus partitions field + padding and margins
+	optionsHeight := 17 // 16 fixed height of form minus partitions field + padding and margins
 	if len(partOptions) < 13 {
 		optionsHeight = len(partOptions) + 2 // 2 for field title + padding
 	} else {
@@ -194,6 +319,16 @@ func (m *Model) newForm(partitions int, ktx *kontext.ProgramKtx) *huh.Form {
 				huh.NewOption("50", 50),
 				huh.NewOption("500", 500),
 				huh.NewOption("5000", 5000)),
+		huh.NewSelect[serdes.Format]().
+			Value(&m.formValues.keyFormat).
+			Title("Key Format: ").
+			Inline(true).
+			Options(formatOptions()...),
+		huh.NewSelect[serdes.Format]().
+			Value(&m.formValues.valueFormat).
+			Title("Value Format: ").
+			Inline(true).
+			Options(formatOptions()...),
 	)
 	filterGroup := m.createFilterGroup()
 	form := huh.NewForm(
@@ -205,6 +340,14 @@ func (m *Model) newForm(partitions int, ktx *kontext.ProgramKtx) *huh.Form {
 	return form
 }
 
+func formatOptions() []huh.Option[serdes.Format] {
+	var options []huh.Option[serdes.Format]
+	for _, format := range serdes.Formats {
+		options = append(options, huh.NewOption(format.String(), format))
+	}
+	return options
+}
+
 func (m *Model) createFilterGroup() *huh.Group {
 	var fields []huh.Field
 
@@ -218,9 +361,39 @@ func (m *Model) createFilterGroup() *huh.Group {
 		fields = append(fields, m.valueFilterTermField())
 	}
 
+	fields = append(fields, m.consumerGroupField(), m.commitOffsetsField(), m.transactionsField())
+
 	return huh.NewGroup(fields...)
 }
 
+func (m *Model) consumerGroupField() *huh.Input {
+	return huh.NewInput().
+		Value(&m.formValues.consumerGroup).
+		Title("Consumer Group").
+		Placeholder("none, start at the committed offsets of a group")
+}
+
+func (m *Model) transactionsField() *huh.Select[transactions] {
+	return huh.NewSelect[transactions]().
+		Value(&m.formValues.transactions).
+		Title("Transactions: ").
+		Inline(true).
+		Options(
+			huh.NewOption("Read Uncommitted", readUncommitted),
+			huh.NewOption("Read Committed", readCommitted),
+			huh.NewOption("Show Markers and Aborted", showTransactions))
+}
+
+func (m *Model) commitOffsetsField() *huh.Select[bool] {
+	return huh.NewSelect[bool]().
+		Value(&m.formValues.commitOffsets).
+		Title("Commit Offsets: ").
+		Inline(true).
+		Options(
+			huh.NewOption("No", false),
+			huh.NewOption("Yes", true))
+}
+
 func (m *Model) valueFilterTermField() *huh.Input {
 	return huh.NewInput().
 		Value(&m.formValues.valueFilterTerm).
@@ -279,6 +452,12 @@ func NewWithDetails(
 	if topic.PartitionCount != len(details.PartitionToRead) {
 		partitionsToRead = details.PartitionToRead
 	}
+	transactions := readUncommitted
+	if details.ShowTransactions {
+		transactions = showTransactions
+	} else if details.IsolationLevel == kadmin.ReadCommitted {
+		transactions = readCommitted
+	}
 	return &Model{
 		ktx:   ktx,
 		topic: topic,
@@ -290,13 +469,26 @@ func NewWithDetails(
 			keyFilterTerm:   details.Filter.KeySearchTerm,
 			valueFilter:     details.Filter.ValueFilter,
 			valueFilterTerm: details.Filter.ValueSearchTerm,
+			keyFormat:       details.KeyFormat,
+			valueFormat:     details.ValueFormat,
+			consumerGroup:   details.ConsumerGroup,
+			commitOffsets:   details.CommitOffsets,
+			transactions:    transactions,
 		}}
 }
 
 func New(topic *kadmin.ListedTopic, ktx *kontext.ProgramKtx) *Model {
+	formValues := &formValues{}
+	if ktx.Config != nil {
+		if cluster := ktx.Config.ActiveCluster(); cluster != nil {
+			format := cluster.TopicFormat(topic.Name)
+			formValues.keyFormat = serdes.Format(format.Key)
+			formValues.valueFormat = serdes.Format(format.Value)
+		}
+	}
 	return &Model{
 		topic:      topic,
-		formValues: &formValues{},
+		formValues: formValues,
 		ktx:        ktx,
 	}
 }
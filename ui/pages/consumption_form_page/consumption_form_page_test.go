@@ -5,8 +5,10 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/stretchr/testify/assert"
+	"ktea/config"
 	"ktea/kadmin"
 	"ktea/kontext"
+	"ktea/serdes"
 	"ktea/tests"
 	"ktea/ui/pages/nav"
 	"testing"
@@ -51,8 +53,8 @@ func TestConsumeForm_Navigation(t *testing.T) {
 		ktx := &kontext.ProgramKtx{
 			Config:          nil,
 			WindowWidth:     100,
-			WindowHeight:    20,
-			AvailableHeight: 20,
+			WindowHeight:    24,
+			AvailableHeight: 24,
 		}
 		m := New(&kadmin.ListedTopic{
 			Name:           "topic1",
@@ -163,6 +165,14 @@ func TestConsumeForm_Navigation(t *testing.T) {
 		cmd = m.Update(tests.Key(tea.KeyEnter))
 		// next field
 		cmd = m.Update(cmd())
+		// auto detect key format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// auto detect value format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
 		// next group
 		m.Update(cmd())
 		// no key filter
@@ -170,6 +180,18 @@ func TestConsumeForm_Navigation(t *testing.T) {
 		// next field
 		cmd = m.Update(cmd())
 		// no value filter
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// no consumer group
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// do not commit offsets
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// read uncommitted
 		msgs := tests.Submit(m)
 
 		assert.Equal(t, nav.LoadConsumptionPageMsg{
@@ -214,6 +236,14 @@ func TestConsumeForm_Navigation(t *testing.T) {
 		cmd = m.Update(tests.Key(tea.KeyEnter))
 		// next field
 		cmd = m.Update(cmd())
+		// auto detect key format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// auto detect value format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
 		// next group
 		m.Update(cmd())
 		// no key filter
@@ -221,6 +251,18 @@ func TestConsumeForm_Navigation(t *testing.T) {
 		// next field
 		cmd = m.Update(cmd())
 		// no value filter
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// no consumer group
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// do not commit offsets
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// read uncommitted
 		msgs := tests.Submit(m)
 
 		assert.Equal(t, nav.LoadConsumptionPageMsg{
@@ -265,6 +307,14 @@ func TestConsumeForm_Navigation(t *testing.T) {
 		cmd = m.Update(tests.Key(tea.KeyEnter))
 		// next field
 		cmd = m.Update(cmd())
+		// auto detect key format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// auto detect value format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
 		// next group
 		m.Update(cmd())
 		// starts-with key filter
@@ -309,6 +359,18 @@ func TestConsumeForm_Navigation(t *testing.T) {
 			// next field
 			cmd = m.Update(cmd())
 			// no value filter
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			// next field
+			cmd = m.Update(cmd())
+			// no consumer group
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			// next field
+			cmd = m.Update(cmd())
+			// do not commit offsets
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			// next field
+			cmd = m.Update(cmd())
+			// read uncommitted
 			msgs := tests.Submit(m)
 
 			assert.Equal(t, nav.LoadConsumptionPageMsg{
@@ -354,6 +416,14 @@ func TestConsumeForm_Navigation(t *testing.T) {
 		cmd = m.Update(tests.Key(tea.KeyEnter))
 		// next field
 		cmd = m.Update(cmd())
+		// auto detect key format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// auto detect value format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
 		// next group
 		m.Update(cmd())
 		// starts-with key filter
@@ -368,6 +438,18 @@ func TestConsumeForm_Navigation(t *testing.T) {
 		// next field
 		cmd = m.Update(cmd())
 		// no value filter
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// no consumer group
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// do not commit offsets
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// read uncommitted
 		msgs := tests.Submit(m)
 
 		assert.EqualValues(t, nav.LoadConsumptionPageMsg{
@@ -413,6 +495,14 @@ func TestConsumeForm_Navigation(t *testing.T) {
 		cmd = m.Update(tests.Key(tea.KeyEnter))
 		// next field
 		cmd = m.Update(cmd())
+		// auto detect key format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// auto detect value format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
 		// next group
 		m.Update(cmd())
 		// no key filter
@@ -476,6 +566,18 @@ func TestConsumeForm_Navigation(t *testing.T) {
 			// next field
 			cmd = m.Update(cmd())
 			// no value filter
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			// next field
+			cmd = m.Update(cmd())
+			// no consumer group
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			// next field
+			cmd = m.Update(cmd())
+			// do not commit offsets
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			// next field
+			cmd = m.Update(cmd())
+			// read uncommitted
 			msgs := tests.Submit(m)
 
 			assert.Equal(t, nav.LoadConsumptionPageMsg{
@@ -499,3 +601,246 @@ func TestConsumeForm_Navigation(t *testing.T) {
 		})
 	})
 }
+
+func TestConsumeForm_Formats(t *testing.T) {
+	newConfig := func() *config.Config {
+		cfg := config.New(&config.InMemoryConfigIO{})
+		cfg.RegisterCluster(config.RegistrationDetails{
+			Name:       "prd",
+			Host:       "localhost:9092",
+			AuthMethod: config.NoneAuthMethod,
+		})
+		return cfg
+	}
+
+	t.Run("submitting form consumes with selected formats and remembers them", func(t *testing.T) {
+		cfg := newConfig()
+		m := New(&kadmin.ListedTopic{
+			Name:           "topic1",
+			PartitionCount: 10,
+			Replicas:       1,
+		}, tests.NewKontext(tests.WithConfig(cfg)))
+		// make sure form has been initialized
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		// start from beginning
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		m.Update(cmd())
+		// select no partitions
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// limit 50
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// select int64 key format
+		for i := 0; i < 4; i++ {
+			m.Update(tests.Key(tea.KeyRight))
+		}
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// select string value format
+		m.Update(tests.Key(tea.KeyRight))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// next group
+		m.Update(cmd())
+		// no key filter
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// no value filter
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// no consumer group
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// do not commit offsets
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// read uncommitted
+		msgs := tests.Submit(m)
+
+		assert.Equal(t, nav.LoadConsumptionPageMsg{
+			ReadDetails: kadmin.ReadDetails{
+				TopicName:       "topic1",
+				Filter:          &kadmin.Filter{},
+				Limit:           50,
+				PartitionToRead: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+				StartPoint:      kadmin.Beginning,
+				KeyFormat:       serdes.Int64Format,
+				ValueFormat:     serdes.StringFormat,
+			},
+			Topic: &kadmin.ListedTopic{
+				Name:           "topic1",
+				PartitionCount: 10,
+				Replicas:       1,
+			},
+		}, msgs[0])
+		assert.Equal(t, config.TopicFormat{Key: "int64", Value: "string"}, cfg.ActiveCluster().TopicFormat("topic1"))
+	})
+
+	t.Run("load formats remembered for the topic", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.SaveTopicFormat("prd", "topic1", config.TopicFormat{Key: "uuid", Value: "hex"})
+		m := New(&kadmin.ListedTopic{
+			Name:           "topic1",
+			PartitionCount: 10,
+			Replicas:       1,
+		}, tests.NewKontext(tests.WithConfig(cfg)))
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+
+		assert.Regexp(t, `Key Format: .*UUID`, render)
+		assert.Regexp(t, `Value Format: .*Hex`, render)
+	})
+}
+
+func TestConsumeForm_SaveQuery(t *testing.T) {
+	cfg := config.New(&config.InMemoryConfigIO{})
+	cfg.RegisterCluster(config.RegistrationDetails{
+		Name:       "prd",
+		Host:       "localhost:9092",
+		AuthMethod: config.NoneAuthMethod,
+	})
+	topic := &kadmin.ListedTopic{
+		Name:           "topic1",
+		PartitionCount: 10,
+		Replicas:       1,
+	}
+	m := NewWithDetails(&kadmin.ReadDetails{
+		TopicName:       "topic1",
+		PartitionToRead: []int{3},
+		StartPoint:      kadmin.MostRecent,
+		Limit:           500,
+		Filter: &kadmin.Filter{
+			KeyFilter:     kadmin.StartsWithFilterType,
+			KeySearchTerm: "order-",
+			ValueFilter:   kadmin.NoFilterType,
+		},
+	}, topic, tests.NewKontext(tests.WithConfig(cfg)))
+	// make sure form has been initialized
+	m.View(tests.NewKontext(), tests.TestRenderer)
+
+	m.Update(tests.Key(tea.KeyCtrlS))
+	tests.UpdateKeys(m, "recent orders")
+	m.Update(tests.Key(tea.KeyEnter))
+
+	assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "Query recent orders saved")
+	assert.Equal(t, []config.ConsumptionQuery{{
+		Name:          "recent orders",
+		Topic:         "topic1",
+		Partitions:    []int{3},
+		StartPoint:    "most-recent",
+		Limit:         500,
+		KeyFilter:     "starts with",
+		KeyFilterTerm: "order-",
+	}}, cfg.ActiveCluster().Queries("topic1"))
+}
+
+func TestConsumeForm_ConsumerGroupAndTransactions(t *testing.T) {
+	topic := &kadmin.ListedTopic{
+		Name:           "topic1",
+		PartitionCount: 2,
+		Replicas:       1,
+	}
+
+	t.Run("consume as consumer group and commit the offsets", func(t *testing.T) {
+		m := New(topic, tests.NewKontext())
+		// make sure form has been initialized
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		// start from beginning
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		m.Update(cmd())
+		// select no partitions
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// limit 50
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// auto detect key format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// auto detect value format
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// next group
+		m.Update(cmd())
+		// no key filter
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		cmd = m.Update(cmd())
+		// no value filter
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		m.Update(cmd())
+		// consumer group
+		tests.UpdateKeys(m, "order-processor")
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		m.Update(cmd())
+		// commit offsets
+		m.Update(tests.Key(tea.KeyRight))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next field
+		m.Update(cmd())
+		// read committed
+		m.Update(tests.Key(tea.KeyRight))
+		msgs := tests.Submit(m)
+
+		assert.Equal(t, nav.LoadConsumptionPageMsg{
+			ReadDetails: kadmin.ReadDetails{
+				TopicName:       "topic1",
+				Filter:          &kadmin.Filter{},
+				Limit:           50,
+				PartitionToRead: []int{0, 1},
+				StartPoint:      kadmin.Beginning,
+				ConsumerGroup:   "order-processor",
+				CommitOffsets:   true,
+				IsolationLevel:  kadmin.ReadCommitted,
+			},
+			Topic: topic,
+		}, msgs[0])
+	})
+
+	t.Run("load the consumer group of previous ReadDetails", func(t *testing.T) {
+		m := NewWithDetails(&kadmin.ReadDetails{
+			TopicName:       "topic1",
+			PartitionToRead: []int{0, 1},
+			Filter:          &kadmin.Filter{},
+			ConsumerGroup:   "order-processor",
+			CommitOffsets:   true,
+		}, topic, tests.NewKontext())
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+
+		assert.Contains(t, render, "order-processor")
+		assert.Regexp(t, `Commit Offsets: .*Yes`, render)
+	})
+
+	t.Run("load the transactions of previous ReadDetails", func(t *testing.T) {
+		m := NewWithDetails(&kadmin.ReadDetails{
+			TopicName:        "topic1",
+			PartitionToRead:  []int{0, 1},
+			Filter:           &kadmin.Filter{},
+			ShowTransactions: true,
+		}, topic, tests.NewKontext())
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+
+		assert.Regexp(t, `Transactions: .*Show Markers and Aborted`, render)
+	})
+}
@@ -0,0 +1,182 @@
+package consumption_page
+
+import (
+	"errors"
+	"fmt"
+	"ktea/kadmin"
+	"ktea/ui"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const headerColumnPrefix = "header:"
+
+// defaultColumns are shown when no columns are configured for the topic.
+var defaultColumns = []string{"key", "timestamp", "partition", "offset"}
+
+// column is a column of the consumption table, it is defined by a spec which is either
+// key, timestamp, partition, offset, header:<name> or a JSON path into the value like $.order.status.
+type column struct {
+	spec string
+	path []ui.JsonPathSegment
+}
+
+func parseColumn(spec string) (column, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "key", spec == "timestamp", spec == "partition", spec == "offset":
+		return column{spec: spec}, nil
+	case strings.HasPrefix(spec, headerColumnPrefix):
+		if strings.TrimPrefix(spec, headerColumnPrefix) == "" {
+			return column{}, fmt.Errorf("header column %q has no header name", spec)
+		}
+		return column{spec: spec}, nil
+	case strings.HasPrefix(spec, "$"):
+		path, err := ui.ParseJsonPath(spec)
+		if err != nil {
+			return column{}, err
+		}
+		return column{spec: spec, path: path}, nil
+	default:
+		return column{}, fmt.Errorf(
+			"unknown column %q, expected key, timestamp, partition, offset, header:<name> or a JSON path like $.field",
+			spec,
+		)
+	}
+}
+
+// parseColumns parses the specs, skipping the invalid ones which are reported in the error.
+// The default columns are used when there are no specs or none of them is valid.
+func parseColumns(specs []string) ([]column, error) {
+	columns := make([]column, 0, len(specs))
+	var errs []error
+	for _, spec := range specs {
+		c, err := parseColumn(spec)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		columns = append(columns, c)
+	}
+	if len(columns) == 0 {
+		columns = defaultLayout()
+	}
+	return columns, errors.Join(errs...)
+}
+
+func defaultLayout() []column {
+	columns := make([]column, len(defaultColumns))
+	for i, spec := range defaultColumns {
+		columns[i] = column{spec: spec}
+	}
+	return columns
+}
+
+func (c column) title() string {
+	switch {
+	case strings.HasPrefix(c.spec, "$"):
+		return c.spec
+	case strings.HasPrefix(c.spec, headerColumnPrefix):
+		return strings.TrimPrefix(c.spec, headerColumnPrefix)
+	default:
+		return strings.ToUpper(c.spec[:1]) + c.spec[1:]
+	}
+}
+
+// weight is the share of the table width the column takes relative to the other columns.
+func (c column) weight() int {
+	switch c.spec {
+	case "key":
+		return 5
+	case "partition", "offset":
+		return 1
+	default:
+		return 3
+	}
+}
+
+// cell renders the column for the record, value is the record's value decoded as JSON,
+// it is only decoded once per row by the caller. Header values are decoded with the decoder of their key.
+func (c column) cell(
+	record kadmin.ConsumerRecord,
+	value func() (any, bool),
+	headerDecoder func(key string) kadmin.HeaderDecoder,
+) string {
+	switch {
+	case c.spec == "key":
+		if record.Transaction.IsMarker() {
+			return "<" + record.Transaction.String() + ">"
+		}
+		if record.Key == "" {
+			return "<null>"
+		}
+		return record.Key
+	case c.spec == "timestamp":
+		return record.Timestamp.Format("2006-01-02 15:04:05")
+	case c.spec == "partition":
+		return strconv.FormatInt(record.Partition, 10)
+	case c.spec == "offset":
+		return strconv.FormatInt(record.Offset, 10)
+	case strings.HasPrefix(c.spec, headerColumnPrefix):
+		name := strings.TrimPrefix(c.spec, headerColumnPrefix)
+		for _, header := range record.Headers {
+			if header.Key == name {
+				decoder := headerDecoder(name)
+				decoded, err := header.Value.Decode(decoder)
+				if err != nil {
+					return "<invalid " + string(decoder) + ">"
+				}
+				return decoded
+			}
+		}
+		return ""
+	default:
+		decoded, ok := value()
+		if !ok {
+			return ""
+		}
+		return jsonCell(c.path, decoded)
+	}
+}
+
+func jsonCell(path []ui.JsonPathSegment, value any) string {
+	value, ok := ui.JsonPathValue(value, path)
+	if !ok {
+		return ""
+	}
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return ui.EncodeJson(value)
+}
+
+// decodeJsonValue lazily decodes the value of the record as JSON, at most once.
+func decodeJsonValue(record kadmin.ConsumerRecord) func() (any, bool) {
+	var (
+		decoded any
+		ok      bool
+		done    bool
+	)
+	return func() (any, bool) {
+		if !done {
+			done = true
+			var err error
+			decoded, err = ui.DecodeJson(record.Payload.Value)
+			ok = err == nil
+		}
+		return decoded, ok
+	}
+}
+
+func columnSpecs(columns []column) []string {
+	specs := make([]string, len(columns))
+	for i, c := range columns {
+		specs[i] = c.spec
+	}
+	return specs
+}
+
+func isDefaultLayout(columns []column) bool {
+	return slices.Equal(columnSpecs(columns), defaultColumns)
+}
@@ -0,0 +1,168 @@
+package consumption_page
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"ktea/kontext"
+	"ktea/styles"
+	"ktea/ui"
+	"ktea/ui/components/cmdbar"
+	"ktea/ui/components/statusbar"
+	"slices"
+	"strings"
+)
+
+// columnsEditor adds, removes and reorders the columns of the consumption table.
+type columnsEditor struct {
+	columns  []column
+	selected int
+	active   bool
+	adding   bool
+	input    *huh.Input
+	err      error
+}
+
+func (e *columnsEditor) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
+	if !e.active {
+		return ""
+	}
+
+	style := styles.CmdBarWithWidth(ktx.WindowWidth - cmdbar.BorderedPadding).
+		BorderForeground(lipgloss.Color(styles.ColorFocusBorder))
+
+	if e.adding {
+		view := e.input.View()
+		if e.err != nil {
+			view += "\n" + lipgloss.NewStyle().
+				Foreground(lipgloss.Color(styles.ColorRed)).
+				Render(e.err.Error())
+		}
+		return renderer.RenderWithStyle(view, style)
+	}
+
+	builder := strings.Builder{}
+	for i, c := range e.columns {
+		chip := lipgloss.NewStyle().
+			Background(lipgloss.Color(styles.ColorDarkGrey)).
+			Foreground(lipgloss.Color(styles.ColorWhite))
+		if i == e.selected {
+			chip = lipgloss.NewStyle().
+				Background(lipgloss.Color(styles.ColorPink)).
+				Foreground(lipgloss.Color(styles.ColorBlack))
+		}
+		builder.WriteString(chip.Padding(0, 1).MarginLeft(1).Render(c.title()))
+	}
+	return renderer.RenderWithStyle(builder.String(), style)
+}
+
+// Update handles the key and reports whether the columns changed.
+func (e *columnsEditor) Update(msg tea.KeyMsg) bool {
+	if e.adding {
+		return e.updateAdding(msg)
+	}
+
+	switch msg.String() {
+	case "h", "left":
+		if e.selected > 0 {
+			e.selected--
+		}
+	case "l", "right":
+		if e.selected < len(e.columns)-1 {
+			e.selected++
+		}
+	case "shift+left":
+		if e.selected > 0 {
+			e.swap(e.selected, e.selected-1)
+			e.selected--
+			return true
+		}
+	case "shift+right":
+		if e.selected < len(e.columns)-1 {
+			e.swap(e.selected, e.selected+1)
+			e.selected++
+			return true
+		}
+	case "+":
+		e.adding = true
+		e.err = nil
+		e.input = newColumnInput()
+		e.input.Focus()
+	case "delete", "backspace":
+		// the table needs at least one column
+		if len(e.columns) > 1 {
+			e.columns = slices.Delete(e.columns, e.selected, e.selected+1)
+			e.selected = min(e.selected, len(e.columns)-1)
+			return true
+		}
+	case "ctrl+r":
+		e.columns = defaultLayout()
+		e.selected = 0
+		return true
+	case "esc", "c":
+		e.active = false
+	}
+	return false
+}
+
+func (e *columnsEditor) updateAdding(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc":
+		e.adding = false
+		return false
+	case "enter":
+		c, err := parseColumn(e.input.GetValue().(string))
+		if err != nil {
+			e.err = err
+			return false
+		}
+		e.columns = slices.Insert(e.columns, e.selected+1, c)
+		e.selected++
+		e.adding = false
+		return true
+	default:
+		input, _ := e.input.Update(msg)
+		if i, ok := input.(*huh.Input); ok {
+			e.input = i
+		}
+		return false
+	}
+}
+
+func (e *columnsEditor) swap(i, j int) {
+	e.columns[i], e.columns[j] = e.columns[j], e.columns[i]
+}
+
+func (e *columnsEditor) Shortcuts() []statusbar.Shortcut {
+	if e.adding {
+		return []statusbar.Shortcut{
+			{"Add Column", "enter"},
+			{"Cancel", "esc"},
+		}
+	}
+	return []statusbar.Shortcut{
+		{"Select", "←/→"},
+		{"Move", "S-←/→"},
+		{"Add", "+"},
+		{"Remove", "del"},
+		{"Reset", "C-r"},
+		{"Close", "esc"},
+	}
+}
+
+// open starts editing the given columns.
+func (e *columnsEditor) open(columns []column) {
+	e.columns = slices.Clone(columns)
+	e.selected = 0
+	e.adding = false
+	e.active = true
+}
+
+func newColumnInput() *huh.Input {
+	input := huh.NewInput().
+		Title("Column: ").
+		Inline(true).
+		Placeholder("key, timestamp, partition, offset, header:<name> or $.json.path")
+	input.Init()
+	return input
+}
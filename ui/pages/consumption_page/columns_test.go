@@ -0,0 +1,112 @@
+package consumption_page
+
+import (
+	"github.com/stretchr/testify/assert"
+	"ktea/kadmin"
+	"ktea/serdes"
+	"testing"
+)
+
+func autoDecoder(string) kadmin.HeaderDecoder {
+	return kadmin.AutoHeaderDecoder
+}
+
+func TestColumns(t *testing.T) {
+	record := kadmin.ConsumerRecord{
+		Key:     "order-1",
+		Payload: serdes.DesData{Value: `{"order":{"status":"SHIPPED","total":12.50,"items":[{"sku":"a1"},{"sku":"b2"}]}}`},
+		Headers: []kadmin.Header{{Key: "trace-id", Value: kadmin.NewHeaderValue("abc")}},
+	}
+
+	cell := func(spec string) string {
+		c, err := parseColumn(spec)
+		assert.NoError(t, err)
+		return c.cell(record, decodeJsonValue(record), autoDecoder)
+	}
+
+	t.Run("Extract a field by JSON path", func(t *testing.T) {
+		assert.Equal(t, "SHIPPED", cell("$.order.status"))
+	})
+
+	t.Run("Keep numbers as they were written", func(t *testing.T) {
+		assert.Equal(t, "12.50", cell("$.order.total"))
+	})
+
+	t.Run("Extract array elements", func(t *testing.T) {
+		assert.Equal(t, "b2", cell("$.order.items[1].sku"))
+	})
+
+	t.Run("Render objects and arrays as JSON", func(t *testing.T) {
+		assert.Equal(t, `[{"sku":"a1"},{"sku":"b2"}]`, cell("$.order.items"))
+	})
+
+	t.Run("Render nothing for missing fields", func(t *testing.T) {
+		assert.Equal(t, "", cell("$.order.customer"))
+		assert.Equal(t, "", cell("$.order.items[5]"))
+	})
+
+	t.Run("Render nothing for values that are no JSON", func(t *testing.T) {
+		c, _ := parseColumn("$.order.status")
+		plain := kadmin.ConsumerRecord{Payload: serdes.DesData{Value: "plain text"}}
+
+		assert.Equal(t, "", c.cell(plain, decodeJsonValue(plain), autoDecoder))
+	})
+
+	t.Run("Extract header values", func(t *testing.T) {
+		assert.Equal(t, "abc", cell("header:trace-id"))
+		assert.Equal(t, "", cell("header:missing"))
+	})
+
+	t.Run("Decode header values with the decoder of their key", func(t *testing.T) {
+		binary := kadmin.ConsumerRecord{Headers: []kadmin.Header{
+			{Key: "count", Value: kadmin.NewBinaryHeaderValue([]byte{0, 0, 0, 0, 0, 0, 0, 42})},
+			{Key: "id", Value: kadmin.NewBinaryHeaderValue([]byte{0, 1})},
+		}}
+		decoders := map[string]kadmin.HeaderDecoder{"count": kadmin.Int64HeaderDecoder, "id": kadmin.UuidHeaderDecoder}
+		decoderCell := func(spec string) string {
+			c, err := parseColumn(spec)
+			assert.NoError(t, err)
+			return c.cell(binary, decodeJsonValue(binary), func(key string) kadmin.HeaderDecoder {
+				return decoders[key]
+			})
+		}
+
+		assert.Equal(t, "42", decoderCell("header:count"))
+		assert.Equal(t, "<invalid uuid>", decoderCell("header:id"))
+	})
+
+	t.Run("Extract fields in brackets", func(t *testing.T) {
+		quoted := kadmin.ConsumerRecord{Payload: serdes.DesData{Value: `{"order-id":{"a.b":"x","say \"hi\"":"y"}}`}}
+		quotedCell := func(spec string) string {
+			c, err := parseColumn(spec)
+			assert.NoError(t, err)
+			return c.cell(quoted, decodeJsonValue(quoted), autoDecoder)
+		}
+
+		assert.Equal(t, "x", quotedCell(`$["order-id"]['a.b']`))
+		assert.Equal(t, "y", quotedCell(`$['order-id']["say \"hi\""]`))
+		assert.Equal(t, "b2", cell(`$["order"]["items"][1].sku`))
+	})
+
+	t.Run("Reject invalid columns", func(t *testing.T) {
+		for _, spec := range []string{"value", "header:", "$.order..status", "$.items[x]", "$.items[0", `$["order"`, `$["order]`, `$['order']x`} {
+			_, err := parseColumn(spec)
+			assert.Error(t, err, spec)
+		}
+	})
+
+	t.Run("Skip and report invalid columns", func(t *testing.T) {
+		columns, err := parseColumns([]string{"key", "unknown", "$.order.status", "$.items[x]"})
+
+		assert.Equal(t, []string{"key", "$.order.status"}, columnSpecs(columns))
+		assert.ErrorContains(t, err, `unknown column "unknown"`)
+		assert.ErrorContains(t, err, "invalid index x")
+	})
+
+	t.Run("Fall back to the default columns when no column is valid", func(t *testing.T) {
+		columns, err := parseColumns([]string{"unknown"})
+
+		assert.Equal(t, defaultColumns, columnSpecs(columns))
+		assert.Error(t, err)
+	})
+}
@@ -9,13 +9,19 @@ import (
 	"ktea/ui"
 	"ktea/ui/components/statusbar"
 	"ktea/ui/pages/nav"
-	"strconv"
+	"slices"
+	"time"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// defaultBufferSize is the number of records kept when no buffer size is configured.
+const defaultBufferSize = 10_000
+
+type Option func(m *Model)
+
 type Model struct {
 	table              *table.Model
 	cmdBar             *ConsumptionCmdBar
@@ -23,13 +29,45 @@ type Model struct {
 	emptyTopicChan     chan bool
 	cancelConsumption  context.CancelFunc
 	errChan            chan error
-	reader             kadmin.RecordReader
-	rows               []table.Row
-	records            []kadmin.ConsumerRecord
+	// markRead marks the displayed records as read by the consumer group, nil when nothing is marked.
+	markRead func(record kadmin.ConsumerRecord)
+	reader   kadmin.RecordReader
+	// rows and records are kept newest first, the rows are those of the records at the same index.
+	rows    *ring[table.Row]
+	records *ring[kadmin.ConsumerRecord]
+	// added is the number of records added so far, which numbers the records in the order they were consumed.
+	added int
+	// marked holds the numbers of the marked records.
+	marked             map[int]bool
 	readDetails        kadmin.ReadDetails
 	consuming          bool
 	noRecordsAvailable bool
 	topic              *kadmin.ListedTopic
+	// bufferSize is the maximum number of records kept, the oldest records are dropped first.
+	bufferSize int
+	paused     bool
+	// pending holds the records received while paused.
+	pending    []kadmin.ConsumerRecord
+	autoScroll bool
+	// scrollToNewest and cursorShift are applied to the table cursor once the rows are set.
+	scrollToNewest bool
+	cursorShift    int
+	throughput     throughput
+	columns        []column
+	columnsEditor  *columnsEditor
+	// columnsErr reports the configured columns that are invalid, until the columns are edited.
+	columnsErr error
+	// commitErr is the last failure to commit the offsets of the consumer group.
+	commitErr error
+	// saveColumns remembers the column layout of the topic, nil when it is not remembered.
+	saveColumns func(specs []string)
+	// headerDecoder is the decoder of header columns by header key
+	headerDecoder func(key string) kadmin.HeaderDecoder
+}
+
+type throughputTickMsg struct {
+	page *Model
+	time time.Time
 }
 
 type ConsumerRecordReceived struct {
@@ -44,28 +82,68 @@ type EmptyTopicMsg struct {
 func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
 	var views []string
 	views = append(views, m.cmdBar.View(ktx, renderer))
+	views = append(views, m.columnsEditor.View(ktx, renderer))
+	if m.columnsErr != nil {
+		views = append(views, renderer.Render(lipgloss.NewStyle().
+			Foreground(lipgloss.Color(styles.ColorRed)).
+			Render("Invalid columns left out: "+m.columnsErr.Error())))
+	}
+	if m.commitErr != nil {
+		views = append(views, renderer.Render(lipgloss.NewStyle().
+			Foreground(lipgloss.Color(styles.ColorRed)).
+			Render(m.commitErr.Error())))
+	}
 
-	if m.noRecordsAvailable {
+	if m.noRecordsAvailable && m.readDetails.ConsumerGroup != "" {
+		views = append(views, styles.CenterText(ktx.WindowWidth, ktx.AvailableHeight).
+			Render("👀 No records left to consume for "+m.readDetails.ConsumerGroup))
+	} else if m.noRecordsAvailable {
 		views = append(views, styles.CenterText(ktx.WindowWidth, ktx.AvailableHeight).
 			Render("👀 Empty topic"))
-	} else if len(m.rows) > 0 {
-		m.table.SetColumns([]table.Column{
-			{Title: "Key", Width: int(float64(ktx.WindowWidth-9) * 0.5)},
-			{Title: "Timestamp", Width: int(float64(ktx.WindowWidth-9) * 0.30)},
-			{Title: "Partition", Width: int(float64(ktx.WindowWidth-9) * 0.10)},
-			{Title: "Offset", Width: int(float64(ktx.WindowWidth-9) * 0.10)},
-		})
+	} else if m.rows.size() > 0 {
+		m.table.SetColumns(m.tableColumns(ktx.WindowWidth))
 		m.table.SetHeight(ktx.AvailableHeight - 2)
-		m.table.SetRows(m.rows)
+		m.table.SetRows(m.rows.all())
+		if m.scrollToNewest || m.cursorShift != 0 {
+			m.table.SetCursor(m.cursor())
+		}
+		m.scrollToNewest = false
+		m.cursorShift = 0
 
 		embeddedText := map[styles.BorderPosition]styles.EmbeddedTextFunc{
 			styles.TopMiddleBorder: func(active bool) string {
 				return lipgloss.NewStyle().
 					Foreground(lipgloss.Color(styles.ColorPink)).
 					Bold(true).
-					Render(fmt.Sprintf("Records: %d", len(m.rows)))
+					Render(fmt.Sprintf("Records: %d", m.rows.size()))
 			},
 		}
+		if m.readDetails.ConsumerGroup != "" {
+			embeddedText[styles.TopLeftBorder] = func(active bool) string {
+				group := "Group: " + m.readDetails.ConsumerGroup
+				if m.readDetails.CommitOffsets {
+					group += " (committing)"
+				}
+				return lipgloss.NewStyle().
+					Foreground(lipgloss.Color(styles.ColorGrey)).
+					Render(group)
+			}
+		}
+		if m.paused {
+			embeddedText[styles.TopRightBorder] = func(active bool) string {
+				return lipgloss.NewStyle().
+					Foreground(lipgloss.Color(styles.ColorYellow)).
+					Bold(true).
+					Render(fmt.Sprintf("⏸ Paused (+%d)", len(m.pending)))
+			}
+		}
+		if m.consuming {
+			embeddedText[styles.BottomMiddleBorder] = func(active bool) string {
+				return lipgloss.NewStyle().
+					Foreground(lipgloss.Color(styles.ColorGrey)).
+					Render(m.throughput.String())
+			}
+		}
 		borderedView := styles.Borderize(m.table.View(), true, embeddedText)
 		views = append(views, borderedView)
 	}
@@ -81,6 +159,13 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.columnsEditor.active {
+			if m.columnsEditor.Update(msg) {
+				m.setColumns(m.columnsEditor.columns)
+			}
+			return tea.Batch(cmds...)
+		}
+
 		if msg.String() == "esc" {
 			m.cancelConsumption()
 			if m.readDetails.StartPoint == kadmin.Live {
@@ -91,13 +176,53 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			m.cancelConsumption()
 			m.consuming = false
 			cmds = append(cmds, ui.PublishMsg(ConsumptionEndedMsg{}))
+		} else if msg.String() == "ctrl+e" {
+			if m.records.size() > 0 {
+				return ui.PublishMsg(nav.LoadExportPageMsg{
+					Records:     m.consumedRecords(),
+					ReadDetails: m.readDetails,
+				})
+			}
+		} else if msg.String() == " " {
+			if m.records.size() > 0 {
+				m.toggleMark()
+			}
+		} else if msg.String() == "R" {
+			if m.records.size() > 0 {
+				return ui.PublishMsg(nav.LoadReplayPageMsg{
+					Records:     m.consumedRecords(),
+					Marked:      m.markedRecords(),
+					ReadDetails: m.readDetails,
+				})
+			}
+		} else if msg.String() == "D" {
+			if marked := m.markedRecords(); len(marked) == 2 {
+				return ui.PublishMsg(nav.LoadRecordDiffPageMsg{
+					Left:      marked[0],
+					Right:     marked[1],
+					TopicName: m.readDetails.TopicName,
+				})
+			}
+		} else if msg.String() == "c" {
+			if !m.noRecordsAvailable {
+				m.columnsEditor.open(m.columns)
+			}
+		} else if msg.String() == "p" {
+			m.togglePause()
+		} else if msg.String() == "a" {
+			m.autoScroll = !m.autoScroll
+			if m.autoScroll {
+				m.scrollToNewest = true
+			}
 		} else if msg.String() == "enter" {
-			if len(m.records) > 0 {
-				selectedRow := m.records[len(m.records)-m.table.Cursor()-1]
+			if m.records.size() > 0 {
+				selectedRow := m.records.at(m.cursor())
+				m.markDisplayed(selectedRow)
 				m.consuming = false
 				return ui.PublishMsg(nav.LoadRecordDetailPageMsg{
-					Record:    &selectedRow,
-					TopicName: m.readDetails.TopicName,
+					Record:         &selectedRow,
+					TopicName:      m.readDetails.TopicName,
+					PartitionCount: m.topic.PartitionCount,
 				})
 			}
 		} else {
@@ -113,55 +238,248 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		m.consumerRecordChan = msg.ConsumerRecord
 		m.emptyTopicChan = msg.EmptyTopic
 		m.errChan = msg.Err
-		cmds = append(cmds, m.waitForActivity())
+		m.markRead = msg.MarkRead
+		m.throughput.measure(time.Now())
+		cmds = append(cmds, m.waitForActivity(), m.tickThroughput())
 	case ConsumptionEndedMsg:
 		m.consuming = false
 		return nil
+	case kadmin.OffsetCommitError:
+		m.commitErr = msg
+		return m.waitForActivity()
+	case throughputTickMsg:
+		if msg.page != m || !m.consuming {
+			return nil
+		}
+		m.throughput.measure(msg.time)
+		return m.tickThroughput()
 	case ConsumerRecordReceived:
-		var key string
-		if msg.Record.Key == "" {
-			key = "<null>"
+		m.throughput.add(msg.Record)
+		if m.paused {
+			m.pending = append(m.pending, msg.Record)
+			if len(m.pending) > m.bufferSize {
+				m.pending = m.pending[len(m.pending)-m.bufferSize:]
+			}
 		} else {
-			key = msg.Record.Key
-		}
-		m.records = append(m.records, msg.Record)
-		m.rows = append(
-			[]table.Row{
-				{
-					key,
-					msg.Record.Timestamp.Format("2006-01-02 15:04:05"),
-					strconv.FormatInt(msg.Record.Partition, 10),
-					strconv.FormatInt(msg.Record.Offset, 10),
-				},
-			},
-			m.rows...,
-		)
+			m.addRecords(msg.Record)
+		}
 		return m.waitForActivity()
 	}
 
 	return tea.Batch(cmds...)
 }
 
+func (m *Model) togglePause() {
+	m.paused = !m.paused
+	if !m.paused {
+		m.addRecords(m.pending...)
+		m.pending = nil
+	}
+}
+
+// addRecords adds the records and drops the oldest ones when the buffer size is exceeded.
+func (m *Model) addRecords(records ...kadmin.ConsumerRecord) {
+	if len(records) == 0 {
+		return
+	}
+
+	// the most recent record is shown on top
+	for _, record := range records {
+		m.records.push(record)
+		m.markDisplayed(record)
+		if dropped := m.rows.push(m.recordRow(record, false)); dropped {
+			delete(m.marked, m.added-m.bufferSize)
+		}
+		m.added++
+	}
+
+	if m.autoScroll {
+		m.scrollToNewest = true
+	} else {
+		m.cursorShift += len(records)
+	}
+}
+
+// markDisplayed marks the record as read by the consumer group, as it is displayed or opened.
+func (m *Model) markDisplayed(record kadmin.ConsumerRecord) {
+	if m.markRead != nil {
+		m.markRead(record)
+	}
+}
+
+// transactionIndicators prefix the rows of records produced in a transaction, when transactions are shown.
+var transactionIndicators = map[kadmin.TransactionState]string{
+	kadmin.CommittedTransaction: "✓",
+	kadmin.OpenTransaction:      "◌",
+	kadmin.AbortedTransaction:   "✗",
+	kadmin.CommitMarker:         "⚑",
+	kadmin.AbortMarker:          "⚑",
+}
+
+func (m *Model) recordRow(record kadmin.ConsumerRecord, marked bool) table.Row {
+	value := decodeJsonValue(record)
+	row := make(table.Row, len(m.columns))
+	for i, c := range m.columns {
+		row[i] = c.cell(record, value, m.headerDecoder)
+	}
+	if len(record.Payload.Violations) > 0 {
+		row[0] = "⚠ " + row[0]
+	}
+	if record.Tombstone {
+		row[0] = "∅ " + row[0]
+	}
+	if indicator, ok := transactionIndicators[record.Transaction]; ok {
+		row[0] = indicator + " " + row[0]
+	}
+	if marked {
+		row[0] = "● " + row[0]
+	}
+	return row
+}
+
+// setColumns changes the columns of the table and remembers them for the topic.
+func (m *Model) setColumns(columns []column) {
+	m.columns = slices.Clone(columns)
+	m.columnsErr = nil
+	for i := 0; i < m.records.size(); i++ {
+		m.rows.set(i, m.recordRow(m.records.at(i), m.marked[m.number(i)]))
+	}
+	// the table panics when the rows have more cells than there are columns
+	m.table.SetRows(nil)
+
+	if m.saveColumns != nil {
+		if isDefaultLayout(m.columns) {
+			m.saveColumns(nil)
+		} else {
+			m.saveColumns(columnSpecs(m.columns))
+		}
+	}
+}
+
+// tableColumns divides the width over the columns according to their weight.
+func (m *Model) tableColumns(width int) []table.Column {
+	// each column is padded by 2 and the table has a border of 1
+	available := width - 1 - 2*len(m.columns)
+	var totalWeight int
+	for _, c := range m.columns {
+		totalWeight += c.weight()
+	}
+
+	columns := make([]table.Column, len(m.columns))
+	for i, c := range m.columns {
+		columns[i] = table.Column{Title: c.title(), Width: available * c.weight() / totalWeight}
+	}
+	return columns
+}
+
+func (m *Model) tickThroughput() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return throughputTickMsg{page: m, time: t}
+	})
+}
+
+// cursor is the selected row, including the records added since the table was last rendered.
+func (m *Model) cursor() int {
+	cursor := m.table.Cursor() + m.cursorShift
+	if m.scrollToNewest {
+		cursor = 0
+	}
+	return max(0, min(cursor, m.rows.size()-1))
+}
+
+// number is the number of the i-th newest record.
+func (m *Model) number(i int) int {
+	return m.added - i - 1
+}
+
+func (m *Model) toggleMark() {
+	cursor := m.cursor()
+	number := m.number(cursor)
+	if m.marked[number] {
+		delete(m.marked, number)
+	} else {
+		m.marked[number] = true
+	}
+	m.rows.set(cursor, m.recordRow(m.records.at(cursor), m.marked[number]))
+}
+
+// consumedRecords returns the records in the order they were consumed.
+func (m *Model) consumedRecords() []kadmin.ConsumerRecord {
+	records := make([]kadmin.ConsumerRecord, 0, m.records.size())
+	for i := m.records.size() - 1; i >= 0; i-- {
+		records = append(records, m.records.at(i))
+	}
+	return records
+}
+
+// markedRecords returns the marked records in the order they were consumed.
+func (m *Model) markedRecords() []kadmin.ConsumerRecord {
+	var marked []kadmin.ConsumerRecord
+	for i := m.records.size() - 1; i >= 0; i-- {
+		if m.marked[m.number(i)] {
+			marked = append(marked, m.records.at(i))
+		}
+	}
+	return marked
+}
+
 func (m *Model) waitForActivity() tea.Cmd {
 	return func() tea.Msg {
 		select {
 		case record, ok := <-m.consumerRecordChan:
 			if !ok {
+				// errors, like failing to commit the offsets, can be reported right before the end
+				if err, ok := <-m.errChan; ok && err != nil {
+					return err
+				}
 				return ConsumptionEndedMsg{}
 			}
 			return ConsumerRecordReceived{Record: record}
 		case <-m.emptyTopicChan:
 			return EmptyTopicMsg{}
-		case err := <-m.errChan:
-			return err
+		case err, ok := <-m.errChan:
+			if ok {
+				return err
+			}
+			// Err is closed along with the records, which may still hold records
+			record, ok := <-m.consumerRecordChan
+			if !ok {
+				return ConsumptionEndedMsg{}
+			}
+			return ConsumerRecordReceived{Record: record}
 		}
 	}
 }
 
 func (m *Model) Shortcuts() []statusbar.Shortcut {
+	if m.columnsEditor.active {
+		return m.columnsEditor.Shortcuts()
+	}
+	// two marked records can be compared
+	if len(m.marked) == 2 {
+		return slices.Insert(m.recordsShortcuts(), 2, statusbar.Shortcut{"Diff Marked", "S-d"})
+	}
+	return m.recordsShortcuts()
+}
+
+func (m *Model) recordsShortcuts() []statusbar.Shortcut {
 	if m.consuming {
+		pause := statusbar.Shortcut{"Pause", "p"}
+		if m.paused {
+			pause = statusbar.Shortcut{"Resume", "p"}
+		}
+		autoScroll := statusbar.Shortcut{"Disable Auto-scroll", "a"}
+		if !m.autoScroll {
+			autoScroll = statusbar.Shortcut{"Enable Auto-scroll", "a"}
+		}
 		return []statusbar.Shortcut{
 			{"View Record", "enter"},
+			{"Mark", "space"},
+			{"Export", "C-e"},
+			{"Replay", "S-r"},
+			{"Edit Columns", "c"},
+			pause,
+			autoScroll,
 			{"Stop consuming", "F2"},
 			{"Go Back", "esc"},
 		}
@@ -172,6 +490,10 @@ func (m *Model) Shortcuts() []statusbar.Shortcut {
 	} else {
 		return []statusbar.Shortcut{
 			{"View Record", "enter"},
+			{"Mark", "space"},
+			{"Export", "C-e"},
+			{"Replay", "S-r"},
+			{"Edit Columns", "c"},
 			{"Go Back", "esc"},
 		}
 	}
@@ -185,6 +507,7 @@ func New(
 	reader kadmin.RecordReader,
 	readDetails kadmin.ReadDetails,
 	topic *kadmin.ListedTopic,
+	options ...Option,
 ) (nav.Page, tea.Cmd) {
 	m := &Model{}
 
@@ -193,10 +516,23 @@ func New(
 		table.WithStyles(styles.Table.Styles),
 	)
 	m.table = &t
+	m.marked = make(map[int]bool)
 	m.reader = reader
 	m.cmdBar = NewConsumptionCmdbar()
 	m.readDetails = readDetails
 	m.topic = topic
+	m.bufferSize = defaultBufferSize
+	m.autoScroll = true
+	m.columnsEditor = &columnsEditor{}
+	m.headerDecoder = func(string) kadmin.HeaderDecoder { return kadmin.AutoHeaderDecoder }
+	for _, option := range options {
+		option(m)
+	}
+	if m.columns == nil {
+		m.columns = defaultLayout()
+	}
+	m.rows = newRing[table.Row](m.bufferSize)
+	m.records = newRing[kadmin.ConsumerRecord](m.bufferSize)
 
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	m.cancelConsumption = cancelFunc
@@ -205,3 +541,37 @@ func New(
 		return m.reader.ReadRecords(ctx, readDetails)
 	}
 }
+
+// WithBufferSize limits the number of records kept to size, non positive sizes are ignored.
+func WithBufferSize(size int) Option {
+	return func(m *Model) {
+		if size > 0 {
+			m.bufferSize = size
+		}
+	}
+}
+
+// WithColumns shows the columns, defined by their specs, instead of the default columns.
+// Invalid specs are left out and reported on the page.
+func WithColumns(specs []string) Option {
+	return func(m *Model) {
+		if len(specs) > 0 {
+			m.columns, m.columnsErr = parseColumns(specs)
+		}
+	}
+}
+
+// WithHeaderDecoders decodes header columns with the decoder configured for their key.
+func WithHeaderDecoders(decoder func(key string) kadmin.HeaderDecoder) Option {
+	return func(m *Model) {
+		m.headerDecoder = decoder
+	}
+}
+
+// WithColumnsSaver remembers the column layout whenever it is changed,
+// nil is passed when the default layout is restored.
+func WithColumnsSaver(save func(specs []string)) Option {
+	return func(m *Model) {
+		m.saveColumns = save
+	}
+}
@@ -1,11 +1,16 @@
 package consumption_page
 
 import (
+	"github.com/IBM/sarama"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
 	"ktea/kadmin"
+	"ktea/serdes"
 	"ktea/tests"
 	"ktea/ui/components/statusbar"
+	"ktea/ui/pages/nav"
 	"testing"
+	"time"
 )
 
 func TestConsumptionPage(t *testing.T) {
@@ -20,4 +25,382 @@ func TestConsumptionPage(t *testing.T) {
 
 		assert.Equal(t, []statusbar.Shortcut{{"Go Back", "esc"}}, m.Shortcuts())
 	})
+	t.Run("Display that a consumer group has no records left", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{ConsumerGroup: "order-processor"}, &kadmin.ListedTopic{})
+
+		m.Update(EmptyTopicMsg{})
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+
+		assert.Contains(t, render, "No records left to consume for order-processor")
+	})
+	t.Run("Display the consumer group that is consumed as", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{
+			ConsumerGroup: "order-processor",
+			CommitOffsets: true,
+		}, &kadmin.ListedTopic{})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+
+		assert.Contains(t, render, "Group: order-processor (committing)")
+	})
+	t.Run("Report failing offset commits and keep consuming", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{
+			ConsumerGroup: "order-processor",
+			CommitOffsets: true,
+		}, &kadmin.ListedTopic{})
+		records := make(chan kadmin.ConsumerRecord, 1)
+		errs := make(chan error, 1)
+		m.Update(kadmin.ReadingStartedMsg{ConsumerRecord: records, Err: errs})
+
+		cmd := m.Update(kadmin.OffsetCommitError{Group: "order-processor", Err: sarama.ErrUnknownMemberId})
+		records <- kadmin.ConsumerRecord{Key: "1"}
+		m.Update(cmd())
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+		assert.Contains(t, render, "unable to commit the offsets of order-processor, the group has active members")
+		assert.Contains(t, render, "1")
+
+		// a failure of the last commit is reported before the consumption ends
+		errs <- kadmin.OffsetCommitError{Group: "order-processor", Err: sarama.ErrRequestTimedOut}
+		close(records)
+		close(errs)
+		msg := m.(*Model).waitForActivity()()
+		assert.IsType(t, kadmin.OffsetCommitError{}, msg)
+		assert.IsType(t, ConsumptionEndedMsg{}, m.Update(msg)())
+	})
+	t.Run("Mark records as read once they are displayed", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{
+			ConsumerGroup: "order-processor",
+			CommitOffsets: true,
+		}, &kadmin.ListedTopic{})
+		var marked []string
+		m.Update(kadmin.ReadingStartedMsg{MarkRead: func(record kadmin.ConsumerRecord) {
+			marked = append(marked, record.Key)
+		}})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+		m.Update(tests.Key('p'))
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2"}})
+
+		// records received while paused are not displayed yet
+		assert.Equal(t, []string{"1"}, marked)
+
+		m.Update(tests.Key('p'))
+		assert.Equal(t, []string{"1", "2"}, marked)
+
+		m.View(tests.NewKontext(), tests.TestRenderer)
+		m.Update(tests.Key(tea.KeyEnter))
+		assert.Equal(t, []string{"1", "2", "2"}, marked)
+	})
+	t.Run("C-e exports the loaded records", func(t *testing.T) {
+		readDetails := kadmin.ReadDetails{TopicName: "topic1"}
+		m, _ := New(nil, readDetails, &kadmin.ListedTopic{})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2"}})
+
+		cmd := m.Update(tests.Key(tea.KeyCtrlE))
+
+		assert.Equal(t, nav.LoadExportPageMsg{
+			Records:     []kadmin.ConsumerRecord{{Key: "1"}, {Key: "2"}},
+			ReadDetails: readDetails,
+		}, cmd())
+	})
+	t.Run("S-r replays the marked records", func(t *testing.T) {
+		readDetails := kadmin.ReadDetails{TopicName: "topic1"}
+		m, _ := New(nil, readDetails, &kadmin.ListedTopic{})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2"}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "3"}})
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		// most recent record is on top
+		m.Update(tests.Key(' '))
+		m.Update(tests.Key(tea.KeyDown))
+		m.Update(tests.Key(' '))
+		m.Update(tests.Key(tea.KeyDown))
+		m.Update(tests.Key(' '))
+		m.Update(tests.Key(' '))
+
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "● 3")
+
+		cmd := m.Update(tests.Key('R'))
+
+		assert.Equal(t, nav.LoadReplayPageMsg{
+			Records:     []kadmin.ConsumerRecord{{Key: "1"}, {Key: "2"}, {Key: "3"}},
+			Marked:      []kadmin.ConsumerRecord{{Key: "2"}, {Key: "3"}},
+			ReadDetails: readDetails,
+		}, cmd())
+	})
+	t.Run("Flag records with schema violations", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{
+			Key:     "1",
+			Payload: serdes.DesData{Violations: []string{"/: missing properties: 'name'"}},
+		}})
+
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "⚠ 1")
+	})
+	t.Run("Flag transactional records and show transaction markers", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{ShowTransactions: true}, &kadmin.ListedTopic{})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1", Transaction: kadmin.CommittedTransaction}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2", Transaction: kadmin.AbortedTransaction}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Transaction: kadmin.AbortMarker}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "3", Transaction: kadmin.OpenTransaction}})
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+
+		assert.Contains(t, render, "✓ 1")
+		assert.Contains(t, render, "✗ 2")
+		assert.Contains(t, render, "⚑ <abort marker>")
+		assert.Contains(t, render, "◌ 3")
+	})
+	t.Run("Flag tombstones", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1", Tombstone: true}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2"}})
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+
+		assert.Contains(t, render, "∅ 1")
+		assert.NotContains(t, render, "∅ 2")
+	})
+	t.Run("Drop the oldest records once the buffer size is reached", func(t *testing.T) {
+		readDetails := kadmin.ReadDetails{TopicName: "topic1"}
+		m, _ := New(nil, readDetails, &kadmin.ListedTopic{}, WithBufferSize(2))
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2"}})
+		m.View(tests.NewKontext(), tests.TestRenderer)
+		// mark record 2
+		m.Update(tests.Key(' '))
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "3"}})
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+		assert.Contains(t, render, "Records: 2")
+		assert.Contains(t, render, "● 2")
+
+		cmd := m.Update(tests.Key('R'))
+
+		assert.Equal(t, nav.LoadReplayPageMsg{
+			Records:     []kadmin.ConsumerRecord{{Key: "2"}, {Key: "3"}},
+			Marked:      []kadmin.ConsumerRecord{{Key: "2"}},
+			ReadDetails: readDetails,
+		}, cmd())
+	})
+	t.Run("Forget the marks of dropped records", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{}, WithBufferSize(2))
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+		m.View(tests.NewKontext(), tests.TestRenderer)
+		// mark record 1
+		m.Update(tests.Key(' '))
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2"}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "3"}})
+
+		assert.Empty(t, m.(*Model).markedRecords())
+		assert.NotContains(t, m.View(tests.NewKontext(), tests.TestRenderer), "●")
+	})
+	t.Run("Keep reading while paused", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{}, WithBufferSize(3))
+		m.Update(kadmin.ReadingStartedMsg{})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+		m.Update(tests.Key('p'))
+
+		assert.Contains(t, m.Shortcuts(), statusbar.Shortcut{"Resume", "p"})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2"}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "3"}})
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+		assert.Contains(t, render, "Records: 1")
+		assert.Contains(t, render, "Paused (+2)")
+
+		m.Update(tests.Key('p'))
+
+		render = m.View(tests.NewKontext(), tests.TestRenderer)
+		assert.Contains(t, render, "Records: 3")
+		assert.NotContains(t, render, "Paused")
+		assert.Contains(t, m.Shortcuts(), statusbar.Shortcut{"Pause", "p"})
+	})
+	t.Run("Auto-scroll selects the most recent record", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{})
+		m.Update(kadmin.ReadingStartedMsg{})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2"}})
+		m.View(tests.NewKontext(), tests.TestRenderer)
+		m.Update(tests.Key(tea.KeyDown))
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "3"}})
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		assert.Equal(t, "3", cmd().(nav.LoadRecordDetailPageMsg).Record.Key)
+	})
+	t.Run("Keep the selected record when auto-scroll is disabled", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{})
+		m.Update(kadmin.ReadingStartedMsg{})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2"}})
+		m.View(tests.NewKontext(), tests.TestRenderer)
+		m.Update(tests.Key('a'))
+		m.Update(tests.Key(tea.KeyDown))
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		assert.Contains(t, m.Shortcuts(), statusbar.Shortcut{"Enable Auto-scroll", "a"})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "3"}})
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		assert.Equal(t, "1", cmd().(nav.LoadRecordDetailPageMsg).Record.Key)
+	})
+	t.Run("Select the record under the cursor before the new records are rendered", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{})
+		m.Update(kadmin.ReadingStartedMsg{})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2"}})
+		m.View(tests.NewKontext(), tests.TestRenderer)
+		m.Update(tests.Key('a'))
+		m.Update(tests.Key(tea.KeyDown))
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "3"}})
+		m.Update(tests.Key(' '))
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+
+		assert.Equal(t, "1", cmd().(nav.LoadRecordDetailPageMsg).Record.Key)
+		assert.Equal(t, []kadmin.ConsumerRecord{{Key: "1"}}, m.(*Model).markedRecords())
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "● 1")
+	})
+	t.Run("Display the throughput while consuming", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{})
+		m.Update(kadmin.ReadingStartedMsg{})
+		page := m.(*Model)
+		start := time.Now()
+		page.throughput.measure(start)
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1", RawValue: make([]byte, 1000)}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2", RawValue: make([]byte, 1000)}})
+		m.Update(throughputTickMsg{page: page, time: start.Add(time.Second)})
+
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "2 records/s, 2.0 kB/s")
+	})
+	t.Run("Edit columns", func(t *testing.T) {
+		var saved []string
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{}, WithColumnsSaver(func(specs []string) {
+			saved = specs
+		}))
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{
+			Key:     "order-1",
+			Payload: serdes.DesData{Value: `{"order":{"status":"SHIPPED"}}`},
+			Headers: []kadmin.Header{{Key: "trace", Value: kadmin.NewHeaderValue("t-42")}},
+		}})
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		m.Update(tests.Key('c'))
+		assert.Contains(t, m.Shortcuts(), statusbar.Shortcut{"Add", "+"})
+
+		// add a JSON path column after the key
+		m.Update(tests.Key('+'))
+		tests.UpdateKeys(m, "$.order.status")
+		m.Update(tests.Key(tea.KeyEnter))
+		// add a header column after the JSON path column
+		m.Update(tests.Key('+'))
+		tests.UpdateKeys(m, "header:trace")
+		m.Update(tests.Key(tea.KeyEnter))
+		// move the header column in front of the JSON path column
+		m.Update(tests.Key(tea.KeyShiftLeft))
+		// remove the timestamp column
+		m.Update(tests.Key(tea.KeyRight))
+		m.Update(tests.Key(tea.KeyRight))
+		m.Update(tests.Key(tea.KeyDelete))
+		m.Update(tests.Key(tea.KeyEsc))
+
+		assert.Equal(t, []string{"key", "header:trace", "$.order.status", "partition", "offset"}, saved)
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+		assert.Contains(t, render, "$.order.status")
+		assert.Contains(t, render, "SHIPPED")
+		assert.Contains(t, render, "t-42")
+		assert.NotContains(t, render, "Timestamp")
+	})
+	t.Run("Show an error for invalid columns", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+
+		m.Update(tests.Key('c'))
+		m.Update(tests.Key('+'))
+		tests.UpdateKeys(m, "value")
+		m.Update(tests.Key(tea.KeyEnter))
+
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "unknown column")
+	})
+	t.Run("Leave out and report invalid configured columns", func(t *testing.T) {
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{},
+			WithColumns([]string{"offset", "value", "key"}),
+		)
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+
+		assert.Equal(t, []string{"offset", "key"}, columnSpecs(m.(*Model).columns))
+		assert.Contains(t, render, `Invalid columns left out: unknown column "value"`)
+	})
+	t.Run("Restore the default columns", func(t *testing.T) {
+		saved := []string{"unchanged"}
+		m, _ := New(nil, kadmin.ReadDetails{}, &kadmin.ListedTopic{},
+			WithColumns([]string{"offset", "key"}),
+			WithColumnsSaver(func(specs []string) {
+				saved = specs
+			}),
+		)
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+
+		m.Update(tests.Key('c'))
+		m.Update(tests.Key(tea.KeyCtrlR))
+
+		assert.Nil(t, saved)
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "Timestamp")
+	})
+	t.Run("S-d compares the two marked records", func(t *testing.T) {
+		readDetails := kadmin.ReadDetails{TopicName: "topic1"}
+		m, _ := New(nil, readDetails, &kadmin.ListedTopic{})
+
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "1"}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "2"}})
+		m.Update(ConsumerRecordReceived{Record: kadmin.ConsumerRecord{Key: "3"}})
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		m.Update(tests.Key(' '))
+		assert.Nil(t, m.Update(tests.Key('D')))
+
+		m.Update(tests.Key(tea.KeyDown))
+		m.Update(tests.Key(tea.KeyDown))
+		m.Update(tests.Key(' '))
+
+		assert.Contains(t, m.Shortcuts(), statusbar.Shortcut{"Diff Marked", "S-d"})
+
+		cmd := m.Update(tests.Key('D'))
+
+		assert.Equal(t, nav.LoadRecordDiffPageMsg{
+			Left:      kadmin.ConsumerRecord{Key: "1"},
+			Right:     kadmin.ConsumerRecord{Key: "3"},
+			TopicName: "topic1",
+		}, cmd())
+	})
 }
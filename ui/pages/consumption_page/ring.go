@@ -0,0 +1,56 @@
+package consumption_page
+
+// ring keeps the most recent items, up to its capacity, newest first. Every item is stored twice, at
+// its index and capacity further, so the kept items always are a contiguous part of the backing slice
+// and adding an item never moves the others.
+type ring[T any] struct {
+	items    []T
+	capacity int
+	// head is the index of the newest item
+	head int
+	len  int
+}
+
+func newRing[T any](capacity int) *ring[T] {
+	return &ring[T]{capacity: capacity}
+}
+
+// push adds the item as the newest one and reports whether the oldest item was dropped to make room.
+func (r *ring[T]) push(item T) bool {
+	if r.items == nil {
+		// only allocated once there is something to keep
+		r.items = make([]T, 2*r.capacity)
+	}
+	r.head = (r.head - 1 + r.capacity) % r.capacity
+	r.items[r.head] = item
+	r.items[r.head+r.capacity] = item
+	if r.len == r.capacity {
+		return true
+	}
+	r.len++
+	return false
+}
+
+// at returns the i-th newest item.
+func (r *ring[T]) at(i int) T {
+	return r.items[r.head+i]
+}
+
+// set replaces the i-th newest item.
+func (r *ring[T]) set(i int, item T) {
+	idx := (r.head + i) % r.capacity
+	r.items[idx] = item
+	r.items[idx+r.capacity] = item
+}
+
+// all returns the items, newest first, without copying them.
+func (r *ring[T]) all() []T {
+	if r.len == 0 {
+		return nil
+	}
+	return r.items[r.head : r.head+r.len]
+}
+
+func (r *ring[T]) size() int {
+	return r.len
+}
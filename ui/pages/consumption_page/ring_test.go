@@ -0,0 +1,45 @@
+package consumption_page
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	t.Run("Keep the newest items first", func(t *testing.T) {
+		r := newRing[int](3)
+
+		assert.Nil(t, r.all())
+		assert.False(t, r.push(1))
+		assert.False(t, r.push(2))
+
+		assert.Equal(t, []int{2, 1}, r.all())
+		assert.Equal(t, 2, r.at(0))
+	})
+
+	t.Run("Drop the oldest items once full", func(t *testing.T) {
+		r := newRing[int](3)
+		for i := 1; i <= 3; i++ {
+			assert.False(t, r.push(i))
+		}
+
+		assert.True(t, r.push(4))
+		assert.True(t, r.push(5))
+
+		assert.Equal(t, []int{5, 4, 3}, r.all())
+		assert.Equal(t, 3, r.size())
+		assert.Equal(t, 3, r.at(2))
+	})
+
+	t.Run("Replace items across the end of the buffer", func(t *testing.T) {
+		r := newRing[int](3)
+		for i := 1; i <= 5; i++ {
+			r.push(i)
+		}
+
+		r.set(0, 50)
+		r.set(2, 30)
+
+		assert.Equal(t, []int{50, 4, 30}, r.all())
+	})
+}
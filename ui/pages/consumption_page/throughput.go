@@ -0,0 +1,40 @@
+package consumption_page
+
+import (
+	"fmt"
+	"github.com/dustin/go-humanize"
+	"ktea/kadmin"
+	"time"
+)
+
+// throughput measures the rate records are consumed at over windows of roughly a second.
+type throughput struct {
+	windowStart   time.Time
+	records       int
+	bytes         int
+	recordsPerSec float64
+	bytesPerSec   float64
+}
+
+func (t *throughput) add(record kadmin.ConsumerRecord) {
+	t.records++
+	t.bytes += len(record.RawKey) + len(record.RawValue)
+}
+
+// measure calculates the rates of the window that ended at now and starts a new one.
+func (t *throughput) measure(now time.Time) {
+	if !t.windowStart.IsZero() {
+		elapsed := now.Sub(t.windowStart).Seconds()
+		if elapsed > 0 {
+			t.recordsPerSec = float64(t.records) / elapsed
+			t.bytesPerSec = float64(t.bytes) / elapsed
+		}
+	}
+	t.windowStart = now
+	t.records = 0
+	t.bytes = 0
+}
+
+func (t *throughput) String() string {
+	return fmt.Sprintf("%.0f records/s, %s/s", t.recordsPerSec, humanize.Bytes(uint64(t.bytesPerSec)))
+}
@@ -0,0 +1,216 @@
+package export_page
+
+import (
+	"context"
+	"fmt"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"ktea/kadmin"
+	"ktea/kontext"
+	"ktea/styles"
+	"ktea/ui"
+	"ktea/ui/components/cmdbar"
+	"ktea/ui/components/notifier"
+	"ktea/ui/components/statusbar"
+	"ktea/ui/pages/nav"
+	"time"
+)
+
+type state int
+
+const (
+	none      state = 0
+	exporting state = 1
+)
+
+type source int
+
+const (
+	loadedRecordsSource source = 0
+	topicSource         source = 1
+)
+
+type Model struct {
+	state       state
+	form        *huh.Form
+	formValues  *formValues
+	exporter    kadmin.RecordExporter
+	records     []kadmin.ConsumerRecord
+	readDetails kadmin.ReadDetails
+	notifier    *notifier.Model
+	// cancel stops streaming the records from the topic
+	cancel context.CancelFunc
+}
+
+type formValues struct {
+	format kadmin.ExportFormat
+	path   string
+	source source
+}
+
+func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
+	notifierView := m.notifier.View(ktx, renderer)
+	if notifierView != "" {
+		notifierView = styles.CmdBarWithWidth(ktx.WindowWidth - cmdbar.BorderedPadding).Render(notifierView)
+	}
+
+	if m.form == nil {
+		m.form = m.newForm(ktx)
+	}
+
+	return ui.JoinVertical(lipgloss.Top,
+		notifierView,
+		renderer.RenderWithStyle(m.form.View(), styles.Form),
+	)
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case spinner.TickMsg, notifier.HideNotificationMsg:
+		return m.notifier.Update(msg)
+	case kadmin.ExportStartedMsg:
+		return msg.AwaitCompletion
+	case kadmin.RecordsExportedMsg:
+		m.cancel()
+		m.state = none
+		m.form = nil
+		m.notifier.ShowSuccessMsg(fmt.Sprintf("%d records exported to %s", msg.Count, msg.Path))
+		return m.notifier.AutoHideCmd("")
+	case kadmin.RecordsExportErrMsg:
+		m.cancel()
+		m.state = none
+		m.form = nil
+		return m.notifier.ShowErrorMsg("Export failed", msg.Err)
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyEsc {
+			if m.state == exporting {
+				// the records streamed so far are exported
+				m.cancel()
+				return nil
+			}
+			return ui.PublishMsg(nav.LoadCachedConsumptionPageMsg{})
+		}
+	}
+
+	if m.form == nil || m.state == exporting {
+		return nil
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	if m.form.State == huh.StateCompleted {
+		m.state = exporting
+		m.form.State = huh.StateNormal
+		return tea.Batch(
+			m.notifier.SpinWithLoadingMsg("Exporting records"),
+			m.export(),
+		)
+	}
+
+	return cmd
+}
+
+func (m *Model) export() tea.Cmd {
+	details := kadmin.ExportDetails{
+		Format: m.formValues.format,
+		Path:   m.formValues.path,
+	}
+	if details.Path == "" {
+		details.Path = m.defaultPath()
+	}
+	if m.formValues.source == topicSource {
+		details.ReadDetails = &m.readDetails
+	} else {
+		details.Records = m.records
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	return func() tea.Msg {
+		return m.exporter.ExportRecords(ctx, details)
+	}
+}
+
+func (m *Model) defaultPath() string {
+	return fmt.Sprintf(
+		"%s-%s.%s",
+		m.readDetails.TopicName,
+		time.Now().Format("20060102150405"),
+		m.formValues.format,
+	)
+}
+
+func (m *Model) newForm(ktx *kontext.ProgramKtx) *huh.Form {
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[kadmin.ExportFormat]().
+				Value(&m.formValues.format).
+				Title("Format").
+				Options(
+					huh.NewOption("JSON Lines", kadmin.JsonLinesExportFormat),
+					huh.NewOption("CSV", kadmin.CsvExportFormat),
+					huh.NewOption("Avro Object Container File", kadmin.AvroExportFormat)),
+			huh.NewSelect[source]().
+				Value(&m.formValues.source).
+				Title("Records").
+				Options(m.sourceOptions()...),
+			huh.NewInput().
+				Value(&m.formValues.path).
+				Title("File").
+				Description("Leave empty to export to <topic>-<timestamp>.<format> in the working directory."),
+		).WithWidth(ktx.WindowWidth / 2),
+	)
+	form.QuitAfterSubmit = false
+	form.Init()
+	return form
+}
+
+// sourceOptions offer streaming from the topic unless the consumption would never end.
+func (m *Model) sourceOptions() []huh.Option[source] {
+	options := []huh.Option[source]{
+		huh.NewOption(fmt.Sprintf("Loaded records (%d)", len(m.records)), loadedRecordsSource),
+	}
+	if !m.readDetails.Unbounded() {
+		options = append(options, huh.NewOption("Stream from topic using the consumption details", topicSource))
+	}
+	return options
+}
+
+func (m *Model) Shortcuts() []statusbar.Shortcut {
+	if m.state == exporting {
+		return []statusbar.Shortcut{
+			{"Stop Export", "esc"},
+		}
+	}
+	return []statusbar.Shortcut{
+		{"Confirm", "enter"},
+		{"Next Field", "tab"},
+		{"Prev. Field", "s-tab"},
+		{"Go Back", "esc"},
+	}
+}
+
+func (m *Model) Title() string {
+	return "Topics / " + m.readDetails.TopicName + " / Records / Export"
+}
+
+func New(
+	exporter kadmin.RecordExporter,
+	records []kadmin.ConsumerRecord,
+	readDetails kadmin.ReadDetails,
+) *Model {
+	return &Model{
+		exporter:    exporter,
+		records:     records,
+		readDetails: readDetails,
+		notifier:    notifier.New(),
+		cancel:      func() {},
+		formValues: &formValues{
+			format: kadmin.JsonLinesExportFormat,
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package export_page
+
+import (
+	"context"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"ktea/kadmin"
+	"ktea/tests"
+	"ktea/ui/pages/nav"
+	"strings"
+	"testing"
+)
+
+type MockExporter struct {
+	ExportRecordsFunc func(ed kadmin.ExportDetails) tea.Msg
+}
+
+func (m *MockExporter) ExportRecords(_ context.Context, ed kadmin.ExportDetails) tea.Msg {
+	if m.ExportRecordsFunc != nil {
+		return m.ExportRecordsFunc(ed)
+	}
+	return nil
+}
+
+func TestExportPage(t *testing.T) {
+	records := []kadmin.ConsumerRecord{{Key: "1"}, {Key: "2"}}
+	readDetails := kadmin.ReadDetails{TopicName: "topic1", Limit: 50}
+
+	t.Run("esc goes back to the consumption page", func(t *testing.T) {
+		m := New(&MockExporter{}, records, readDetails)
+
+		cmd := m.Update(tests.Key(tea.KeyEsc))
+
+		assert.IsType(t, nav.LoadCachedConsumptionPageMsg{}, cmd())
+	})
+
+	t.Run("export loaded records as JSON Lines by default", func(t *testing.T) {
+		var details kadmin.ExportDetails
+		m := New(&MockExporter{
+			ExportRecordsFunc: func(ed kadmin.ExportDetails) tea.Msg {
+				details = ed
+				return nil
+			},
+		}, records, readDetails)
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		// format
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// records
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// file
+		tests.UpdateKeys(m, "dump.jsonl")
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next group and submit
+		cmd = m.Update(cmd())
+		tests.ExecuteBatchCmd(m.Update(cmd()))
+
+		assert.Equal(t, kadmin.JsonLinesExportFormat, details.Format)
+		assert.Equal(t, "dump.jsonl", details.Path)
+		assert.Equal(t, records, details.Records)
+		assert.Nil(t, details.ReadDetails)
+	})
+
+	t.Run("stream records from topic into a default file", func(t *testing.T) {
+		var details kadmin.ExportDetails
+		m := New(&MockExporter{
+			ExportRecordsFunc: func(ed kadmin.ExportDetails) tea.Msg {
+				details = ed
+				return nil
+			},
+		}, records, readDetails)
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		// format
+		m.Update(tests.Key(tea.KeyDown))
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// records
+		m.Update(tests.Key(tea.KeyDown))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// file
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next group and submit
+		cmd = m.Update(cmd())
+		tests.ExecuteBatchCmd(m.Update(cmd()))
+
+		assert.Equal(t, kadmin.CsvExportFormat, details.Format)
+		assert.True(t, strings.HasPrefix(details.Path, "topic1-"))
+		assert.True(t, strings.HasSuffix(details.Path, ".csv"))
+		assert.Nil(t, details.Records)
+		assert.Equal(t, &readDetails, details.ReadDetails)
+	})
+
+	t.Run("show amount of exported records", func(t *testing.T) {
+		m := New(&MockExporter{}, records, readDetails)
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		m.Update(kadmin.RecordsExportedMsg{Count: 2, Path: "dump.jsonl"})
+
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "2 records exported to dump.jsonl")
+	})
+
+	t.Run("only export loaded records of a live consumption", func(t *testing.T) {
+		var details kadmin.ExportDetails
+		m := New(&MockExporter{
+			ExportRecordsFunc: func(ed kadmin.ExportDetails) tea.Msg {
+				details = ed
+				return nil
+			},
+		}, records, kadmin.ReadDetails{TopicName: "topic1", StartPoint: kadmin.Live})
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+		assert.NotContains(t, render, "Stream from topic")
+
+		// format
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// records
+		m.Update(tests.Key(tea.KeyDown))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// file
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next group and submit
+		cmd = m.Update(cmd())
+		tests.ExecuteBatchCmd(m.Update(cmd()))
+
+		assert.Equal(t, records, details.Records)
+		assert.Nil(t, details.ReadDetails)
+	})
+
+	t.Run("esc stops streaming the records", func(t *testing.T) {
+		var cancelled bool
+		m := New(&MockExporter{}, records, readDetails)
+		m.View(tests.NewKontext(), tests.TestRenderer)
+		m.state = exporting
+		m.cancel = func() { cancelled = true }
+
+		cmd := m.Update(tests.Key(tea.KeyEsc))
+
+		assert.Nil(t, cmd)
+		assert.True(t, cancelled)
+		assert.Equal(t, exporting, m.state)
+	})
+}
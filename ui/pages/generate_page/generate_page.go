@@ -0,0 +1,416 @@
+package generate_page
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"ktea/kadmin"
+	"ktea/kontext"
+	"ktea/serdes"
+	"ktea/styles"
+	"ktea/ui"
+	"ktea/ui/components/cmdbar"
+	"ktea/ui/components/notifier"
+	"ktea/ui/components/statusbar"
+	"ktea/ui/pages/nav"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type state int
+
+const (
+	none       state = 0
+	generating state = 1
+	generated  state = 2
+)
+
+const defaultValueTemplate = `{"id":"{{uuid}}","seq":{{.Seq}},"amount":{{int 1 100}},"status":"{{pick "NEW" "PAID"}}","at":"{{now}}"}`
+
+type valueSource int
+
+const (
+	templateSource valueSource = iota
+	schemaSource
+)
+
+type Model struct {
+	state      state
+	form       *huh.Form
+	formValues *formValues
+	generator  kadmin.LoadGenerator
+	started    *kadmin.LoadStartedMsg
+	// cancel stops generating
+	cancel   context.CancelFunc
+	details  kadmin.LoadDetails
+	stats    kadmin.LoadStats
+	topic    *kadmin.ListedTopic
+	notifier *notifier.Model
+	progress progress.Model
+	// schemaRegistry enables generating values matching a schema
+	schemaRegistry bool
+}
+
+type Option func(m *Model)
+
+// WithSchemaRegistry enables generating values that match a schema of the Schema Registry.
+func WithSchemaRegistry() Option {
+	return func(m *Model) {
+		m.schemaRegistry = true
+	}
+}
+
+type formValues struct {
+	records       string
+	duration      string
+	rate          string
+	keyTemplate   string
+	valueSource   valueSource
+	subject       string
+	valueTemplate string
+	acks          kadmin.Acks
+	compression   kadmin.Compression
+}
+
+func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
+	notifierView := m.notifier.View(ktx, renderer)
+	if notifierView != "" {
+		notifierView = styles.CmdBarWithWidth(ktx.WindowWidth - cmdbar.BorderedPadding).Render(notifierView)
+	}
+
+	if m.state == none {
+		if m.form == nil {
+			m.form = m.newForm(ktx)
+		}
+		return ui.JoinVertical(lipgloss.Top,
+			notifierView,
+			renderer.RenderWithStyle(m.form.View(), styles.Form),
+		)
+	}
+
+	m.progress.Width = ktx.WindowWidth - 4
+	statsView := renderer.RenderWithStyle(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.progress.ViewAs(m.percent()),
+			"",
+			m.statsView(),
+		),
+		lipgloss.NewStyle().Padding(1),
+	)
+
+	return ui.JoinVertical(lipgloss.Top, notifierView, statsView)
+}
+
+// percent is the share of the records, or else the duration, that has been generated.
+func (m *Model) percent() float64 {
+	switch {
+	case m.state == generated:
+		return 1
+	case m.details.Records > 0:
+		return float64(m.stats.Published+m.stats.Failed) / float64(m.details.Records)
+	case m.details.Duration > 0:
+		return min(1, float64(m.stats.Elapsed)/float64(m.details.Duration))
+	}
+	return 0
+}
+
+func (m *Model) statsView() string {
+	label := lipgloss.NewStyle().Width(14).Bold(true)
+	line := func(name string, value string) string {
+		return label.Render(name) + value
+	}
+
+	lines := []string{
+		line("Published", strconv.Itoa(m.stats.Published)),
+		line("Failed", strconv.Itoa(m.stats.Failed)),
+		line("Elapsed", m.stats.Elapsed.Round(time.Millisecond).String()),
+		line("Throughput", fmt.Sprintf("%.1f records/s", m.stats.Throughput())),
+		line("Latency p50", m.stats.P50.Round(time.Microsecond).String()),
+		line("Latency p95", m.stats.P95.Round(time.Microsecond).String()),
+		line("Latency p99", m.stats.P99.Round(time.Microsecond).String()),
+		line("Latency max", m.stats.Max.Round(time.Microsecond).String()),
+	}
+	if m.stats.LastErr != nil {
+		lines = append(lines, line("Last error", styles.FG(styles.ColorRed).Render(m.stats.LastErr.Error())))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case spinner.TickMsg, notifier.HideNotificationMsg:
+		return m.notifier.Update(msg)
+	case kadmin.LoadStartedMsg:
+		m.started = &msg
+		return m.started.AwaitActivity
+	case kadmin.LoadProgressMsg:
+		m.stats = msg.Stats
+		return m.started.AwaitActivity
+	case kadmin.LoadGeneratedMsg:
+		m.cancel()
+		m.state = generated
+		m.stats = msg.Stats
+		return m.notifyGenerated()
+	case kadmin.LoadGenerationErrMsg:
+		m.cancel()
+		m.state = none
+		m.form = nil
+		return m.notifier.ShowErrorMsg("Load generation failed", msg.Err)
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEsc:
+			if m.state == generating {
+				// the results so far are reported once the records in flight are published
+				m.cancel()
+				return nil
+			}
+			return ui.PublishMsg(nav.LoadTopicsPageMsg{})
+		case tea.KeyCtrlR:
+			if m.state == generated {
+				m.reset()
+				return nil
+			}
+		}
+	}
+
+	if m.form == nil || m.state != none {
+		return nil
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	if m.form.State == huh.StateCompleted {
+		m.state = generating
+		m.form.State = huh.StateNormal
+		m.stats = kadmin.LoadStats{}
+		m.details = m.formValues.loadDetails(m.topic.Name)
+		var ctx context.Context
+		ctx, m.cancel = context.WithCancel(context.Background())
+		details := m.details
+		return tea.Batch(
+			m.notifier.SpinWithLoadingMsg("Generating load"),
+			func() tea.Msg {
+				return m.generator.GenerateLoad(ctx, details)
+			},
+		)
+	}
+
+	return cmd
+}
+
+func (m *Model) notifyGenerated() tea.Cmd {
+	if m.stats.Failed > 0 {
+		return m.notifier.ShowErrorMsg(
+			"Load generated with errors",
+			fmt.Errorf("%d records published, %d failed", m.stats.Published, m.stats.Failed),
+		)
+	}
+	return m.notifier.ShowSuccessMsg(fmt.Sprintf("%d records published", m.stats.Published))
+}
+
+// loadDetails are the details of the validated form.
+func (v *formValues) loadDetails(topic string) kadmin.LoadDetails {
+	details := kadmin.LoadDetails{
+		Topic:       topic,
+		KeyTemplate: v.keyTemplate,
+		Settings: kadmin.ProducerSettings{
+			Acks:        v.acks,
+			Compression: v.compression,
+		},
+	}
+	details.Records, _ = strconv.Atoi(v.records)
+	details.Duration, _ = time.ParseDuration(v.duration)
+	details.Rate, _ = strconv.Atoi(v.rate)
+	if v.valueSource == schemaSource {
+		details.ValueSchema = &serdes.SchemaSelection{Subject: v.subject, Version: serdes.LatestVersion}
+	} else {
+		details.ValueTemplate = v.valueTemplate
+	}
+	return details
+}
+
+func (m *Model) reset() {
+	m.state = none
+	m.form = nil
+	m.started = nil
+	m.cancel = nil
+	m.stats = kadmin.LoadStats{}
+	m.notifier.Idle()
+}
+
+func validatePositive(str string) error {
+	if str == "" {
+		return nil
+	}
+	if n, err := strconv.Atoi(str); err != nil || n < 1 {
+		return fmt.Errorf("'%s' is not a positive number", str)
+	}
+	return nil
+}
+
+func validateTemplate(str string) error {
+	if str == "" {
+		return nil
+	}
+	_, err := kadmin.ParsePayloadTemplate(str)
+	return err
+}
+
+func (m *Model) newForm(ktx *kontext.ProgramKtx) *huh.Form {
+	fields := []huh.Field{
+		huh.NewInput().
+			Value(&m.formValues.records).
+			Title("Records").
+			Description("Number of records to publish, leave empty to publish for a duration.").
+			Validate(validatePositive),
+		huh.NewInput().
+			Value(&m.formValues.duration).
+			Title("Duration").
+			Description("Like 30s or 5m, leave empty to publish the number of records.").
+			Validate(func(str string) error {
+				if str == "" {
+					if m.formValues.records == "" {
+						return errors.New("records or a duration is required")
+					}
+					return nil
+				}
+				if d, err := time.ParseDuration(str); err != nil || d <= 0 {
+					return fmt.Errorf("'%s' is not a valid duration", str)
+				}
+				return nil
+			}),
+		huh.NewInput().
+			Value(&m.formValues.rate).
+			Title("Rate").
+			Description("Target records per second, leave empty to publish as fast as possible.").
+			Validate(validatePositive),
+		huh.NewInput().
+			Value(&m.formValues.keyTemplate).
+			Title("Key").
+			Description("Template of the keys, leave empty to use null keys.").
+			Validate(validateTemplate),
+		huh.NewSelect[kadmin.Acks]().
+			Inline(true).
+			Title("Acks: ").
+			Options(
+				huh.NewOption("All", kadmin.AcksAll),
+				huh.NewOption("Leader", kadmin.AcksLeader),
+				huh.NewOption("None", kadmin.AcksNone),
+			).
+			Value(&m.formValues.acks),
+		huh.NewSelect[kadmin.Compression]().
+			Inline(true).
+			Title("Compression: ").
+			Options(
+				huh.NewOption("None", kadmin.CompressionNone),
+				huh.NewOption("Gzip", kadmin.CompressionGzip),
+				huh.NewOption("Snappy", kadmin.CompressionSnappy),
+				huh.NewOption("LZ4", kadmin.CompressionLz4),
+				huh.NewOption("Zstd", kadmin.CompressionZstd),
+			).
+			Value(&m.formValues.compression),
+	}
+	if m.schemaRegistry {
+		fields = append(fields,
+			huh.NewSelect[valueSource]().
+				Inline(true).
+				Title("Values: ").
+				Options(
+					huh.NewOption("Template", templateSource),
+					huh.NewOption("Schema Registry", schemaSource),
+				).
+				Value(&m.formValues.valueSource),
+			huh.NewInput().
+				Value(&m.formValues.subject).
+				Title("Subject").
+				Description("Random values are generated matching the latest schema of the subject.").
+				Validate(func(str string) error {
+					if m.formValues.valueSource == schemaSource && strings.TrimSpace(str) == "" {
+						return errors.New("subject is required to generate values matching a schema")
+					}
+					return nil
+				}),
+		)
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(fields...).WithWidth(ktx.WindowWidth/2),
+		huh.NewGroup(
+			huh.NewText().
+				Value(&m.formValues.valueTemplate).
+				Title("Value Template").
+				Description("Placeholders: {{uuid}}, {{now}}, {{nowMillis}}, {{int 1 100}}, {{pick \"a\" \"b\"}} and {{.Seq}}.").
+				ShowLineNumbers(true).
+				Validate(func(str string) error {
+					if strings.TrimSpace(str) == "" {
+						return errors.New("value template cannot be empty")
+					}
+					return validateTemplate(str)
+				}).
+				WithHeight(ktx.AvailableHeight-10),
+		).WithHideFunc(func() bool {
+			return m.formValues.valueSource == schemaSource
+		}),
+		huh.NewGroup(huh.NewConfirm().
+			Inline(true).
+			Affirmative("Generate").
+			Negative(""),
+		),
+	)
+	form.WithLayout(huh.LayoutGrid(4, 2))
+	form.QuitAfterSubmit = false
+	form.Init()
+	return form
+}
+
+func (m *Model) Shortcuts() []statusbar.Shortcut {
+	switch m.state {
+	case generating:
+		return []statusbar.Shortcut{
+			{"Stop", "esc"},
+		}
+	case generated:
+		return []statusbar.Shortcut{
+			{"New Run", "C-r"},
+			{"Go Back", "esc"},
+		}
+	default:
+		return []statusbar.Shortcut{
+			{"Confirm", "enter"},
+			{"Next Field", "tab"},
+			{"Prev. Field", "s-tab"},
+			{"Go Back", "esc"},
+		}
+	}
+}
+
+func (m *Model) Title() string {
+	return "Topics / " + m.topic.Name + " / Generate Load"
+}
+
+func New(generator kadmin.LoadGenerator, topic *kadmin.ListedTopic, options ...Option) *Model {
+	m := &Model{
+		generator: generator,
+		topic:     topic,
+		notifier:  notifier.New(),
+		progress:  progress.New(progress.WithDefaultGradient(), progress.WithoutPercentage()),
+		formValues: &formValues{
+			subject:       topic.Name + "-value",
+			valueTemplate: defaultValueTemplate,
+		},
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
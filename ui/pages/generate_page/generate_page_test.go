@@ -0,0 +1,199 @@
+package generate_page
+
+import (
+	"context"
+	"errors"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"ktea/kadmin"
+	"ktea/serdes"
+	"ktea/tests"
+	"ktea/ui/pages/nav"
+	"testing"
+	"time"
+)
+
+type MockGenerator struct {
+	GenerateLoadFunc func(ctx context.Context, ld kadmin.LoadDetails) tea.Msg
+}
+
+func (m *MockGenerator) GenerateLoad(ctx context.Context, ld kadmin.LoadDetails) tea.Msg {
+	if m.GenerateLoadFunc != nil {
+		return m.GenerateLoadFunc(ctx, ld)
+	}
+	return nil
+}
+
+var topic = &kadmin.ListedTopic{
+	Name:           "orders",
+	PartitionCount: 3,
+	Replicas:       1,
+}
+
+// next confirms the focussed field and moves to the next one.
+func next(m *Model) {
+	cmd := m.Update(tests.Key(tea.KeyEnter))
+	m.Update(cmd())
+}
+
+func TestGeneratePage(t *testing.T) {
+	t.Run("esc goes back to the topics page", func(t *testing.T) {
+		m := New(&MockGenerator{}, topic)
+
+		cmd := m.Update(tests.Key(tea.KeyEsc))
+
+		assert.IsType(t, nav.LoadTopicsPageMsg{}, cmd())
+	})
+
+	t.Run("generate templated records", func(t *testing.T) {
+		var details kadmin.LoadDetails
+		m := New(&MockGenerator{
+			GenerateLoadFunc: func(ctx context.Context, ld kadmin.LoadDetails) tea.Msg {
+				details = ld
+				return nil
+			},
+		}, topic)
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		// records
+		tests.UpdateKeys(m, "1000")
+		next(m)
+		// duration
+		next(m)
+		// rate
+		tests.UpdateKeys(m, "50")
+		next(m)
+		// key
+		tests.UpdateKeys(m, "{{uuid}}")
+		next(m)
+		// acks
+		next(m)
+		// compression
+		m.Update(tests.Key(tea.KeyRight))
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		tests.NextGroup(m, cmd)
+		// value template
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		tests.NextGroup(m, cmd)
+
+		tests.Submit(m)
+
+		assert.Equal(t, kadmin.LoadDetails{
+			Topic:         "orders",
+			Records:       1000,
+			Rate:          50,
+			KeyTemplate:   "{{uuid}}",
+			ValueTemplate: defaultValueTemplate,
+			Settings:      kadmin.ProducerSettings{Compression: kadmin.CompressionGzip},
+		}, details)
+	})
+
+	t.Run("generate values matching a schema for a duration", func(t *testing.T) {
+		var details kadmin.LoadDetails
+		m := New(&MockGenerator{
+			GenerateLoadFunc: func(ctx context.Context, ld kadmin.LoadDetails) tea.Msg {
+				details = ld
+				return nil
+			},
+		}, topic, WithSchemaRegistry())
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		// records
+		next(m)
+		// duration
+		tests.UpdateKeys(m, "30s")
+		next(m)
+		// rate, key, acks and compression
+		for i := 0; i < 4; i++ {
+			next(m)
+		}
+		// values
+		m.Update(tests.Key(tea.KeyRight))
+		next(m)
+		// subject defaults to the TopicNameStrategy
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		tests.NextGroup(m, cmd)
+
+		tests.Submit(m)
+
+		assert.Equal(t, 30*time.Second, details.Duration)
+		assert.Equal(t, &serdes.SchemaSelection{Subject: "orders-value"}, details.ValueSchema)
+		assert.Empty(t, details.ValueTemplate)
+	})
+
+	t.Run("require records or a duration", func(t *testing.T) {
+		m := New(&MockGenerator{}, topic)
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		// records
+		next(m)
+		// duration
+		m.Update(tests.Key(tea.KeyEnter))
+
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "records or a duration is required")
+	})
+
+	t.Run("validate the key template", func(t *testing.T) {
+		m := New(&MockGenerator{}, topic)
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		// records
+		tests.UpdateKeys(m, "10")
+		next(m)
+		// duration and rate
+		next(m)
+		next(m)
+		// key
+		tests.UpdateKeys(m, "{{pick}}")
+		m.Update(tests.Key(tea.KeyEnter))
+
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "pick requires at least one value")
+	})
+
+	t.Run("show progress and stop with esc", func(t *testing.T) {
+		var cancelled bool
+		m := New(&MockGenerator{}, topic)
+		m.state = generating
+		m.details = kadmin.LoadDetails{Records: 100}
+		m.started = &kadmin.LoadStartedMsg{}
+		m.cancel = func() { cancelled = true }
+
+		m.Update(kadmin.LoadProgressMsg{Stats: kadmin.LoadStats{
+			Published: 50,
+			Elapsed:   time.Second,
+			P50:       2 * time.Millisecond,
+			P99:       9 * time.Millisecond,
+		}})
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+		assert.Regexp(t, "Published\\W+50", render)
+		assert.Regexp(t, "Throughput\\W+50.0 records/s", render)
+		assert.Regexp(t, "Latency p50\\W+2ms", render)
+		assert.Regexp(t, "Latency p99\\W+9ms", render)
+
+		cmd := m.Update(tests.Key(tea.KeyEsc))
+
+		assert.Nil(t, cmd)
+		assert.True(t, cancelled)
+	})
+
+	t.Run("report the results", func(t *testing.T) {
+		m := New(&MockGenerator{}, topic)
+		m.state = generating
+		m.cancel = func() {}
+
+		m.Update(kadmin.LoadGeneratedMsg{Stats: kadmin.LoadStats{
+			Published: 99,
+			Failed:    1,
+			LastErr:   errors.New("broker unavailable"),
+		}})
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+		assert.Contains(t, render, "99 records published, 1 failed")
+		assert.Regexp(t, "Last error\\W+broker unavailable", render)
+
+		m.Update(tests.Key(tea.KeyCtrlR))
+
+		assert.Equal(t, none, m.state)
+	})
+}
@@ -0,0 +1,289 @@
+package import_page
+
+import (
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"ktea/kadmin"
+	"ktea/kontext"
+	"ktea/styles"
+	"ktea/ui"
+	"ktea/ui/components/cmdbar"
+	"ktea/ui/components/notifier"
+	"ktea/ui/components/statusbar"
+	"ktea/ui/pages/nav"
+	"os"
+	"strings"
+)
+
+type state int
+
+const (
+	none      state = 0
+	importing state = 1
+	imported  state = 2
+)
+
+type Model struct {
+	state         state
+	form          *huh.Form
+	formValues    *formValues
+	importer      kadmin.RecordImporter
+	importStarted *kadmin.ImportStartedMsg
+	topic         *kadmin.ListedTopic
+	notifier      *notifier.Model
+	progress      progress.Model
+	processed     int
+	total         int
+	result        *kadmin.RecordsImportedMsg
+	reportVp      *viewport.Model
+}
+
+type formValues struct {
+	path             string
+	batchSize        int
+	ignorePartitions bool
+	dryRun           bool
+}
+
+func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
+	notifierView := m.notifier.View(ktx, renderer)
+	if notifierView != "" {
+		notifierView = styles.CmdBarWithWidth(ktx.WindowWidth - cmdbar.BorderedPadding).Render(notifierView)
+	}
+
+	if m.state == none {
+		if m.form == nil {
+			m.form = m.newForm(ktx)
+		}
+		return ui.JoinVertical(lipgloss.Top,
+			notifierView,
+			renderer.RenderWithStyle(m.form.View(), styles.Form),
+		)
+	}
+
+	m.progress.Width = ktx.WindowWidth - 4
+	var percent float64
+	if m.total > 0 {
+		percent = float64(m.processed) / float64(m.total)
+	}
+	progressView := renderer.RenderWithStyle(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.progress.ViewAs(percent),
+			fmt.Sprintf("%d/%d lines processed", m.processed, m.total),
+		),
+		lipgloss.NewStyle().Padding(1),
+	)
+
+	var reportView string
+	if m.state == imported {
+		if m.reportVp == nil {
+			reportVp := viewport.New(ktx.WindowWidth-4, ktx.AvailableHeight-2)
+			m.reportVp = &reportVp
+			m.reportVp.SetContent(m.report())
+		}
+		reportView = styles.Borderize(m.reportVp.View(), true, nil)
+	}
+
+	return ui.JoinVertical(lipgloss.Top, notifierView, progressView, reportView)
+}
+
+func (m *Model) report() string {
+	if len(m.result.Errors) == 0 {
+		return "No errors"
+	}
+	var b strings.Builder
+	for _, lineErr := range m.result.Errors {
+		b.WriteString(styles.FG(styles.ColorRed).Render(fmt.Sprintf("line %d", lineErr.Line)))
+		b.WriteString(": ")
+		b.WriteString(lineErr.Err.Error())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case spinner.TickMsg, notifier.HideNotificationMsg:
+		return m.notifier.Update(msg)
+	case kadmin.ImportStartedMsg:
+		m.importStarted = &msg
+		return m.importStarted.AwaitActivity
+	case kadmin.ImportProgressMsg:
+		m.processed = msg.Processed
+		m.total = msg.Total
+		return m.importStarted.AwaitActivity
+	case kadmin.RecordsImportedMsg:
+		m.state = imported
+		m.processed = m.total
+		m.result = &msg
+		return m.notifyImported(msg)
+	case kadmin.RecordsImportErrMsg:
+		m.state = none
+		m.form = nil
+		return m.notifier.ShowErrorMsg("Import failed", msg.Err)
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEsc:
+			if m.state == importing {
+				return nil
+			}
+			return ui.PublishMsg(nav.LoadTopicsPageMsg{})
+		case tea.KeyCtrlR:
+			if m.state == imported {
+				m.reset()
+				return nil
+			}
+		}
+		if m.state == imported {
+			vp, cmd := m.reportVp.Update(msg)
+			m.reportVp = &vp
+			return cmd
+		}
+	}
+
+	if m.form == nil || m.state != none {
+		return nil
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	if m.form.State == huh.StateCompleted {
+		m.state = importing
+		m.form.State = huh.StateNormal
+		details := kadmin.ImportDetails{
+			Topic:            m.topic.Name,
+			PartitionCount:   m.topic.PartitionCount,
+			Path:             m.formValues.path,
+			BatchSize:        m.formValues.batchSize,
+			DryRun:           m.formValues.dryRun,
+			IgnorePartitions: m.formValues.ignorePartitions,
+		}
+		return tea.Batch(
+			m.notifier.SpinWithLoadingMsg("Importing records"),
+			func() tea.Msg {
+				return m.importer.ImportRecords(details)
+			},
+		)
+	}
+
+	return cmd
+}
+
+func (m *Model) notifyImported(msg kadmin.RecordsImportedMsg) tea.Cmd {
+	if msg.DryRun {
+		if len(msg.Errors) > 0 {
+			return m.notifier.ShowErrorMsg(
+				"Dry run failed",
+				fmt.Errorf("%d valid records, %d invalid lines", msg.Published, len(msg.Errors)),
+			)
+		}
+		return m.notifier.ShowSuccessMsg(fmt.Sprintf("Dry run succeeded, %d valid records", msg.Published))
+	}
+	if len(msg.Errors) > 0 {
+		return m.notifier.ShowErrorMsg(
+			"Import completed with errors",
+			fmt.Errorf("%d records published, %d lines failed", msg.Published, len(msg.Errors)),
+		)
+	}
+	return m.notifier.ShowSuccessMsg(fmt.Sprintf("%d records published", msg.Published))
+}
+
+func (m *Model) reset() {
+	m.state = none
+	m.form = nil
+	m.result = nil
+	m.reportVp = nil
+	m.processed = 0
+	m.total = 0
+	m.notifier.Idle()
+}
+
+func (m *Model) newForm(ktx *kontext.ProgramKtx) *huh.Form {
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Value(&m.formValues.path).
+				Title("File").
+				Description("JSON Lines file, one record per line with key, value, headers, partition and timestamp.").
+				Validate(func(path string) error {
+					if path == "" {
+						return errors.New("file cannot be empty")
+					}
+					if _, err := os.Stat(path); err != nil {
+						return fmt.Errorf("'%s' cannot be read", path)
+					}
+					return nil
+				}),
+			huh.NewSelect[int]().
+				Value(&m.formValues.batchSize).
+				Title("Batch Size").
+				Description("Number of records published concurrently.").
+				Options(
+					huh.NewOption("1", 1),
+					huh.NewOption("10", 10),
+					huh.NewOption("100", 100),
+					huh.NewOption("500", 500)),
+			huh.NewSelect[bool]().
+				Value(&m.formValues.ignorePartitions).
+				Title("Partitioning").
+				Options(
+					huh.NewOption("Use the partition from the file", false),
+					huh.NewOption("Use murmur2 key based partitioner", true)),
+			huh.NewSelect[bool]().
+				Value(&m.formValues.dryRun).
+				Title("Mode").
+				Options(
+					huh.NewOption("Publish", false),
+					huh.NewOption("Dry run, only validate the file", true)),
+		).WithWidth(ktx.WindowWidth / 2),
+	)
+	form.QuitAfterSubmit = false
+	form.Init()
+	return form
+}
+
+func (m *Model) Shortcuts() []statusbar.Shortcut {
+	switch m.state {
+	case importing:
+		return nil
+	case imported:
+		return []statusbar.Shortcut{
+			{"Scroll Errors", "↑/↓"},
+			{"New Import", "C-r"},
+			{"Go Back", "esc"},
+		}
+	default:
+		return []statusbar.Shortcut{
+			{"Confirm", "enter"},
+			{"Next Field", "tab"},
+			{"Prev. Field", "s-tab"},
+			{"Go Back", "esc"},
+		}
+	}
+}
+
+func (m *Model) Title() string {
+	return "Topics / " + m.topic.Name + " / Import"
+}
+
+func New(importer kadmin.RecordImporter, topic *kadmin.ListedTopic) *Model {
+	return &Model{
+		importer: importer,
+		topic:    topic,
+		notifier: notifier.New(),
+		progress: progress.New(progress.WithDefaultGradient(), progress.WithoutPercentage()),
+		formValues: &formValues{
+			batchSize: 100,
+		},
+	}
+}
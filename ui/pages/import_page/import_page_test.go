@@ -0,0 +1,112 @@
+package import_page
+
+import (
+	"errors"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"ktea/kadmin"
+	"ktea/tests"
+	"ktea/ui/pages/nav"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type MockImporter struct {
+	ImportRecordsFunc func(id kadmin.ImportDetails) tea.Msg
+}
+
+func (m *MockImporter) ImportRecords(id kadmin.ImportDetails) tea.Msg {
+	if m.ImportRecordsFunc != nil {
+		return m.ImportRecordsFunc(id)
+	}
+	return nil
+}
+
+var topic = &kadmin.ListedTopic{
+	Name:           "topic1",
+	PartitionCount: 3,
+	Replicas:       1,
+}
+
+func TestImportPage(t *testing.T) {
+	t.Run("esc goes back to the topics page", func(t *testing.T) {
+		m := New(&MockImporter{}, topic)
+
+		cmd := m.Update(tests.Key(tea.KeyEsc))
+
+		assert.IsType(t, nav.LoadTopicsPageMsg{}, cmd())
+	})
+
+	t.Run("import file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "records.jsonl")
+		_ = os.WriteFile(path, []byte(`{"key":"1","value":"a"}`), 0644)
+
+		var details kadmin.ImportDetails
+		m := New(&MockImporter{
+			ImportRecordsFunc: func(id kadmin.ImportDetails) tea.Msg {
+				details = id
+				return nil
+			},
+		}, topic)
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		// file
+		tests.UpdateKeys(m, path)
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// batch size
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// partitioning
+		m.Update(tests.Key(tea.KeyDown))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// mode
+		m.Update(tests.Key(tea.KeyDown))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next group and submit
+		cmd = m.Update(cmd())
+		tests.ExecuteBatchCmd(m.Update(cmd()))
+
+		assert.Equal(t, kadmin.ImportDetails{
+			Topic:            "topic1",
+			PartitionCount:   3,
+			Path:             path,
+			BatchSize:        100,
+			DryRun:           true,
+			IgnorePartitions: true,
+		}, details)
+	})
+
+	t.Run("file must exist", func(t *testing.T) {
+		m := New(&MockImporter{}, topic)
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		tests.UpdateKeys(m, "missing.jsonl")
+		m.Update(tests.Key(tea.KeyEnter))
+
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "'missing.jsonl' cannot be read")
+	})
+
+	t.Run("show progress and report per line errors", func(t *testing.T) {
+		m := New(&MockImporter{}, topic)
+		m.state = importing
+		m.importStarted = &kadmin.ImportStartedMsg{}
+
+		m.Update(kadmin.ImportProgressMsg{Processed: 5, Total: 10})
+
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "5/10 lines processed")
+
+		m.Update(kadmin.RecordsImportedMsg{
+			Published: 9,
+			Errors:    []kadmin.LineError{{Line: 7, Err: errors.New("invalid JSON")}},
+		})
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+		assert.Contains(t, render, "10/10 lines processed")
+		assert.Contains(t, render, "9 records published, 1 lines failed")
+		assert.Contains(t, render, "line 7")
+		assert.Contains(t, render, "invalid JSON")
+	})
+}
@@ -24,6 +24,14 @@ type LoadPublishPageMsg struct {
 	Topic *kadmin.ListedTopic
 }
 
+type LoadImportPageMsg struct {
+	Topic *kadmin.ListedTopic
+}
+
+type LoadGeneratePageMsg struct {
+	Topic *kadmin.ListedTopic
+}
+
 type LoadConsumptionPageMsg struct {
 	ReadDetails kadmin.ReadDetails
 	Topic       *kadmin.ListedTopic
@@ -42,9 +50,34 @@ type LoadConsumptionFormPageMsg struct {
 	ReadDetails *kadmin.ReadDetails
 }
 
+type LoadSavedQueriesPageMsg struct {
+	Topic *kadmin.ListedTopic
+}
+
+type LoadExportPageMsg struct {
+	Records     []kadmin.ConsumerRecord
+	ReadDetails kadmin.ReadDetails
+}
+
+type LoadReplayPageMsg struct {
+	Records []kadmin.ConsumerRecord
+	// Marked are the records marked by the user, a subset of Records.
+	Marked      []kadmin.ConsumerRecord
+	ReadDetails kadmin.ReadDetails
+}
+
 type LoadRecordDetailPageMsg struct {
 	Record    *kadmin.ConsumerRecord
 	TopicName string
+	// PartitionCount of the topic, zero when unknown
+	PartitionCount int
+}
+
+// LoadRecordDiffPageMsg compares the Left record to the Right record.
+type LoadRecordDiffPageMsg struct {
+	Left      kadmin.ConsumerRecord
+	Right     kadmin.ConsumerRecord
+	TopicName string
 }
 
 type LoadCGroupsPageMsg struct {
@@ -1,6 +1,8 @@
 package publish_page
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -10,6 +12,8 @@ import (
 	"github.com/charmbracelet/log"
 	"ktea/kadmin"
 	"ktea/kontext"
+	"ktea/serdes"
+	"ktea/sradmin"
 	"ktea/styles"
 	"ktea/ui"
 	"ktea/ui/components/cmdbar"
@@ -36,17 +40,99 @@ type Model struct {
 	topic      *kadmin.ListedTopic
 	notifier   *notifier.Model
 	formValues *formValues
+	// serializer serializes the payload with a schema, nil when the cluster has no Schema Registry
+	serializer serdes.Serializer
+}
+
+type Option func(m *Model)
+
+// WithSchemaRegistry enables serializing the payload with a schema of the Schema Registry.
+func WithSchemaRegistry(sra sradmin.SrAdmin) Option {
+	return func(m *Model) {
+		m.serializer = serdes.NewSerializer(sra)
+	}
 }
 
 type LoadPageMsg struct {
 	Topic kadmin.ListedTopic
 }
 
+type SerializationFailedMsg struct {
+	Err error
+}
+
+// TemplateInsertedMsg carries a skeleton payload of the selected schema.
+type TemplateInsertedMsg struct {
+	Payload string
+}
+
+type TemplateFailedMsg struct {
+	Err error
+}
+
+type serialization int
+
+const (
+	rawSerialization serialization = iota
+	schemaRegistrySerialization
+)
+
+// encoding of the payload and header values as they are entered
+type encoding int
+
+const (
+	textEncoding encoding = iota
+	base64Encoding
+	hexEncoding
+)
+
+func (e encoding) decode(input string) ([]byte, error) {
+	switch e {
+	case base64Encoding:
+		decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(input), ""))
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is no valid base64: %w", input, err)
+		}
+		return decoded, nil
+	case hexEncoding:
+		decoded, err := hex.DecodeString(strings.Join(strings.Fields(input), ""))
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is no valid hex: %w", input, err)
+		}
+		return decoded, nil
+	default:
+		return []byte(input), nil
+	}
+}
+
 type formValues struct {
-	Key       string
+	Key string
+	// NullKey publishes a null key instead of the Key, like a tombstone does for the value
+	NullKey   bool
 	Partition string
 	Payload   string
 	Headers   string
+	// HeaderEncoding of the header values
+	HeaderEncoding encoding
+	// Value is the encoding of the payload, or a tombstone
+	Value         valueInput
+	Serialization serialization
+	// Subject and Version of the schema the payload is serialized with, an empty Version is the latest one
+	Subject string
+	Version string
+	// Message is the Protobuf message the payload is serialized as, the first message of the schema when empty
+	Message string
+	// Timestamp of the record, the time of publication when empty
+	Timestamp   string
+	Acks        kadmin.Acks
+	Compression kadmin.Compression
+	Idempotent  bool
+}
+
+// valueInput is how the value is entered, the encoding of the payload or a null value.
+type valueInput struct {
+	encoding  encoding
+	tombstone bool
 }
 
 func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
@@ -77,10 +163,28 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			m.notifier.SpinWithLoadingMsg("Publishing record"),
 			msg.AwaitCompletion,
 		)
+	case SerializationFailedMsg:
+		m.state = none
+		m.topicForm.Init()
+		return m.notifier.ShowErrorMsg("Serialization failed", msg.Err)
+	case TemplateInsertedMsg:
+		m.formValues.Payload = msg.Payload
+		m.formValues.Value = valueInput{}
+		m.formValues.Serialization = schemaRegistrySerialization
+		// the form is recreated to show the payload
+		m.topicForm = nil
+		return tea.Batch(
+			m.notifier.ShowSuccessMsg("Template inserted"),
+			func() tea.Msg {
+				time.Sleep(5 * time.Second)
+				return notifier.HideNotificationMsg{}
+			})
+	case TemplateFailedMsg:
+		return m.notifier.ShowErrorMsg("Unable to insert template", msg.Err)
 	case kadmin.PublicationFailed:
 		m.state = none
 		m.topicForm.Init()
-		return m.notifier.ShowErrorMsg("Publication failed!", fmt.Errorf("TODO"))
+		return m.notifier.ShowErrorMsg("Publication failed!", msg.Err)
 	case kadmin.PublicationSucceeded:
 		m.resetForm()
 		return tea.Batch(
@@ -98,6 +202,10 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			return ui.PublishMsg(nav.LoadTopicsPageMsg{})
 		case tea.KeyCtrlR:
 			m.resetForm()
+		case tea.KeyCtrlT:
+			if m.serializer != nil && m.state != publishing {
+				return m.insertTemplate()
+			}
 		}
 	}
 	if m.topicForm != nil && m.state != publishing {
@@ -118,12 +226,30 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 						}
 					}
 
+					value, err := m.value()
+					if err != nil {
+						return SerializationFailedMsg{Err: err}
+					}
+					headers, err := m.formValues.parsedHeaders()
+					if err != nil {
+						return SerializationFailedMsg{Err: err}
+					}
+					// validated by the form
+					timestamp, _ := parseTimestamp(m.formValues.Timestamp)
+
 					return m.publisher.PublishRecord(&kadmin.ProducerRecord{
 						Key:       m.formValues.Key,
-						Value:     []byte(m.formValues.Payload),
+						NullKey:   m.formValues.NullKey,
+						Value:     value,
 						Topic:     m.topic.Name,
-						Headers:   m.formValues.parsedHeaders(),
+						Headers:   headers,
 						Partition: part,
+						Timestamp: timestamp,
+						Settings: kadmin.ProducerSettings{
+							Acks:        m.formValues.Acks,
+							Compression: m.formValues.Compression,
+							Idempotent:  m.formValues.Idempotent,
+						},
 					})
 				})
 		}
@@ -132,28 +258,91 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
-func (v *formValues) parsedHeaders() map[string]string {
+// value is the payload, serialized with the chosen schema when publishing with the Schema Registry.
+func (m *Model) value() ([]byte, error) {
+	switch {
+	case m.formValues.Value.tombstone:
+		return nil, nil
+	case m.formValues.Serialization == schemaRegistrySerialization:
+		if m.formValues.Value.encoding != textEncoding {
+			return nil, errors.New("the payload is serialized from JSON, enter it as text")
+		}
+		return m.serializer.Serialize(m.schemaSelection(), m.formValues.Payload)
+	default:
+		return m.formValues.Value.encoding.decode(m.formValues.Payload)
+	}
+}
+
+func (m *Model) schemaSelection() serdes.SchemaSelection {
+	version := serdes.LatestVersion
+	if m.formValues.Version != "" {
+		version, _ = strconv.Atoi(m.formValues.Version)
+	}
+	return serdes.SchemaSelection{
+		Subject: m.formValues.Subject,
+		Version: version,
+		Message: m.formValues.Message,
+	}
+}
+
+// insertTemplate replaces the payload with a skeleton of the selected schema, the latest version by default.
+func (m *Model) insertTemplate() tea.Cmd {
+	selection := m.schemaSelection()
+	return tea.Batch(
+		m.notifier.SpinWithLoadingMsg("Inserting template"),
+		func() tea.Msg {
+			skeleton, err := m.serializer.Skeleton(selection)
+			if err != nil {
+				return TemplateFailedMsg{Err: err}
+			}
+			return TemplateInsertedMsg{Payload: skeleton}
+		})
+}
+
+func (v *formValues) parsedHeaders() (map[string]string, error) {
 	if v.Headers == "" {
-		return map[string]string{}
+		return map[string]string{}, nil
 	}
 	headers := map[string]string{}
 	for _, line := range strings.Split(v.Headers, "\n") {
 		if strings.Contains(line, "=") {
-			split := strings.Split(line, "=")
-			key := split[0]
-			value := split[1]
-			headers[key] = value
+			// base64 values may end with =
+			key, value, _ := strings.Cut(line, "=")
+			decoded, err := v.HeaderEncoding.decode(value)
+			if err != nil {
+				return nil, fmt.Errorf("header %s: %w", key, err)
+			}
+			headers[key] = string(decoded)
 		}
 	}
-	return headers
+	return headers, nil
+}
+
+// parseTimestamp parses an RFC 3339 timestamp or epoch millis, an empty timestamp is the zero time.
+func parseTimestamp(input string) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return time.Time{}, nil
+	}
+	if millis, err := strconv.ParseInt(input, 10, 64); err == nil {
+		return time.UnixMilli(millis), nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, input)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is no RFC 3339 timestamp or epoch millis", input)
+	}
+	return timestamp, nil
 }
 
 func (m *Model) Shortcuts() []statusbar.Shortcut {
-	return []statusbar.Shortcut{
+	shortcuts := []statusbar.Shortcut{
 		{"Confirm", "enter"},
 		{"Reset Form", "C-r"},
-		{"Go Back", "esc"},
 	}
+	if m.serializer != nil {
+		shortcuts = append(shortcuts, statusbar.Shortcut{"Insert Template", "C-t"})
+	}
+	return append(shortcuts, statusbar.Shortcut{"Go Back", "esc"})
 }
 
 func (m *Model) Title() string {
@@ -163,22 +352,51 @@ func (m *Model) Title() string {
 func (m *Model) resetForm() {
 	m.state = none
 	m.formValues.Key = ""
+	m.formValues.NullKey = false
 	m.formValues.Partition = ""
 	m.formValues.Payload = ""
 	m.formValues.Headers = ""
+	m.formValues.HeaderEncoding = textEncoding
+	m.formValues.Value = valueInput{}
+	m.formValues.Serialization = rawSerialization
+	m.formValues.Subject = m.defaultSubject()
+	m.formValues.Version = ""
+	m.formValues.Message = ""
+	m.formValues.Timestamp = ""
+	m.formValues.Acks = kadmin.AcksAll
+	m.formValues.Compression = kadmin.CompressionNone
+	m.formValues.Idempotent = false
 	m.topicForm = nil
 }
 
+// defaultSubject is the subject of the value according to the TopicNameStrategy.
+func (m *Model) defaultSubject() string {
+	return m.topic.Name + "-value"
+}
+
 func (m *Model) newForm(ktx *kontext.ProgramKtx) *huh.Form {
+	var payloadDescription string
+	if m.serializer != nil {
+		payloadDescription = "C-t inserts a template of the schema of the subject."
+	}
 	payload := huh.NewText().
 		ShowLineNumbers(true).
 		Value(&m.formValues.Payload).
 		Title("Payload").
+		Description(payloadDescription).
 		WithHeight(ktx.AvailableHeight - 10)
 	key := huh.NewInput().
 		Title("Key").
-		Description("Leave empty to use a null key for the message.").
+		Description("Leave empty to use an empty key, or select a null key below.").
 		Value(&m.formValues.Key)
+	nullKey := huh.NewSelect[bool]().
+		Inline(true).
+		Title("Key: ").
+		Options(
+			huh.NewOption("Text", false),
+			huh.NewOption("Null", true),
+		).
+		Value(&m.formValues.NullKey)
 	partition := huh.NewInput().
 		Value(&m.formValues.Partition).
 		Description("Leave empty to use murmur2 key based partitioner (identical to JVM clients).").
@@ -202,21 +420,43 @@ func (m *Model) newForm(ktx *kontext.ProgramKtx) *huh.Form {
 		Value(&m.formValues.Headers).
 		Title("Headers").
 		WithHeight(10)
+	headerEncoding := huh.NewSelect[encoding]().
+		Inline(true).
+		Title("Header Values: ").
+		Options(
+			huh.NewOption("Text", textEncoding),
+			huh.NewOption("Base64", base64Encoding),
+			huh.NewOption("Hex", hexEncoding),
+		).
+		Value(&m.formValues.HeaderEncoding)
+	value := huh.NewSelect[valueInput]().
+		Inline(true).
+		Title("Value: ").
+		Options(
+			huh.NewOption("Text", valueInput{encoding: textEncoding}),
+			huh.NewOption("Base64", valueInput{encoding: base64Encoding}),
+			huh.NewOption("Hex", valueInput{encoding: hexEncoding}),
+			huh.NewOption("Null (tombstone)", valueInput{tombstone: true}),
+		).
+		Value(&m.formValues.Value)
+
+	fields := []huh.Field{key, nullKey, partition, headers, headerEncoding, value}
+	if m.serializer != nil {
+		fields = append(fields, m.schemaRegistryFields()...)
+	}
 
 	form := huh.NewForm(
-		huh.NewGroup(
-			key,
-			partition,
-			headers,
-		).WithWidth(ktx.WindowWidth/2),
+		huh.NewGroup(fields...).WithWidth(ktx.WindowWidth/2),
 		huh.NewGroup(
 			payload,
-		),
-		huh.NewGroup(huh.NewConfirm().
+		).WithHideFunc(func() bool {
+			return m.formValues.Value.tombstone
+		}),
+		huh.NewGroup(append(m.producerFields(), huh.NewConfirm().
 			Inline(true).
 			Affirmative("Produce").
 			Negative(""),
-		),
+		)...),
 	)
 	form.WithLayout(huh.LayoutGrid(4, 2))
 	form.QuitAfterSubmit = false
@@ -224,11 +464,104 @@ func (m *Model) newForm(ktx *kontext.ProgramKtx) *huh.Form {
 	return form
 }
 
-func New(p kadmin.Publisher, topic *kadmin.ListedTopic) *Model {
-	return &Model{
+func (m *Model) schemaRegistryFields() []huh.Field {
+	serializationField := huh.NewSelect[serialization]().
+		Inline(true).
+		Title("Serialization: ").
+		Options(
+			huh.NewOption("Raw", rawSerialization),
+			huh.NewOption("Schema Registry", schemaRegistrySerialization),
+		).
+		Value(&m.formValues.Serialization)
+	subject := huh.NewInput().
+		Title("Subject").
+		Description("Subject of the schema to serialize the JSON payload with, defaults to the TopicNameStrategy.").
+		Value(&m.formValues.Subject).
+		Validate(func(str string) error {
+			if m.formValues.Serialization == schemaRegistrySerialization && strings.TrimSpace(str) == "" {
+				return errors.New("subject is required to serialize with the Schema Registry")
+			}
+			return nil
+		})
+	version := huh.NewInput().
+		Title("Version").
+		Description("Leave empty to use the latest version.").
+		Placeholder("latest").
+		Value(&m.formValues.Version).
+		Validate(func(str string) error {
+			if str == "" {
+				return nil
+			}
+			if n, err := strconv.Atoi(str); err != nil || n < 1 {
+				return fmt.Errorf("'%s' is not a valid version", str)
+			}
+			return nil
+		})
+	message := huh.NewInput().
+		Title("Message").
+		Description("Protobuf schemas only, leave empty to use the first message of the schema.").
+		Placeholder("first message").
+		Value(&m.formValues.Message)
+	return []huh.Field{serializationField, subject, version, message}
+}
+
+// producerFields set the timestamp of the record and how it is produced.
+func (m *Model) producerFields() []huh.Field {
+	timestamp := huh.NewInput().
+		Title("Timestamp").
+		Description("RFC 3339 or epoch millis, leave empty to use the time of publication.").
+		Value(&m.formValues.Timestamp).
+		Validate(func(str string) error {
+			_, err := parseTimestamp(str)
+			return err
+		})
+	acks := huh.NewSelect[kadmin.Acks]().
+		Inline(true).
+		Title("Acks: ").
+		Options(
+			huh.NewOption("All", kadmin.AcksAll),
+			huh.NewOption("Leader", kadmin.AcksLeader),
+			huh.NewOption("None", kadmin.AcksNone),
+		).
+		Value(&m.formValues.Acks)
+	compression := huh.NewSelect[kadmin.Compression]().
+		Inline(true).
+		Title("Compression: ").
+		Options(
+			huh.NewOption("None", kadmin.CompressionNone),
+			huh.NewOption("Gzip", kadmin.CompressionGzip),
+			huh.NewOption("Snappy", kadmin.CompressionSnappy),
+			huh.NewOption("LZ4", kadmin.CompressionLz4),
+			huh.NewOption("Zstd", kadmin.CompressionZstd),
+		).
+		Value(&m.formValues.Compression)
+	idempotence := huh.NewSelect[bool]().
+		Inline(true).
+		Title("Idempotence: ").
+		Options(
+			huh.NewOption("Off", false),
+			huh.NewOption("On", true),
+		).
+		Value(&m.formValues.Idempotent).
+		Validate(func(idempotent bool) error {
+			if idempotent && m.formValues.Acks != kadmin.AcksAll {
+				return errors.New("idempotence requires all acks")
+			}
+			return nil
+		})
+	return []huh.Field{timestamp, acks, compression, idempotence}
+}
+
+func New(p kadmin.Publisher, topic *kadmin.ListedTopic, options ...Option) *Model {
+	m := &Model{
 		topic:      topic,
 		publisher:  p,
 		notifier:   notifier.New(),
 		formValues: &formValues{},
 	}
+	m.formValues.Subject = m.defaultSubject()
+	for _, option := range options {
+		option(m)
+	}
+	return m
 }
@@ -1,14 +1,19 @@
 package publish_page
 
 import (
+	"errors"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/stretchr/testify/assert"
 	"ktea/kadmin"
 	"ktea/kontext"
+	"ktea/sradmin"
 	"ktea/tests"
 	"ktea/ui/components/notifier"
+	"ktea/ui/components/statusbar"
 	"ktea/ui/pages/nav"
 	"testing"
+	"time"
 )
 
 type MockPublisher struct {
@@ -22,27 +27,76 @@ func (m *MockPublisher) PublishRecord(p *kadmin.ProducerRecord) kadmin.Publicati
 	return kadmin.PublicationStartedMsg{}
 }
 
+// publish submits the form with the default timestamp, acks, compression and idempotence.
+func publish(m *Model) []tea.Msg {
+	for i := 0; i < 4; i++ {
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+	}
+	return tests.Submit(m)
+}
+
 func TestParseHeaders(t *testing.T) {
 	t.Run("header format is key=value", func(t *testing.T) {
 		fv := formValues{
 			Headers: "key1=value1\n\nkey2=value2\n",
 		}
 
-		headers := fv.parsedHeaders()
+		headers, err := fv.parsedHeaders()
 
+		assert.NoError(t, err)
 		assert.Equal(t, map[string]string{
 			"key1": "value1",
 			"key2": "value2",
 		}, headers)
 	})
 
+	t.Run("header values can be base64", func(t *testing.T) {
+		fv := formValues{
+			Headers:        "key1=dmFsdWUx\nkey2=AP8=",
+			HeaderEncoding: base64Encoding,
+		}
+
+		headers, err := fv.parsedHeaders()
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"key1": "value1",
+			"key2": "\x00\xff",
+		}, headers)
+	})
+
+	t.Run("header values can be hex", func(t *testing.T) {
+		fv := formValues{
+			Headers:        "key1=00ff",
+			HeaderEncoding: hexEncoding,
+		}
+
+		headers, err := fv.parsedHeaders()
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"key1": "\x00\xff"}, headers)
+	})
+
+	t.Run("invalid header values", func(t *testing.T) {
+		fv := formValues{
+			Headers:        "key1=zz",
+			HeaderEncoding: hexEncoding,
+		}
+
+		_, err := fv.parsedHeaders()
+
+		assert.ErrorContains(t, err, "header key1: 'zz' is no valid hex")
+	})
+
 	t.Run("no headers filled in", func(t *testing.T) {
 		formValues := formValues{
 			Headers: "",
 		}
 
-		headers := formValues.parsedHeaders()
+		headers, err := formValues.parsedHeaders()
 
+		assert.NoError(t, err)
 		assert.Equal(t, map[string]string{}, headers)
 	})
 }
@@ -81,6 +135,9 @@ func TestPublish(t *testing.T) {
 		tests.UpdateKeys(m, "key")
 		cmd := m.Update(tests.Key(tea.KeyEnter))
 		m.Update(cmd())
+		// key is text
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
 
 		// Partition
 		tests.UpdateKeys(m, "2")
@@ -92,13 +149,21 @@ func TestPublish(t *testing.T) {
 		cmd = m.Update(tests.KeyWithAlt(tea.KeyEnter))
 		tests.UpdateKeys(m, "user=456")
 		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// header values
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// value
+		cmd = m.Update(tests.Key(tea.KeyEnter))
 		tests.NextGroup(m, cmd)
 
 		tests.UpdateKeys(m, "payload")
 		cmd = m.Update(tests.Key(tea.KeyEnter))
 		tests.NextGroup(m, cmd)
 
-		tests.Submit(m)
+		publish(m)
 
 		cmd = m.Update(tests.Key(tea.KeyEsc))
 		assert.Nil(t, cmd)
@@ -126,6 +191,9 @@ func TestPublish(t *testing.T) {
 		tests.UpdateKeys(m, "key")
 		cmd := m.Update(tests.Key(tea.KeyEnter))
 		m.Update(cmd())
+		// key is text
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
 
 		// Partition
 		tests.UpdateKeys(m, "2")
@@ -137,13 +205,21 @@ func TestPublish(t *testing.T) {
 		cmd = m.Update(tests.KeyWithAlt(tea.KeyEnter))
 		tests.UpdateKeys(m, "user=456")
 		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// header values
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// value
+		cmd = m.Update(tests.Key(tea.KeyEnter))
 		tests.NextGroup(m, cmd)
 
 		tests.UpdateKeys(m, "payload")
 		cmd = m.Update(tests.Key(tea.KeyEnter))
 		tests.NextGroup(m, cmd)
 
-		tests.Submit(m)
+		publish(m)
 
 		assert.Equal(t, "key", producerRecord.Key)
 		assert.Equal(t, "topic1", producerRecord.Topic)
@@ -159,6 +235,371 @@ func TestPublish(t *testing.T) {
 		)
 	})
 
+	t.Run("publish hex with a null key and producer settings", func(t *testing.T) {
+		var producerRecord *kadmin.ProducerRecord
+		m := New(&MockPublisher{
+			PublishRecordFunc: func(p *kadmin.ProducerRecord) kadmin.PublicationStartedMsg {
+				producerRecord = p
+				return kadmin.PublicationStartedMsg{}
+			},
+		}, &kadmin.ListedTopic{
+			Name:           "topic1",
+			PartitionCount: 10,
+			Replicas:       1,
+		})
+
+		m.View(&kontext.ProgramKtx{
+			WindowWidth:  100,
+			WindowHeight: 100,
+		}, tests.TestRenderer)
+
+		// key
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// null key
+		m.Update(tests.Key(tea.KeyRight))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// partition, headers and header values
+		for i := 0; i < 3; i++ {
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			m.Update(cmd())
+		}
+
+		// value
+		m.Update(tests.Key(tea.KeyRight))
+		m.Update(tests.Key(tea.KeyRight))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		tests.NextGroup(m, cmd)
+
+		tests.UpdateKeys(m, "dead beef")
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		tests.NextGroup(m, cmd)
+
+		// timestamp
+		tests.UpdateKeys(m, "1740825000000")
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// acks
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// compression
+		m.Update(tests.Key(tea.KeyRight))
+		m.Update(tests.Key(tea.KeyRight))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// idempotence
+		m.Update(tests.Key(tea.KeyRight))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		tests.Submit(m)
+
+		assert.True(t, producerRecord.NullKey)
+		assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, producerRecord.Value)
+		assert.Equal(t, time.UnixMilli(1740825000000), producerRecord.Timestamp)
+		assert.Equal(t, kadmin.ProducerSettings{
+			Acks:        kadmin.AcksAll,
+			Compression: kadmin.CompressionSnappy,
+			Idempotent:  true,
+		}, producerRecord.Settings)
+	})
+
+	t.Run("publish an empty key unless a null key is selected", func(t *testing.T) {
+		var producerRecord *kadmin.ProducerRecord
+		m := New(&MockPublisher{
+			PublishRecordFunc: func(p *kadmin.ProducerRecord) kadmin.PublicationStartedMsg {
+				producerRecord = p
+				return kadmin.PublicationStartedMsg{}
+			},
+		}, &kadmin.ListedTopic{
+			Name:           "topic1",
+			PartitionCount: 10,
+			Replicas:       1,
+		})
+
+		m.View(&kontext.ProgramKtx{
+			WindowWidth:  100,
+			WindowHeight: 100,
+		}, tests.TestRenderer)
+
+		// key, key type, partition, headers and header values
+		for i := 0; i < 5; i++ {
+			cmd := m.Update(tests.Key(tea.KeyEnter))
+			m.Update(cmd())
+		}
+
+		// value
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		tests.NextGroup(m, cmd)
+
+		tests.UpdateKeys(m, "payload")
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		tests.NextGroup(m, cmd)
+
+		publish(m)
+
+		assert.Equal(t, "", producerRecord.Key)
+		assert.False(t, producerRecord.NullKey)
+	})
+
+	t.Run("report invalid payloads without publishing", func(t *testing.T) {
+		published := false
+		m := New(&MockPublisher{
+			PublishRecordFunc: func(p *kadmin.ProducerRecord) kadmin.PublicationStartedMsg {
+				published = true
+				return kadmin.PublicationStartedMsg{}
+			},
+		}, &kadmin.ListedTopic{
+			Name:           "topic1",
+			PartitionCount: 10,
+			Replicas:       1,
+		})
+
+		m.View(&kontext.ProgramKtx{
+			WindowWidth:  100,
+			WindowHeight: 100,
+		}, tests.TestRenderer)
+
+		// key, key type, partition, headers and header values
+		for i := 0; i < 5; i++ {
+			cmd := m.Update(tests.Key(tea.KeyEnter))
+			m.Update(cmd())
+		}
+
+		// value
+		m.Update(tests.Key(tea.KeyRight))
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		tests.NextGroup(m, cmd)
+
+		tests.UpdateKeys(m, "not base64!")
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		tests.NextGroup(m, cmd)
+
+		for _, msg := range publish(m) {
+			m.Update(msg)
+		}
+
+		assert.False(t, published)
+		render := ansi.Strip(m.View(tests.TestKontext, tests.TestRenderer))
+		assert.Contains(t, render, "is no valid base64")
+	})
+
+	t.Run("publish tombstone", func(t *testing.T) {
+		var producerRecord *kadmin.ProducerRecord
+		m := New(&MockPublisher{
+			PublishRecordFunc: func(p *kadmin.ProducerRecord) kadmin.PublicationStartedMsg {
+				producerRecord = p
+				return kadmin.PublicationStartedMsg{}
+			},
+		}, &kadmin.ListedTopic{
+			Name:           "topic1",
+			PartitionCount: 10,
+			Replicas:       1,
+		})
+
+		m.View(&kontext.ProgramKtx{
+			WindowWidth:  100,
+			WindowHeight: 100,
+		}, tests.TestRenderer)
+
+		// Key
+		tests.UpdateKeys(m, "key")
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// key is text
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// Partition
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// headers
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// header values
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// value
+		for i := 0; i < 3; i++ {
+			m.Update(tests.Key(tea.KeyRight))
+		}
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		tests.NextGroup(m, cmd)
+
+		publish(m)
+
+		assert.Equal(t, "key", producerRecord.Key)
+		assert.Equal(t, "topic1", producerRecord.Topic)
+		assert.Nil(t, producerRecord.Value)
+	})
+
+	t.Run("publish serialized with the Schema Registry", func(t *testing.T) {
+		schema := `{"type":"record","name":"Person","fields":[{"name":"Name","type":"string"},{"name":"Age","type":"int"}]}`
+		newSraMock := func(requestedSubject *string) *sradmin.MockSrAdmin {
+			sraMock := sradmin.NewMock()
+			sraMock.GetLatestSchemaBySubjectFunc = func(subject string) tea.Msg {
+				*requestedSubject = subject
+				return sradmin.LatestSchemaBySubjectReceived{Schema: sradmin.Schema{Id: "7", Value: schema}}
+			}
+			return sraMock
+		}
+		fillForm := func(m *Model, payload string) []tea.Msg {
+			m.View(&kontext.ProgramKtx{
+				WindowWidth:  100,
+				WindowHeight: 100,
+			}, tests.TestRenderer)
+
+			// key, key type, partition, headers, header values and value
+			for i := 0; i < 6; i++ {
+				cmd := m.Update(tests.Key(tea.KeyEnter))
+				m.Update(cmd())
+			}
+
+			// serialization
+			m.Update(tests.Key(tea.KeyRight))
+			cmd := m.Update(tests.Key(tea.KeyEnter))
+			m.Update(cmd())
+
+			// subject defaults to the TopicNameStrategy
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			m.Update(cmd())
+
+			// latest version
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			m.Update(cmd())
+
+			// first message
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			tests.NextGroup(m, cmd)
+
+			tests.UpdateKeys(m, payload)
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			tests.NextGroup(m, cmd)
+
+			return publish(m)
+		}
+
+		t.Run("prefix the encoded value with the magic byte and schema id", func(t *testing.T) {
+			var (
+				producerRecord   *kadmin.ProducerRecord
+				requestedSubject string
+			)
+			m := New(&MockPublisher{
+				PublishRecordFunc: func(p *kadmin.ProducerRecord) kadmin.PublicationStartedMsg {
+					producerRecord = p
+					return kadmin.PublicationStartedMsg{}
+				},
+			}, &kadmin.ListedTopic{
+				Name:           "person",
+				PartitionCount: 1,
+				Replicas:       1,
+			}, WithSchemaRegistry(newSraMock(&requestedSubject)))
+
+			fillForm(m, `{"Name":"John","Age":21}`)
+
+			assert.Equal(t, "person-value", requestedSubject)
+			// John is a 4 character string, 21 a zigzag encoded int
+			assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x07, 0x08, 'J', 'o', 'h', 'n', 0x2a}, producerRecord.Value)
+		})
+
+		t.Run("report field errors without publishing", func(t *testing.T) {
+			var (
+				published        bool
+				requestedSubject string
+			)
+			m := New(&MockPublisher{
+				PublishRecordFunc: func(p *kadmin.ProducerRecord) kadmin.PublicationStartedMsg {
+					published = true
+					return kadmin.PublicationStartedMsg{}
+				},
+			}, &kadmin.ListedTopic{
+				Name:           "person",
+				PartitionCount: 1,
+				Replicas:       1,
+			}, WithSchemaRegistry(newSraMock(&requestedSubject)))
+
+			for _, msg := range fillForm(m, `{"Name":"John","Age":"21"}`) {
+				m.Update(msg)
+			}
+
+			assert.False(t, published)
+			render := ansi.Strip(m.View(tests.TestKontext, tests.TestRenderer))
+			assert.Contains(t, render, "Serialization failed")
+			assert.Contains(t, render, "$.Age: expected int, got string")
+		})
+
+		t.Run("insert a template of the latest schema", func(t *testing.T) {
+			var requestedSubject string
+			m := New(&MockPublisher{}, &kadmin.ListedTopic{
+				Name:           "person",
+				PartitionCount: 1,
+				Replicas:       1,
+			}, WithSchemaRegistry(newSraMock(&requestedSubject)))
+			m.View(&kontext.ProgramKtx{
+				WindowWidth:  100,
+				WindowHeight: 100,
+			}, tests.TestRenderer)
+
+			cmd := m.Update(tests.Key(tea.KeyCtrlT))
+			for _, msg := range tests.ExecuteBatchCmd(cmd) {
+				m.Update(msg)
+			}
+
+			assert.Equal(t, "person-value", requestedSubject)
+			assert.Equal(t, "{\n  \"Name\": \"\",\n  \"Age\": 0\n}", m.formValues.Payload)
+			assert.Equal(t, schemaRegistrySerialization, m.formValues.Serialization)
+			render := ansi.Strip(m.View(tests.TestKontext, tests.TestRenderer))
+			assert.Contains(t, render, "Template inserted")
+			assert.Contains(t, render, "1 {")
+		})
+
+		t.Run("report a failing template", func(t *testing.T) {
+			sraMock := sradmin.NewMock()
+			sraMock.GetLatestSchemaBySubjectFunc = func(subject string) tea.Msg {
+				return sradmin.FailedToFetchLatestSchemaBySubject{Err: errors.New("subject not found")}
+			}
+			m := New(&MockPublisher{}, &kadmin.ListedTopic{
+				Name:           "person",
+				PartitionCount: 1,
+				Replicas:       1,
+			}, WithSchemaRegistry(sraMock))
+			m.View(tests.TestKontext, tests.TestRenderer)
+
+			cmd := m.Update(tests.Key(tea.KeyCtrlT))
+			for _, msg := range tests.ExecuteBatchCmd(cmd) {
+				m.Update(msg)
+			}
+
+			assert.Empty(t, m.formValues.Payload)
+			render := ansi.Strip(m.View(tests.TestKontext, tests.TestRenderer))
+			assert.Contains(t, render, "Unable to insert template")
+			assert.Contains(t, render, "subject not found")
+		})
+	})
+
+	t.Run("insert template only with a Schema Registry", func(t *testing.T) {
+		m := New(&MockPublisher{}, &kadmin.ListedTopic{
+			Name:           "person",
+			PartitionCount: 1,
+			Replicas:       1,
+		})
+		m.View(tests.TestKontext, tests.TestRenderer)
+
+		cmd := m.Update(tests.Key(tea.KeyCtrlT))
+
+		assert.Empty(t, tests.ExecuteBatchCmd(cmd))
+		assert.NotContains(t, m.Shortcuts(), statusbar.Shortcut{"Insert Template", "C-t"})
+	})
+
 	t.Run("reset form after successful publication", func(t *testing.T) {
 		m := New(&MockPublisher{
 			PublishRecordFunc: func(p *kadmin.ProducerRecord) kadmin.PublicationStartedMsg {
@@ -179,6 +620,9 @@ func TestPublish(t *testing.T) {
 		tests.UpdateKeys(m, "key")
 		cmd := m.Update(tests.Key(tea.KeyEnter))
 		m.Update(cmd())
+		// key is text
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
 
 		// Partition
 		cmd = m.Update(tests.Key(tea.KeyEnter))
@@ -189,6 +633,14 @@ func TestPublish(t *testing.T) {
 		cmd = m.Update(tests.KeyWithAlt(tea.KeyEnter))
 		tests.UpdateKeys(m, "user=456")
 		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// header values
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// value
+		cmd = m.Update(tests.Key(tea.KeyEnter))
 		tests.NextGroup(m, cmd)
 
 		// payload
@@ -196,7 +648,7 @@ func TestPublish(t *testing.T) {
 		cmd = m.Update(tests.Key(tea.KeyEnter))
 		tests.NextGroup(m, cmd)
 
-		tests.Submit(m)
+		publish(m)
 
 		m.Update(kadmin.PublicationSucceeded{})
 
@@ -229,6 +681,9 @@ func TestPublish(t *testing.T) {
 		tests.UpdateKeys(m, "key")
 		cmd := m.Update(tests.Key(tea.KeyEnter))
 		m.Update(cmd())
+		// key is text
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
 
 		// Partition
 		cmd = m.Update(tests.Key(tea.KeyEnter))
@@ -236,6 +691,14 @@ func TestPublish(t *testing.T) {
 
 		// headers
 		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// header values
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// value
+		cmd = m.Update(tests.Key(tea.KeyEnter))
 		tests.NextGroup(m, cmd)
 
 		// payload
@@ -243,7 +706,7 @@ func TestPublish(t *testing.T) {
 		cmd = m.Update(tests.Key(tea.KeyEnter))
 		tests.NextGroup(m, cmd)
 
-		tests.Submit(m)
+		publish(m)
 
 		assert.Equal(t, "key", producerRecord.Key)
 		assert.Equal(t, "topic1", producerRecord.Topic)
@@ -300,6 +763,9 @@ func TestPublish(t *testing.T) {
 		tests.UpdateKeys(m, "key")
 		cmd := m.Update(tests.Key(tea.KeyEnter))
 		m.Update(cmd())
+		// key is text
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
 
 		// Partition
 		cmd = m.Update(tests.Key(tea.KeyEnter))
@@ -310,6 +776,14 @@ func TestPublish(t *testing.T) {
 		cmd = m.Update(tests.KeyWithAlt(tea.KeyEnter))
 		tests.UpdateKeys(m, "user=456")
 		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// header values
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		// value
+		cmd = m.Update(tests.Key(tea.KeyEnter))
 		tests.NextGroup(m, cmd)
 
 		// payload
@@ -328,6 +802,39 @@ func TestPublish(t *testing.T) {
 
 	t.Run("Validate", func(t *testing.T) {
 
+		t.Run("When timestamp is invalid", func(t *testing.T) {
+			m := New(&MockPublisher{}, &kadmin.ListedTopic{
+				Name:           "topic1",
+				PartitionCount: 1,
+				Replicas:       1,
+			})
+
+			m.View(&kontext.ProgramKtx{
+				WindowWidth:  100,
+				WindowHeight: 100,
+			}, tests.TestRenderer)
+			// key, key type, partition, headers and header values
+			for i := 0; i < 5; i++ {
+				cmd := m.Update(tests.Key(tea.KeyEnter))
+				m.Update(cmd())
+			}
+			// value
+			cmd := m.Update(tests.Key(tea.KeyEnter))
+			tests.NextGroup(m, cmd)
+			// payload
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			tests.NextGroup(m, cmd)
+			// timestamp
+			tests.UpdateKeys(m, "yesterday")
+			m.Update(tests.Key(tea.KeyEnter))
+
+			render := m.View(&kontext.ProgramKtx{
+				WindowWidth:  100,
+				WindowHeight: 100,
+			}, tests.TestRenderer)
+			assert.Contains(t, render, "'yesterday' is no RFC 3339 timestamp or epoch millis")
+		})
+
 		t.Run("When partition is not a number", func(t *testing.T) {
 			m := New(&MockPublisher{}, &kadmin.ListedTopic{
 				Name:           "topic1",
@@ -343,6 +850,9 @@ func TestPublish(t *testing.T) {
 			tests.UpdateKeys(m, "key")
 			cmd := m.Update(tests.Key(tea.KeyEnter))
 			m.Update(cmd())
+			// key is text
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			m.Update(cmd())
 			// Partition
 			tests.UpdateKeys(m, "a1")
 			m.Update(tests.Key(tea.KeyEnter))
@@ -369,6 +879,9 @@ func TestPublish(t *testing.T) {
 			tests.UpdateKeys(m, "key")
 			cmd := m.Update(tests.Key(tea.KeyEnter))
 			m.Update(cmd())
+			// key is text
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			m.Update(cmd())
 			// Partition
 			tests.UpdateKeys(m, "-1")
 			m.Update(tests.Key(tea.KeyEnter))
@@ -395,6 +908,9 @@ func TestPublish(t *testing.T) {
 			tests.UpdateKeys(m, "key")
 			cmd := m.Update(tests.Key(tea.KeyEnter))
 			m.Update(cmd())
+			// key is text
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			m.Update(cmd())
 			// Partition
 			tests.UpdateKeys(m, "0")
 			m.Update(tests.Key(tea.KeyEnter))
@@ -423,6 +939,9 @@ func TestPublish(t *testing.T) {
 			tests.UpdateKeys(m, "key")
 			cmd := m.Update(tests.Key(tea.KeyEnter))
 			m.Update(cmd())
+			// key is text
+			cmd = m.Update(tests.Key(tea.KeyEnter))
+			m.Update(cmd())
 			// Partition
 			tests.UpdateKeys(m, "10")
 			m.Update(tests.Key(tea.KeyEnter))
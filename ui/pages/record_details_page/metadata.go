@@ -0,0 +1,117 @@
+package record_details_page
+
+import (
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/dustin/go-humanize"
+	"ktea/kadmin"
+	"ktea/sradmin"
+	"strings"
+	"time"
+)
+
+// metadata renders the metadata panel of the record, sizes are those of the serialized bytes.
+func (m *Model) metadata() string {
+	record := m.record
+
+	key := record.Key
+	if key == "" {
+		key = "<null>"
+	}
+
+	lines := []string{
+		"key: " + key,
+		"timestamp: " + record.Timestamp.Format(time.UnixDate),
+		"timestamp type: " + record.TimestampType.String(),
+	}
+	if !record.BlockTimestamp.IsZero() {
+		lines = append(lines, "block timestamp: "+record.BlockTimestamp.Format(time.UnixDate))
+	}
+
+	lines = append(lines, "key size: "+size(record.RawKey))
+	if record.Tombstone {
+		lines = append(lines, "value size: null")
+	} else {
+		lines = append(lines, "value size: "+size(record.RawValue))
+	}
+
+	var headerBytes int
+	for _, header := range record.Headers {
+		headerBytes += len(header.Key) + len(header.Value.Raw())
+	}
+	lines = append(lines, fmt.Sprintf("headers: %d (%s)", len(record.Headers), humanize.Bytes(uint64(headerBytes))))
+
+	if record.KeySchemaId != 0 {
+		lines = append(lines, "key schema: "+m.schemaInfo(record.KeySchemaId))
+	}
+	if record.Payload.SchemaId != 0 {
+		lines = append(lines, "value schema: "+m.schemaInfo(record.Payload.SchemaId))
+	}
+
+	if partition := kadmin.JVMPartition(record.RawKey, m.partitionCount); partition >= 0 {
+		jvmPartition := fmt.Sprintf("jvm partition: %d", partition)
+		if int64(partition) != record.Partition {
+			jvmPartition += fmt.Sprintf(" (read from %d)", record.Partition)
+		}
+		lines = append(lines, jvmPartition)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func size(data []byte) string {
+	if data == nil {
+		return "null"
+	}
+	return humanize.Bytes(uint64(len(data)))
+}
+
+// schemaInfo shows the id of the schema followed by the subjects it is registered under, once known.
+func (m *Model) schemaInfo(schemaId int) string {
+	info := fmt.Sprintf("id %d", schemaId)
+	subjects := m.schemaSubjects[schemaId]
+	if len(subjects) == 0 {
+		return info
+	}
+	var registrations []string
+	for _, subject := range subjects {
+		registrations = append(registrations, fmt.Sprintf("%s v%d", subject.Subject, subject.Version))
+	}
+	return info + " (" + strings.Join(registrations, ", ") + ")"
+}
+
+// LoadSchemaSubjects looks up the subjects of the key and value schema.
+func (m *Model) LoadSchemaSubjects() tea.Cmd {
+	if m.subjectsLister == nil {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	for _, schemaId := range []int{m.record.KeySchemaId, m.record.Payload.SchemaId} {
+		if _, loading := m.schemaSubjects[schemaId]; schemaId == 0 || loading {
+			continue
+		}
+		m.schemaSubjects[schemaId] = nil
+		cmds = append(cmds, func() tea.Msg {
+			msg := m.subjectsLister.ListSchemaSubjects(schemaId)
+			if started, ok := msg.(sradmin.SchemaSubjectsListingStartedMsg); ok {
+				return started.AwaitCompletion()
+			}
+			return msg
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) updateSchemaSubjects(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case sradmin.SchemaSubjectsListedMsg:
+		m.schemaSubjects[msg.SchemaId] = msg.Subjects
+		return true
+	case sradmin.SchemaSubjectsListingErrMsg:
+		log.Error("Unable to list the subjects of schema", "id", msg.SchemaId, "err", msg.Err)
+		return true
+	}
+	return false
+}
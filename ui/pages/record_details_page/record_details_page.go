@@ -1,15 +1,19 @@
 package record_details_page
 
 import (
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	"ktea/config"
 	"ktea/kadmin"
 	"ktea/kontext"
+	"ktea/sradmin"
 	"ktea/styles"
 	"ktea/ui"
 	"ktea/ui/clipper"
@@ -19,10 +23,10 @@ import (
 	"ktea/ui/components/statusbar"
 	ktable "ktea/ui/components/table"
 	"ktea/ui/pages/nav"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
-	"time"
 )
 
 type focus bool
@@ -47,11 +51,54 @@ type Model struct {
 	state          state
 	payload        string
 	err            error
-	metaInfo       string
 	clipWriter     clipper.Writer
 	config         *config.Config
 	schemaVp       *viewport.Model
 	border         *border.Model
+	// hexDump shows the raw key and value bytes instead of the deserialized payload
+	hexDump bool
+	// headerDecoders holds the decoder per header key
+	headerDecoders map[string]kadmin.HeaderDecoder
+	// rawHeaders shows the raw bytes of the header values instead of the decoded value
+	rawHeaders bool
+	// tree is the payload as a collapsible tree, nil when the payload is no JSON object or array
+	tree     *ui.JsonTree
+	treeView bool
+	// searchInput is set while a search term is entered in the tree view
+	searchInput *huh.Input
+	searchTerm  string
+	// publisher publishes tombstones, the action is unavailable when it is nil
+	publisher       kadmin.Publisher
+	tombstoneCmdBar *cmdbar.DeleteCmdBar[*kadmin.ConsumerRecord]
+	// partitionCount of the topic, the partition the JVM partitioner assigns the key to is shown when known
+	partitionCount int
+	// subjectsLister looks up the subjects of the schemas, they are not shown when it is nil
+	subjectsLister sradmin.SchemaSubjectsLister
+	schemaSubjects map[int][]sradmin.SubjectVersion
+}
+
+type Option func(m *Model)
+
+// WithPartitionCount shows the partition the JVM partitioner assigns the key to.
+func WithPartitionCount(partitionCount int) Option {
+	return func(m *Model) {
+		m.partitionCount = partitionCount
+	}
+}
+
+// WithSchemaSubjectsLister shows the subjects the schemas of the record are registered under,
+// they are loaded by LoadSchemaSubjects.
+func WithSchemaSubjectsLister(lister sradmin.SchemaSubjectsLister) Option {
+	return func(m *Model) {
+		m.subjectsLister = lister
+	}
+}
+
+// WithPublisher enables publishing a tombstone for the key of the record.
+func WithPublisher(publisher kadmin.Publisher) Option {
+	return func(m *Model) {
+		m.publisher = publisher
+	}
 }
 
 type PayloadCopiedMsg struct {
@@ -63,14 +110,47 @@ type SchemaCopiedMsg struct {
 type HeaderValueCopiedMsg struct {
 }
 
+type JsonValueCopiedMsg struct {
+}
+
+type JsonPathCopiedMsg struct {
+	Path string
+}
+
+type NoJsonMatchMsg struct {
+	Term string
+}
+
 type CopyErrorMsg struct {
 	Err error
 }
 
+type TombstonePublishedMsg struct {
+	Key string
+}
+
+type TombstonePublicationFailedMsg struct {
+	Err error
+}
+
 func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
 
 	notifierCmdbarView := m.notifierCmdbar.View(ktx, renderer)
 
+	var tombstoneView string
+	if m.tombstoneCmdBar.IsFocussed() {
+		tombstoneView = m.tombstoneCmdBar.View(ktx, renderer)
+	}
+
+	var searchView string
+	if m.searchInput != nil {
+		searchView = renderer.RenderWithStyle(
+			m.searchInput.View(),
+			styles.CmdBarWithWidth(ktx.WindowWidth-cmdbar.BorderedPadding).
+				BorderForeground(lipgloss.Color(styles.ColorFocusBorder)),
+		)
+	}
+
 	width := int(float64(ktx.WindowWidth) * 0.70)
 	height := ktx.AvailableHeight - 2
 
@@ -80,6 +160,8 @@ func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
 	return ui.JoinVertical(
 		lipgloss.Top,
 		notifierCmdbarView,
+		tombstoneView,
+		searchView,
 		lipgloss.NewStyle().Render(lipgloss.JoinHorizontal(
 			lipgloss.Top,
 			m.border.View(mainView),
@@ -87,6 +169,9 @@ func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
 }
 
 func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	if m.updateSchemaSubjects(msg) {
+		return nil
+	}
 	if m.recordVp == nil && m.err == nil {
 		return nil
 	}
@@ -97,10 +182,36 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	cmds = append(cmds, cmd)
 
 	switch msg := msg.(type) {
+	case kadmin.PublicationStartedMsg:
+		return tea.Batch(append(cmds, func() tea.Msg {
+			if failed, ok := msg.AwaitCompletion().(kadmin.PublicationFailed); ok {
+				return TombstonePublicationFailedMsg{Err: failed.Err}
+			}
+			return TombstonePublishedMsg{Key: m.record.Key}
+		})...)
 	case tea.KeyMsg:
+		if m.tombstoneCmdBar.IsFocussed() {
+			_, _, cmd := m.tombstoneCmdBar.Update(msg)
+			return tea.Batch(append(cmds, cmd)...)
+		}
+		if m.searchInput != nil {
+			return tea.Batch(append(cmds, m.updateSearch(msg))...)
+		}
+		if m.isTreeFocussed() {
+			if cmd, handled := m.updateTree(msg); handled {
+				return tea.Batch(append(cmds, cmd)...)
+			}
+		}
+
 		switch msg.String() {
 		case "esc":
 			return ui.PublishMsg(nav.LoadCachedConsumptionPageMsg{})
+		case "f2":
+			if m.canPublishTombstone() {
+				m.tombstoneCmdBar.Delete(m.record)
+				_, _, cmd := m.tombstoneCmdBar.Update(msg)
+				cmds = append(cmds, cmd)
+			}
 		case "ctrl+h", "left", "right":
 			if len(m.record.Headers) >= 1 {
 				m.focus = !m.focus
@@ -108,8 +219,27 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			}
 		case "c":
 			cmds = m.handleCopy(cmds)
+		case "t":
+			if m.focus == headersViewFocus {
+				m.nextHeaderDecoder()
+			}
+		case "r":
+			if m.focus == headersViewFocus {
+				m.rawHeaders = !m.rawHeaders
+			}
+		case "v":
+			if m.tree != nil && m.focus == mainViewFocus && m.state == recordView && !m.hexDump {
+				m.treeView = !m.treeView
+				m.recordVp = nil
+			}
+		case "x":
+			if m.focus == mainViewFocus && m.state == recordView {
+				m.hexDump = !m.hexDump
+				// recreate the viewport with the content of the toggled view
+				m.recordVp = nil
+			}
 		case "tab":
-			if m.record.Payload.Schema != "" && m.focus == mainViewFocus {
+			if m.hasSchema() && m.focus == mainViewFocus {
 				m.state = !m.state
 				m.border.NextTab()
 			}
@@ -121,6 +251,105 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// canPublishTombstone reports whether a tombstone can be published for the key of the record,
+// records without a key can not be deleted.
+func (m *Model) canPublishTombstone() bool {
+	return m.publisher != nil && m.record.RawKey != nil && !m.record.Transaction.IsMarker()
+}
+
+// publishTombstone publishes a null value for the raw key of the record to its partition,
+// so compaction removes the key.
+func (m *Model) publishTombstone(record *kadmin.ConsumerRecord) tea.Cmd {
+	return func() tea.Msg {
+		partition := int(record.Partition)
+		return m.publisher.PublishRecord(&kadmin.ProducerRecord{
+			Key:       string(record.RawKey),
+			Value:     nil,
+			Topic:     m.topicName,
+			Partition: &partition,
+		})
+	}
+}
+
+// isTreeFocussed reports whether the keys are handled by the tree view.
+func (m *Model) isTreeFocussed() bool {
+	return m.treeView && !m.hexDump && m.focus == mainViewFocus && m.state == recordView
+}
+
+func (m *Model) updateTree(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		m.tree.MoveUp(1)
+	case "down", "j":
+		m.tree.MoveDown(1)
+	case "pgup", "u":
+		m.tree.MoveUp(m.treePageSize())
+	case "pgdown", "d":
+		m.tree.MoveDown(m.treePageSize())
+	case "home", "g":
+		m.tree.MoveUp(m.tree.Cursor())
+	case "end", "G":
+		m.tree.MoveDown(math.MaxInt)
+	case "enter", " ":
+		m.tree.Toggle()
+	case "+":
+		m.tree.ExpandAll()
+	case "-":
+		m.tree.CollapseAll()
+	case "/":
+		m.searchInput = huh.NewInput().Title("Search: ").Inline(true)
+		m.searchInput.Init()
+		m.searchInput.Focus()
+	case "n", "N":
+		if m.searchTerm != "" && !m.tree.Search(m.searchTerm, msg.String() == "n") {
+			return ui.PublishMsg(NoJsonMatchMsg{Term: m.searchTerm}), true
+		}
+	case "c":
+		return m.copy(m.tree.Focused().Value(), JsonValueCopiedMsg{}), true
+	case "p":
+		path := m.tree.Focused().Path()
+		return m.copy(path, JsonPathCopiedMsg{Path: path}), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// treePageSize is the number of lines the cursor moves when paging, half the height of the view.
+func (m *Model) treePageSize() int {
+	if m.recordVp == nil {
+		return 1
+	}
+	return max(m.recordVp.Height/2, 1)
+}
+
+func (m *Model) updateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.searchInput = nil
+	case "enter":
+		m.searchTerm = m.searchInput.GetValue().(string)
+		m.searchInput = nil
+		if m.searchTerm != "" && !m.tree.Search(m.searchTerm, true) {
+			return ui.PublishMsg(NoJsonMatchMsg{Term: m.searchTerm})
+		}
+	default:
+		input, cmd := m.searchInput.Update(msg)
+		if i, ok := input.(*huh.Input); ok {
+			m.searchInput = i
+		}
+		return cmd
+	}
+	return nil
+}
+
+func (m *Model) copy(value string, copiedMsg tea.Msg) tea.Cmd {
+	if err := m.clipWriter.Write(value); err != nil {
+		return ui.PublishMsg(CopyErrorMsg{Err: err})
+	}
+	return ui.PublishMsg(copiedMsg)
+}
+
 func (m *Model) mainView(width int, height int) string {
 	var mainView string
 	if m.state == recordView {
@@ -137,9 +366,7 @@ func (m *Model) schemaView(width int, height int) string {
 		schemaVp := viewport.New(width, height)
 		m.schemaVp = &schemaVp
 		if m.err == nil {
-			m.schemaVp.SetContent(lipgloss.NewStyle().
-				Padding(0, 1).
-				Render(ui.PrettyPrintJson(m.record.Payload.Schema)))
+			m.schemaVp.SetContent(m.schemaContent(width))
 		}
 	} else {
 		m.schemaVp.Height = height
@@ -148,6 +375,37 @@ func (m *Model) schemaView(width int, height int) string {
 	return m.schemaVp.View()
 }
 
+// schemaContent renders the key schema next to the value schema,
+// or only the schema that is present.
+func (m *Model) schemaContent(width int) string {
+	keySchema, valueSchema := m.record.KeySchema, m.record.Payload.Schema
+	if keySchema == "" || valueSchema == "" {
+		return lipgloss.NewStyle().
+			Padding(0, 1).
+			Render(ui.PrettyPrintJson(keySchema + valueSchema))
+	}
+
+	column := func(title, schema string) string {
+		return lipgloss.NewStyle().
+			Padding(0, 1).
+			Width(width / 2).
+			Render(lipgloss.JoinVertical(
+				lipgloss.Left,
+				lipgloss.NewStyle().Bold(true).Render(title),
+				ui.PrettyPrintJson(schema),
+			))
+	}
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		column("Key Schema", keySchema),
+		column("Value Schema", valueSchema),
+	)
+}
+
+func (m *Model) hasSchema() bool {
+	return m.record.Payload.Schema != "" || m.record.KeySchema != ""
+}
+
 func (m *Model) sidebarView(ktx *kontext.ProgramKtx, payloadWidth int, height int) string {
 	headersTableStyle := m.headerStyle()
 	sideBarWidth := ktx.WindowWidth - (payloadWidth + 7)
@@ -156,7 +414,7 @@ func (m *Model) sidebarView(ktx *kontext.ProgramKtx, payloadWidth int, height in
 	if len(m.record.Headers) == 0 {
 		headerSideBar = ui.JoinVertical(
 			lipgloss.Top,
-			lipgloss.NewStyle().Padding(1).Render(m.metaInfo),
+			lipgloss.NewStyle().Padding(1).Render(m.metadata()),
 			lipgloss.JoinVertical(lipgloss.Center, lipgloss.NewStyle().Padding(1).Render("No headers present")),
 		)
 	} else {
@@ -176,34 +434,98 @@ func (m *Model) sidebarView(ktx *kontext.ProgramKtx, payloadWidth int, height in
 		}
 
 		headerValue := m.selectedHeaderValue()
-		m.headerValueVp.SetContent("Header Value\n" + headerValueLine.String() + "\n" + headerValue)
+		m.headerValueVp.SetContent(m.headerValueTitle() + "\n" + headerValueLine.String() + "\n" + headerValue)
 
 		headerSideBar = ui.JoinVertical(
 			lipgloss.Top,
-			lipgloss.NewStyle().Padding(1).Render(m.metaInfo),
+			lipgloss.NewStyle().Padding(1).Render(m.metadata()),
 			headersTableStyle.Render(lipgloss.JoinVertical(lipgloss.Top, m.headerKeyTable.View(), m.headerValueVp.View())),
 		)
 	}
 	return headerSideBar
 }
 
+func (m *Model) selectedHeader() *kadmin.Header {
+	if len(m.record.Headers) == 0 {
+		return nil
+	}
+	if m.headerKeyTable.SelectedRow() == nil {
+		return &m.record.Headers[0]
+	}
+	return &m.record.Headers[m.headerKeyTable.Cursor()]
+}
+
 func (m *Model) selectedHeaderValue() string {
-	selectedRow := m.headerKeyTable.SelectedRow()
-	if selectedRow == nil {
-		if len(m.record.Headers) > 0 {
-			return m.record.Headers[0].Value.String()
-		}
-	} else {
-		return m.record.Headers[m.headerKeyTable.Cursor()].Value.String()
+	header := m.selectedHeader()
+	if header == nil {
+		return ""
+	}
+
+	if m.rawHeaders {
+		return hex.Dump(header.Value.Raw())
+	}
+
+	decoder := m.headerDecoders[header.Key]
+	value, err := header.Value.Decode(decoder)
+	if err != nil {
+		return fmt.Sprintf("Unable to decode as %s: %s", decoder, err)
+	}
+	return value
+}
+
+func (m *Model) headerValueTitle() string {
+	if m.rawHeaders {
+		return "Header Value (raw)"
+	}
+	if header := m.selectedHeader(); header != nil {
+		return fmt.Sprintf("Header Value (%s)", m.headerDecoders[header.Key])
+	}
+	return "Header Value"
+}
+
+// nextHeaderDecoder switches the selected header key to the next decoder, and remembers it.
+func (m *Model) nextHeaderDecoder() {
+	header := m.selectedHeader()
+	if header == nil {
+		return
+	}
+
+	decoder := m.headerDecoders[header.Key].Next()
+	m.headerDecoders[header.Key] = decoder
+	m.rawHeaders = false
+
+	if m.config == nil {
+		return
+	}
+	if cluster := m.config.ActiveCluster(); cluster != nil {
+		m.config.SaveHeaderDecoder(cluster.Name, header.Key, string(decoder))
 	}
-	return ""
 }
 
 func (m *Model) recordView(payloadWidth int, height int) string {
+	if m.treeView && !m.hexDump {
+		return m.treeContent(payloadWidth, height)
+	}
+
 	if m.recordVp == nil {
 		recordVp := viewport.New(payloadWidth, height)
 		m.recordVp = &recordVp
-		if m.err == nil {
+		if m.hexDump {
+			m.recordVp.SetContent(lipgloss.NewStyle().
+				Padding(0, 1).
+				Render(m.hexDumpContent()))
+		} else if m.record.Tombstone {
+			m.recordVp.SetContent(lipgloss.NewStyle().
+				AlignHorizontal(lipgloss.Center).
+				AlignVertical(lipgloss.Center).
+				Width(payloadWidth).
+				Height(height).
+				Render(lipgloss.NewStyle().
+					Bold(true).
+					Padding(1).
+					Foreground(lipgloss.Color(styles.ColorGrey)).
+					Render("∅ Tombstone, the value is null")))
+		} else if m.err == nil {
 			m.recordVp.SetContent(lipgloss.NewStyle().
 				Padding(0, 1).
 				Render(m.payload))
@@ -226,6 +548,46 @@ func (m *Model) recordView(payloadWidth int, height int) string {
 	return m.recordVp.View()
 }
 
+// treeContent renders the tree, scrolled so the focused node is visible.
+func (m *Model) treeContent(width int, height int) string {
+	if m.recordVp == nil {
+		recordVp := viewport.New(width, height)
+		m.recordVp = &recordVp
+	}
+	m.recordVp.Width = width
+	m.recordVp.Height = height
+	m.recordVp.SetContent(lipgloss.NewStyle().
+		Padding(0, 1).
+		Render(m.tree.Render(width - 2)))
+
+	cursor := m.tree.Cursor()
+	if cursor < m.recordVp.YOffset {
+		m.recordVp.SetYOffset(cursor)
+	} else if cursor >= m.recordVp.YOffset+height {
+		m.recordVp.SetYOffset(cursor - height + 1)
+	}
+	return m.recordVp.View()
+}
+
+// hexDumpContent renders the raw key and value bytes as a hex and ASCII dump.
+func (m *Model) hexDumpContent() string {
+	return m.hexDumpSection("Key", m.record.RawKey) + "\n" + m.hexDumpSection("Value", m.record.RawValue)
+}
+
+func (m *Model) hexDumpSection(title string, data []byte) string {
+	header := fmt.Sprintf("%s (%d bytes)", title, len(data))
+	// the Schema Registry wire format starts with a magic byte followed by a 4 byte schema id
+	if len(data) >= 5 && data[0] == 0x00 {
+		header += fmt.Sprintf(", magic byte 0x00, schema id %d", binary.BigEndian.Uint32(data[1:5]))
+	}
+	header = lipgloss.NewStyle().Bold(true).Render(header)
+
+	if len(data) == 0 {
+		return header + "\n<null>\n"
+	}
+	return header + "\n" + hex.Dump(data)
+}
+
 func (m *Model) headerStyle() lipgloss.Style {
 	var headersTableStyle lipgloss.Style
 	if m.focus == mainViewFocus {
@@ -248,7 +610,14 @@ func (m *Model) handleCopy(cmds []tea.Cmd) []tea.Cmd {
 	if m.focus == mainViewFocus {
 		var copiedValue string
 		if m.state == schemaView {
-			copiedValue = ansi.Strip(m.record.Payload.Schema)
+			// the value schema takes precedence, the key schema is copied when it is the only one
+			copiedValue = m.record.Payload.Schema
+			if copiedValue == "" {
+				copiedValue = m.record.KeySchema
+			}
+			copiedValue = ansi.Strip(copiedValue)
+		} else if m.hexDump {
+			copiedValue = ansi.Strip(m.hexDumpContent())
 		} else {
 			copiedValue = ansi.Strip(m.payload)
 		}
@@ -274,8 +643,8 @@ func (m *Model) handleCopy(cmds []tea.Cmd) []tea.Cmd {
 }
 
 func (m *Model) updatedFocussedArea(msg tea.Msg, cmds []tea.Cmd) []tea.Cmd {
-	// only update component if no error is present
-	if m.err != nil {
+	// only update component if no error is present, the hex dump is always available
+	if m.err != nil && !m.hexDump {
 		return cmds
 	}
 
@@ -298,10 +667,16 @@ func (m *Model) updatedFocussedArea(msg tea.Msg, cmds []tea.Cmd) []tea.Cmd {
 }
 
 func (m *Model) Shortcuts() []statusbar.Shortcut {
+	if m.tombstoneCmdBar.IsFocussed() {
+		return m.tombstoneCmdBar.Shortcuts()
+	}
+
 	whatToCopy := "Header Value"
 	if m.focus == mainViewFocus {
 		if m.state == schemaView {
 			whatToCopy = "Schema"
+		} else if m.isTreeFocussed() {
+			whatToCopy = "Value"
 		} else {
 			whatToCopy = "Record"
 		}
@@ -320,10 +695,45 @@ func (m *Model) Shortcuts() []statusbar.Shortcut {
 			})
 		}
 
+		if m.isTreeFocussed() {
+			shortcuts = append(shortcuts,
+				statusbar.Shortcut{Name: "Copy Path", Keybinding: "p"},
+				statusbar.Shortcut{Name: "Expand/Collapse", Keybinding: "enter"},
+				statusbar.Shortcut{Name: "Expand/Collapse All", Keybinding: "+/-"},
+				statusbar.Shortcut{Name: "Search", Keybinding: "/"},
+				statusbar.Shortcut{Name: "Next/Previous Match", Keybinding: "n/N"},
+			)
+		}
+
+		if m.focus == mainViewFocus {
+			if m.tree != nil && m.state == recordView && !m.hexDump {
+				shortcuts = append(shortcuts, statusbar.Shortcut{
+					Name:       "Toggle Tree View",
+					Keybinding: "v",
+				})
+			}
+			shortcuts = append(shortcuts, statusbar.Shortcut{
+				Name:       "Toggle Hex Dump",
+				Keybinding: "x",
+			})
+			if m.canPublishTombstone() {
+				shortcuts = append(shortcuts, statusbar.Shortcut{
+					Name:       "Publish Tombstone",
+					Keybinding: "F2",
+				})
+			}
+		} else {
+			shortcuts = append(shortcuts,
+				statusbar.Shortcut{Name: "Cycle Header Decoder", Keybinding: "t"},
+				statusbar.Shortcut{Name: "Toggle Raw Header", Keybinding: "r"},
+			)
+		}
+
 		return shortcuts
 	} else {
 		return []statusbar.Shortcut{
 			{"Go Back", "esc"},
+			{"Toggle Hex Dump", "x"},
 		}
 	}
 }
@@ -337,6 +747,7 @@ func New(
 	topicName string,
 	clipWriter clipper.Writer,
 	ktx *kontext.ProgramKtx,
+	options ...Option,
 ) *Model {
 	headersTable := ktable.NewDefaultTable()
 
@@ -344,30 +755,36 @@ func New(
 	sort.SliceStable(record.Headers, func(i, j int) bool {
 		return record.Headers[i].Key < record.Headers[j].Key
 	})
+	headerDecoders := make(map[string]kadmin.HeaderDecoder)
 	for _, header := range record.Headers {
 		headerRows = append(headerRows, table.Row{header.Key})
+		if ktx.Config != nil && ktx.Config.ActiveCluster() != nil {
+			headerDecoders[header.Key] = kadmin.HeaderDecoder(ktx.Config.ActiveCluster().HeaderDecoder(header.Key))
+		}
 	}
 
 	notifierCmdBar := cmdbar.NewNotifierCmdBar("record-details-page")
 
 	var (
 		payload string
+		tree    *ui.JsonTree
 		err     error
 	)
 	if record.Err == nil {
 		payload = ui.PrettyPrintJson(record.Payload.Value)
+		// payloads that are no JSON object or array can only be shown as is
+		tree, _ = ui.ParseJsonTree(record.Payload.Value)
 	} else {
 		err = record.Err
 		notifierCmdBar.Notifier.ShowError(record.Err)
 	}
-
-	key := record.Key
-	if key == "" {
-		key = "<null>"
+	if violations := record.Payload.Violations; record.Err == nil && len(violations) > 0 {
+		notifierCmdBar.Notifier.ShowWarningMsg(
+			fmt.Sprintf("%d schema violations", len(violations)),
+			strings.Join(violations, ", "),
+		)
 	}
 
-	metaInfo := fmt.Sprintf("key: %s\ntimestamp: %s", key, record.Timestamp.Format(time.UnixDate))
-
 	cmdbar.WithMsgHandler(notifierCmdBar, func(msg PayloadCopiedMsg, m *notifier.Model) (bool, tea.Cmd) {
 		m.ShowSuccessMsg("Payload copied")
 		return true, m.AutoHideCmd("record-details-page")
@@ -380,13 +797,36 @@ func New(
 		m.ShowSuccessMsg("Header Value copied")
 		return true, m.AutoHideCmd("record-details-page")
 	})
+	cmdbar.WithMsgHandler(notifierCmdBar, func(msg JsonValueCopiedMsg, m *notifier.Model) (bool, tea.Cmd) {
+		m.ShowSuccessMsg("Value copied")
+		return true, m.AutoHideCmd("record-details-page")
+	})
+	cmdbar.WithMsgHandler(notifierCmdBar, func(msg JsonPathCopiedMsg, m *notifier.Model) (bool, tea.Cmd) {
+		m.ShowSuccessMsg("Path " + msg.Path + " copied")
+		return true, m.AutoHideCmd("record-details-page")
+	})
+	cmdbar.WithMsgHandler(notifierCmdBar, func(msg NoJsonMatchMsg, m *notifier.Model) (bool, tea.Cmd) {
+		m.ShowWarningMsg("No match", "nothing matches "+msg.Term)
+		return true, m.AutoHideCmd("record-details-page")
+	})
 	cmdbar.WithMsgHandler(notifierCmdBar, func(msg CopyErrorMsg, m *notifier.Model) (bool, tea.Cmd) {
 		m.ShowErrorMsg("Copy failed", msg.Err)
 		return true, m.AutoHideCmd("record-details-page")
 	})
+	cmdbar.WithMsgHandler(notifierCmdBar, func(msg kadmin.PublicationStartedMsg, m *notifier.Model) (bool, tea.Cmd) {
+		return true, m.SpinWithLoadingMsg("Publishing tombstone")
+	})
+	cmdbar.WithMsgHandler(notifierCmdBar, func(msg TombstonePublishedMsg, m *notifier.Model) (bool, tea.Cmd) {
+		m.ShowSuccessMsg("Tombstone published for key " + msg.Key)
+		return true, m.AutoHideCmd("record-details-page")
+	})
+	cmdbar.WithMsgHandler(notifierCmdBar, func(msg TombstonePublicationFailedMsg, m *notifier.Model) (bool, tea.Cmd) {
+		m.ShowErrorMsg("Publishing tombstone failed", msg.Err)
+		return true, m.AutoHideCmd("record-details-page")
+	})
 
 	var tabs []border.Tab
-	if record.Payload.Schema != "" {
+	if record.Payload.Schema != "" || record.KeySchema != "" {
 		tabs = []border.Tab{
 			{Title: "Record", TabLabel: "record"},
 			{Title: "Schema", TabLabel: "record"},
@@ -394,9 +834,9 @@ func New(
 	}
 	b := border.New(
 		border.WithTabs(tabs...),
-		border.WithTitle("AVRO Record"))
+		border.WithTitle("Record"))
 
-	return &Model{
+	m := &Model{
 		record:         record,
 		topicName:      topicName,
 		headerKeyTable: &headersTable,
@@ -404,11 +844,30 @@ func New(
 		headerRows:     headerRows,
 		payload:        payload,
 		err:            err,
-		metaInfo:       metaInfo,
 		clipWriter:     clipWriter,
 		notifierCmdbar: notifierCmdBar,
 		config:         ktx.Config,
 		state:          recordView,
 		border:         b,
+		headerDecoders: headerDecoders,
+		tree:           tree,
+		schemaSubjects: make(map[int][]sradmin.SubjectVersion),
+	}
+	for _, option := range options {
+		option(m)
 	}
+
+	tombstoneMsgFunc := func(record *kadmin.ConsumerRecord) string {
+		return "Publish a tombstone for key " + record.Key + lipgloss.NewStyle().
+			Foreground(lipgloss.Color(styles.ColorIndigo)).
+			Bold(true).
+			Render(" to delete it from the compacted topic?")
+	}
+	tombstoneFunc := func(record *kadmin.ConsumerRecord) tea.Cmd {
+		m.tombstoneCmdBar.Hide()
+		return m.publishTombstone(record)
+	}
+	m.tombstoneCmdBar = cmdbar.NewDeleteCmdBar(tombstoneMsgFunc, tombstoneFunc, nil)
+
+	return m
 }
@@ -7,13 +7,30 @@ import (
 	"github.com/stretchr/testify/assert"
 	"ktea/config"
 	"ktea/kadmin"
+	"ktea/kontext"
 	"ktea/serdes"
+	"ktea/sradmin"
 	"ktea/tests"
 	"ktea/ui/clipper"
 	"ktea/ui/components/statusbar"
 	"testing"
+	"time"
 )
 
+type mockPublisher struct {
+	published *kadmin.ProducerRecord
+}
+
+func (m *mockPublisher) PublishRecord(p *kadmin.ProducerRecord) kadmin.PublicationStartedMsg {
+	m.published = p
+	published := make(chan bool, 1)
+	published <- true
+	return kadmin.PublicationStartedMsg{
+		Err:       make(chan error),
+		Published: published,
+	}
+}
+
 func TestRecordDetailsPage(t *testing.T) {
 	t.Run("c-h or arrows toggles focus between content and headers", func(t *testing.T) {
 		m := New(&kadmin.ConsumerRecord{
@@ -194,6 +211,47 @@ func TestRecordDetailsPage(t *testing.T) {
 		})
 	})
 
+	t.Run("Display key schema next to value schema", func(t *testing.T) {
+		m := New(&kadmin.ConsumerRecord{
+			Key:       `{"id":7}`,
+			KeySchema: `{"type": "record", "name": "OrderKey", "fields": [{"name": "id", "type": "int"}]}`,
+			Payload: serdes.DesData{
+				Value:  `{"name":"John"}`,
+				Schema: `{"type": "record", "name": "Order", "fields": [{"name": "name", "type": "string"}]}`,
+			},
+		},
+			"",
+			clipper.NewMock(),
+			tests.NewKontext(),
+		)
+
+		m.View(tests.NewKontext(), tests.TestRenderer)
+		m.Update(tests.Key(tea.KeyTab))
+
+		render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+		assert.Regexp(t, `Key Schema\s+Value Schema`, render)
+		assert.Regexp(t, `"name": "OrderKey",\s+"name": "Order",`, render)
+	})
+
+	t.Run("Display schema violations as a warning", func(t *testing.T) {
+		m := New(&kadmin.ConsumerRecord{
+			Payload: serdes.DesData{
+				Value:      `{"age":-1}`,
+				Violations: []string{"/: missing properties: 'name'", "/age: must be >= 0 but found -1"},
+			},
+		},
+			"",
+			clipper.NewMock(),
+			tests.NewKontext(),
+		)
+
+		render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+		assert.Contains(t, render, "2 schema violations: /: missing properties: 'name', /age: must be >= 0 but found -1")
+		assert.Contains(t, render, `"age": -1`)
+	})
+
 	t.Run("Display record without headers", func(t *testing.T) {
 		m := New(&kadmin.ConsumerRecord{
 			Key:       "",
@@ -212,6 +270,171 @@ func TestRecordDetailsPage(t *testing.T) {
 		assert.Contains(t, render, "No headers present")
 	})
 
+	t.Run("Title the record regardless of its format", func(t *testing.T) {
+		m := New(&kadmin.ConsumerRecord{
+			Key:     "1",
+			Payload: serdes.DesData{Value: `{"id":1}`},
+		},
+			"",
+			clipper.NewMock(),
+			tests.NewKontext(),
+		)
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+
+		assert.Contains(t, render, "Record")
+		assert.NotContains(t, render, "AVRO")
+	})
+
+	t.Run("Display tombstone", func(t *testing.T) {
+		m := New(&kadmin.ConsumerRecord{
+			Key:       "k1",
+			RawKey:    []byte("k1"),
+			Tombstone: true,
+		},
+			"",
+			clipper.NewMock(),
+			tests.NewKontext(),
+		)
+
+		render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+		assert.Contains(t, render, "∅ Tombstone, the value is null")
+	})
+
+	t.Run("Publish tombstone", func(t *testing.T) {
+		ktx := tests.NewKontext(tests.WithConfig(&config.Config{
+			Clusters: []config.Cluster{{Name: "prd", Active: true}},
+		}))
+		newPage := func(publisher kadmin.Publisher, rawKey []byte) *Model {
+			return New(&kadmin.ConsumerRecord{
+				Key:       string(rawKey),
+				RawKey:    rawKey,
+				Payload:   serdes.DesData{Value: `{"name":"John"}`},
+				Partition: 2,
+				Offset:    123,
+			},
+				"topic1",
+				clipper.NewMock(),
+				ktx,
+				WithPublisher(publisher),
+			)
+		}
+
+		t.Run("publish a null value for the key to the partition of the record", func(t *testing.T) {
+			publisher := &mockPublisher{}
+			m := newPage(publisher, []byte("k1"))
+			m.View(tests.NewKontext(), tests.TestRenderer)
+
+			m.Update(tests.Key(tea.KeyF2))
+			render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+			assert.Contains(t, render, "Publish a tombstone for key k1")
+
+			m.Update(tests.Key('d'))
+			cmd := m.Update(tests.Key(tea.KeyEnter))
+			for _, msg := range tests.ExecuteBatchCmd(cmd) {
+				for _, msg := range tests.ExecuteBatchCmd(m.Update(msg)) {
+					m.Update(msg)
+				}
+			}
+
+			partition := 2
+			assert.Equal(t, &kadmin.ProducerRecord{
+				Key:       "k1",
+				Value:     nil,
+				Topic:     "topic1",
+				Partition: &partition,
+			}, publisher.published)
+			assert.Contains(t, ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer)), "Tombstone published for key k1")
+		})
+
+		t.Run("unavailable for records without a key", func(t *testing.T) {
+			publisher := &mockPublisher{}
+			m := newPage(publisher, nil)
+			m.View(tests.NewKontext(), tests.TestRenderer)
+
+			assert.NotContains(t, m.Shortcuts(), statusbar.Shortcut{Name: "Publish Tombstone", Keybinding: "F2"})
+
+			m.Update(tests.Key(tea.KeyF2))
+
+			assert.NotContains(t, ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer)), "Publish a tombstone")
+		})
+
+		t.Run("unavailable without a publisher", func(t *testing.T) {
+			m := newPage(nil, []byte("k1"))
+			m.View(tests.NewKontext(), tests.TestRenderer)
+
+			assert.NotContains(t, m.Shortcuts(), statusbar.Shortcut{Name: "Publish Tombstone", Keybinding: "F2"})
+		})
+	})
+
+	t.Run("Display metadata", func(t *testing.T) {
+		timestamp := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		newRecord := func() *kadmin.ConsumerRecord {
+			return &kadmin.ConsumerRecord{
+				Key:            "foobar",
+				RawKey:         []byte("foobar"),
+				RawValue:       make([]byte, 2048),
+				Payload:        serdes.DesData{Value: `{"name":"John"}`, SchemaId: 12},
+				Partition:      2,
+				Timestamp:      timestamp,
+				TimestampType:  kadmin.LogAppendTime,
+				BlockTimestamp: timestamp,
+				Headers: []kadmin.Header{
+					{Key: "h1", Value: kadmin.NewHeaderValue("v1")},
+					{Key: "h2", Value: kadmin.NewHeaderValue("value2")},
+				},
+			}
+		}
+
+		t.Run("timestamps, sizes and headers", func(t *testing.T) {
+			m := New(newRecord(), "topic1", clipper.NewMock(), tests.NewKontext())
+
+			metadata := m.metadata()
+
+			assert.Contains(t, metadata, "timestamp type: LogAppendTime")
+			assert.Contains(t, metadata, "block timestamp: Fri Mar  1 10:00:00 UTC 2024")
+			assert.Contains(t, metadata, "key size: 6 B")
+			assert.Contains(t, metadata, "value size: 2.0 kB")
+			assert.Contains(t, metadata, "headers: 2 (12 B)")
+			assert.Contains(t, metadata, "value schema: id 12")
+			assert.NotContains(t, metadata, "key schema")
+			assert.NotContains(t, metadata, "jvm partition")
+		})
+
+		t.Run("partition chosen by the JVM partitioner", func(t *testing.T) {
+			m := New(newRecord(), "topic1", clipper.NewMock(), tests.NewKontext(), WithPartitionCount(10))
+
+			assert.Contains(t, m.metadata(), "jvm partition: 6 (read from 2)")
+		})
+
+		t.Run("subjects of the schema", func(t *testing.T) {
+			lister := sradmin.NewMock()
+			lister.ListSchemaSubjectsFunc = func(schemaId int) tea.Msg {
+				return sradmin.SchemaSubjectsListedMsg{
+					SchemaId: schemaId,
+					Subjects: []sradmin.SubjectVersion{{Subject: "topic1-value", Version: 3}},
+				}
+			}
+			m := New(newRecord(), "topic1", clipper.NewMock(), tests.NewKontext(), WithSchemaSubjectsLister(lister))
+
+			for _, msg := range tests.ExecuteBatchCmd(m.LoadSchemaSubjects()) {
+				m.Update(msg)
+			}
+
+			assert.Contains(t, m.metadata(), "value schema: id 12 (topic1-value v3)")
+		})
+
+		t.Run("null value of a tombstone", func(t *testing.T) {
+			record := newRecord()
+			record.RawValue = nil
+			record.Tombstone = true
+			m := New(record, "topic1", clipper.NewMock(), tests.NewKontext())
+
+			assert.Contains(t, m.metadata(), "value size: null")
+		})
+	})
+
 	t.Run("Copy payload", func(t *testing.T) {
 		var clippedText string
 		clipMock := clipper.NewMock()
@@ -348,6 +571,88 @@ func TestRecordDetailsPage(t *testing.T) {
 		assert.Contains(t, render, "Header Value copied")
 	})
 
+	t.Run("Decode header values", func(t *testing.T) {
+		cfg := config.New(&config.InMemoryConfigIO{})
+		cfg.RegisterCluster(config.RegistrationDetails{
+			Name:       "prd",
+			Host:       "localhost:9092",
+			AuthMethod: config.NoneAuthMethod,
+		})
+		cfg.SaveHeaderDecoder("prd", "span-id", "hex")
+		newPage := func() *Model {
+			return New(&kadmin.ConsumerRecord{
+				Payload: serdes.DesData{Value: `{"name":"John"}`},
+				Headers: []kadmin.Header{
+					{Key: "retry-count", Value: kadmin.NewBinaryHeaderValue([]byte{0, 0, 0, 3})},
+					{Key: "span-id", Value: kadmin.NewBinaryHeaderValue([]byte{0x00, 0xf0, 0x67, 0xaa})},
+				},
+			},
+				"",
+				clipper.NewMock(),
+				tests.NewKontext(tests.WithConfig(cfg)),
+			)
+		}
+
+		t.Run("int32 header is decoded as a number", func(t *testing.T) {
+			m := newPage()
+
+			render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+			assert.Contains(t, render, "Header Value (auto)")
+			assert.Contains(t, render, "│3 ")
+		})
+
+		t.Run("use decoder configured for the header key", func(t *testing.T) {
+			m := newPage()
+			m.View(tests.NewKontext(), tests.TestRenderer)
+
+			m.Update(tests.Key(tea.KeyCtrlH))
+			m.Update(tests.Key(tea.KeyDown))
+			render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+			assert.Contains(t, render, "Header Value (hex)")
+			assert.Contains(t, render, "00f067aa")
+		})
+
+		t.Run("cycle decoder and remember it", func(t *testing.T) {
+			m := newPage()
+			m.View(tests.NewKontext(), tests.TestRenderer)
+
+			m.Update(tests.Key(tea.KeyCtrlH))
+			// string
+			m.Update(tests.Key('t'))
+			// int32
+			m.Update(tests.Key('t'))
+			render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+			assert.Contains(t, render, "Header Value (int32)")
+			assert.Equal(t, "int32", cfg.ActiveCluster().HeaderDecoder("retry-count"))
+
+			// int64
+			m.Update(tests.Key('t'))
+			render = ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+			assert.Contains(t, render, "Unable to decode")
+		})
+
+		t.Run("toggle raw header value", func(t *testing.T) {
+			m := newPage()
+			m.View(tests.NewKontext(), tests.TestRenderer)
+
+			m.Update(tests.Key(tea.KeyCtrlH))
+			m.Update(tests.Key('r'))
+			render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+			assert.Contains(t, render, "Header Value (raw)")
+			assert.Contains(t, render, "00000000  00 00 00 03")
+
+			m.Update(tests.Key('r'))
+			render = ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+			assert.NotContains(t, render, "Header Value (raw)")
+		})
+	})
+
 	t.Run("Copy header value failed", func(t *testing.T) {
 		clipMock := clipper.NewMock()
 		clipMock.WriteFunc = func(text string) error {
@@ -452,5 +757,122 @@ func TestRecordDetailsPage(t *testing.T) {
 			// do not crash but ignore the update
 			m.Update(tests.Key(tea.KeyF2))
 		})
+
+		t.Run("hex dump remains available", func(t *testing.T) {
+			m.record.RawValue = []byte{0xde, 0xad, 0xbe, 0xef}
+
+			m.Update(tests.Key('x'))
+			render := m.View(tests.NewKontext(), tests.TestRenderer)
+
+			assert.NotContains(t, render, "Unable to render payload")
+			assert.Contains(t, render, "de ad be ef")
+		})
+	})
+
+	t.Run("Toggle hex dump", func(t *testing.T) {
+		m := New(&kadmin.ConsumerRecord{
+			Key:      "key-1",
+			Payload:  serdes.DesData{Value: `{"name":"John"}`, SchemaId: 7},
+			RawKey:   []byte("key-1"),
+			RawValue: []byte{0x00, 0x00, 0x00, 0x00, 0x07, 0x08, 'J', 'o', 'h', 'n'},
+			Headers:  []kadmin.Header{},
+		},
+			"",
+			clipper.NewMock(),
+			tests.NewKontext(),
+		)
+		// wide enough to render the ASCII column of the dump
+		ktx := tests.NewKontext(func(ktx *kontext.ProgramKtx) {
+			ktx.WindowWidth = 150
+		})
+		// init ui
+		m.View(ktx, tests.TestRenderer)
+
+		m.Update(tests.Key('x'))
+		render := ansi.Strip(m.View(ktx, tests.TestRenderer))
+
+		assert.Contains(t, render, "Key (5 bytes)")
+		assert.Contains(t, render, "00000000  6b 65 79 2d 31")
+		assert.Contains(t, render, "|key-1|")
+		assert.Contains(t, render, "Value (10 bytes), magic byte 0x00, schema id 7")
+		assert.Contains(t, render, "00000000  00 00 00 00 07 08 4a 6f  68 6e")
+		assert.Contains(t, render, "|......John|")
+		assert.NotContains(t, render, `"name"`)
+
+		t.Run("toggle back to the deserialized payload", func(t *testing.T) {
+			m.Update(tests.Key('x'))
+			render := ansi.Strip(m.View(ktx, tests.TestRenderer))
+
+			assert.Contains(t, render, `"name"`)
+			assert.NotContains(t, render, "|......John|")
+		})
+	})
+
+	t.Run("Json tree view", func(t *testing.T) {
+		var clippedText string
+		clipMock := clipper.NewMock()
+		clipMock.WriteFunc = func(text string) error {
+			clippedText = text
+			return nil
+		}
+		m := New(&kadmin.ConsumerRecord{
+			Key:     "order-1",
+			Payload: serdes.DesData{Value: `{"order":{"status":"SHIPPED","items":[{"sku":"a1"},{"sku":"b2"}]}}`},
+			Headers: []kadmin.Header{},
+		},
+			"",
+			clipMock,
+			tests.NewKontext(),
+		)
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		m.Update(tests.Key('v'))
+		render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+		assert.Contains(t, render, "▼ items: [2 items]")
+
+		t.Run("collapse the focused node", func(t *testing.T) {
+			// focus the items
+			m.Update(tests.Key(tea.KeyDown))
+			m.Update(tests.Key(tea.KeyDown))
+			m.Update(tests.Key(tea.KeyDown))
+			m.Update(tests.Key(tea.KeyEnter))
+			render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+			assert.Contains(t, render, "▶ items: [2 items]")
+			assert.NotContains(t, render, "sku")
+		})
+
+		t.Run("search expands and focuses the match", func(t *testing.T) {
+			m.Update(tests.Key('/'))
+			tests.UpdateKeys(m, "b2")
+			m.Update(tests.Key(tea.KeyEnter))
+			m.View(tests.NewKontext(), tests.TestRenderer)
+
+			m.Update(tests.Key('p'))
+			assert.Equal(t, "$.order.items[1].sku", clippedText)
+
+			m.Update(tests.Key('c'))
+			assert.Equal(t, "b2", clippedText)
+		})
+
+		t.Run("report missing matches", func(t *testing.T) {
+			m.Update(tests.Key('/'))
+			tests.UpdateKeys(m, "DELIVERED")
+			cmd := m.Update(tests.Key(tea.KeyEnter))
+			for _, msg := range tests.ExecuteBatchCmd(cmd) {
+				m.Update(msg)
+			}
+
+			assert.Contains(t, ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer)), "No match")
+		})
+
+		t.Run("toggle back to the formatted payload", func(t *testing.T) {
+			m.Update(tests.Key('v'))
+			render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+			assert.Contains(t, render, `"status": "SHIPPED"`)
+			assert.NotContains(t, render, "▼")
+		})
 	})
 }
@@ -0,0 +1,139 @@
+package record_diff_page
+
+import (
+	"ktea/kadmin"
+	"ktea/ui"
+	"strconv"
+	"time"
+)
+
+type change int
+
+const (
+	unchanged change = iota
+	added
+	removed
+	changed
+)
+
+// difference compares a single field of the left and the right record.
+type difference struct {
+	path   string
+	change change
+	left   string
+	right  string
+}
+
+// diffMetadata compares the metadata of the records.
+func diffMetadata(left, right kadmin.ConsumerRecord) []difference {
+	return []difference{
+		compare("key", left.Key, right.Key),
+		compare("partition", strconv.FormatInt(left.Partition, 10), strconv.FormatInt(right.Partition, 10)),
+		compare("offset", strconv.FormatInt(left.Offset, 10), strconv.FormatInt(right.Offset, 10)),
+		compare("timestamp", left.Timestamp.Format(time.RFC3339Nano), right.Timestamp.Format(time.RFC3339Nano)),
+		compare("schema id", schemaId(left), schemaId(right)),
+	}
+}
+
+func schemaId(record kadmin.ConsumerRecord) string {
+	if record.Payload.SchemaId == 0 {
+		return ""
+	}
+	return strconv.Itoa(record.Payload.SchemaId)
+}
+
+// diffHeaders compares the headers by key, in the order they appear in the left and then the right record.
+func diffHeaders(left, right []kadmin.Header) []difference {
+	var (
+		keys        []string
+		leftValues  = make(map[string]string)
+		rightValues = make(map[string]string)
+	)
+	for _, header := range left {
+		if _, ok := leftValues[header.Key]; !ok {
+			keys = append(keys, header.Key)
+		}
+		leftValues[header.Key] = header.Value.String()
+	}
+	for _, header := range right {
+		if _, ok := leftValues[header.Key]; !ok {
+			if _, ok := rightValues[header.Key]; !ok {
+				keys = append(keys, header.Key)
+			}
+		}
+		rightValues[header.Key] = header.Value.String()
+	}
+
+	var differences []difference
+	for _, key := range keys {
+		leftValue, inLeft := leftValues[key]
+		rightValue, inRight := rightValues[key]
+		switch {
+		case !inLeft:
+			differences = append(differences, difference{key, added, "", rightValue})
+		case !inRight:
+			differences = append(differences, difference{key, removed, leftValue, ""})
+		default:
+			differences = append(differences, compare(key, leftValue, rightValue))
+		}
+	}
+	return differences
+}
+
+// diffValues compares the values structurally when both are JSON, and as text otherwise.
+func diffValues(left, right string) []difference {
+	leftJson, leftErr := ui.DecodeJson(left)
+	rightJson, rightErr := ui.DecodeJson(right)
+	if leftErr != nil || rightErr != nil {
+		return []difference{compare("$", left, right)}
+	}
+
+	var differences []difference
+	diffJson("$", leftJson, rightJson, &differences)
+	return differences
+}
+
+func diffJson(path string, left, right any, differences *[]difference) {
+	switch leftValue := left.(type) {
+	case ui.JsonObject:
+		if rightValue, ok := right.(ui.JsonObject); ok {
+			for _, key := range leftValue.Keys {
+				childPath := ui.JsonFieldPath(path, key)
+				if rightChild, ok := rightValue.Values[key]; ok {
+					diffJson(childPath, leftValue.Values[key], rightChild, differences)
+				} else {
+					*differences = append(*differences, difference{childPath, removed, ui.EncodeJson(leftValue.Values[key]), ""})
+				}
+			}
+			for _, key := range rightValue.Keys {
+				if _, ok := leftValue.Values[key]; !ok {
+					*differences = append(*differences, difference{ui.JsonFieldPath(path, key), added, "", ui.EncodeJson(rightValue.Values[key])})
+				}
+			}
+			return
+		}
+	case []any:
+		if rightValue, ok := right.([]any); ok {
+			for i := 0; i < max(len(leftValue), len(rightValue)); i++ {
+				childPath := ui.JsonIndexPath(path, i)
+				switch {
+				case i >= len(rightValue):
+					*differences = append(*differences, difference{childPath, removed, ui.EncodeJson(leftValue[i]), ""})
+				case i >= len(leftValue):
+					*differences = append(*differences, difference{childPath, added, "", ui.EncodeJson(rightValue[i])})
+				default:
+					diffJson(childPath, leftValue[i], rightValue[i], differences)
+				}
+			}
+			return
+		}
+	}
+	*differences = append(*differences, compare(path, ui.EncodeJson(left), ui.EncodeJson(right)))
+}
+
+func compare(path, left, right string) difference {
+	if left == right {
+		return difference{path, unchanged, left, right}
+	}
+	return difference{path, changed, left, right}
+}
@@ -0,0 +1,60 @@
+package record_diff_page
+
+import (
+	"github.com/stretchr/testify/assert"
+	"ktea/kadmin"
+	"testing"
+)
+
+func TestDiffValues(t *testing.T) {
+	t.Run("Compare JSON structurally", func(t *testing.T) {
+		differences := diffValues(
+			`{"id":1,"status":"FAILED","items":[{"sku":"a1"},{"sku":"b2"}],"reason":"timeout"}`,
+			`{"status":"SHIPPED","id":1,"items":[{"sku":"a1"}],"carrier":"ups"}`,
+		)
+
+		assert.Equal(t, []difference{
+			{"$.id", unchanged, "1", "1"},
+			{"$.status", changed, `"FAILED"`, `"SHIPPED"`},
+			{"$.items[0].sku", unchanged, `"a1"`, `"a1"`},
+			{"$.items[1]", removed, `{"sku":"b2"}`, ""},
+			{"$.reason", removed, `"timeout"`, ""},
+			{"$.carrier", added, "", `"ups"`},
+		}, differences)
+	})
+
+	t.Run("Compare values of different types as a whole", func(t *testing.T) {
+		assert.Equal(t, []difference{
+			{"$.items", changed, `["a1"]`, `{"sku":"a1"}`},
+		}, diffValues(`{"items":["a1"]}`, `{"items":{"sku":"a1"}}`))
+	})
+
+	t.Run("Render paths and values as JSON", func(t *testing.T) {
+		assert.Equal(t, []difference{
+			{`$["a\u0001b"]`, changed, `"<b>"`, `"\t&"`},
+		}, diffValues(`{"a\u0001b":"<b>"}`, `{"a\u0001b":"\t&"}`))
+	})
+
+	t.Run("Compare values that are no JSON as text", func(t *testing.T) {
+		assert.Equal(t, []difference{{"$", changed, "plain", `{"a":1}`}}, diffValues("plain", `{"a":1}`))
+	})
+}
+
+func TestDiffHeaders(t *testing.T) {
+	differences := diffHeaders(
+		[]kadmin.Header{
+			{Key: "trace", Value: kadmin.NewHeaderValue("t-1")},
+			{Key: "retry", Value: kadmin.NewHeaderValue("0")},
+		},
+		[]kadmin.Header{
+			{Key: "retry", Value: kadmin.NewHeaderValue("3")},
+			{Key: "error", Value: kadmin.NewHeaderValue("timeout")},
+		},
+	)
+
+	assert.Equal(t, []difference{
+		{"trace", removed, "t-1", ""},
+		{"retry", changed, "0", "3"},
+		{"error", added, "", "timeout"},
+	}, differences)
+}
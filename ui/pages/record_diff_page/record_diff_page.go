@@ -0,0 +1,148 @@
+package record_diff_page
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"ktea/kadmin"
+	"ktea/kontext"
+	"ktea/styles"
+	"ktea/ui"
+	"ktea/ui/components/statusbar"
+	"ktea/ui/pages/nav"
+	"strings"
+)
+
+// Model compares two records side by side.
+type Model struct {
+	left      kadmin.ConsumerRecord
+	right     kadmin.ConsumerRecord
+	topicName string
+	metadata  []difference
+	headers   []difference
+	values    []difference
+	// onlyChanges hides the fields that are equal in both records
+	onlyChanges bool
+	vp          *viewport.Model
+}
+
+func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
+	width := ktx.WindowWidth - 2
+	height := ktx.AvailableHeight - 2
+
+	if m.vp == nil {
+		vp := viewport.New(width, height)
+		m.vp = &vp
+	}
+	m.vp.Width = width
+	m.vp.Height = height
+	m.vp.SetContent(m.content(width))
+
+	return renderer.Render(styles.Borderize(m.vp.View(), true, nil))
+}
+
+func (m *Model) content(width int) string {
+	// the marker takes 2 columns, the path a fifth of the remaining width and the values the rest
+	pathWidth := (width - 2) / 5
+	valueWidth := (width - 2 - pathWidth) / 2
+
+	cell := func(text string, width int) string {
+		return lipgloss.NewStyle().
+			Width(width).
+			MaxWidth(width).
+			Render(ansi.Truncate(strings.ReplaceAll(text, "\n", " "), width-1, "…"))
+	}
+
+	bold := lipgloss.NewStyle().Bold(true)
+	lines := []string{
+		"  " + cell("", pathWidth) +
+			bold.Render(cell(describe(m.left), valueWidth)) +
+			bold.Render(cell(describe(m.right), valueWidth)),
+	}
+
+	section := func(title string, differences []difference) {
+		lines = append(lines, "", bold.Render(title))
+		var shown int
+		for _, d := range differences {
+			if m.onlyChanges && d.change == unchanged {
+				continue
+			}
+			shown++
+			marker, style := " ", lipgloss.NewStyle()
+			switch d.change {
+			case added:
+				marker, style = "+", lipgloss.NewStyle().Foreground(lipgloss.Color(styles.ColorGreen))
+			case removed:
+				marker, style = "-", lipgloss.NewStyle().Foreground(lipgloss.Color(styles.ColorRed))
+			case changed:
+				marker, style = "~", lipgloss.NewStyle().Foreground(lipgloss.Color(styles.ColorYellow))
+			}
+			lines = append(lines, style.Render(marker+" "+cell(d.path, pathWidth)+cell(d.left, valueWidth)+cell(d.right, valueWidth)))
+		}
+		if shown == 0 {
+			lines = append(lines, lipgloss.NewStyle().
+				Foreground(lipgloss.Color(styles.ColorGrey)).
+				Render("  No differences"))
+		}
+	}
+
+	section("Metadata", m.metadata)
+	section("Headers", m.headers)
+	section("Value", m.values)
+
+	return strings.Join(lines, "\n")
+}
+
+func describe(record kadmin.ConsumerRecord) string {
+	return fmt.Sprintf("Partition %d, Offset %d", record.Partition, record.Offset)
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return ui.PublishMsg(nav.LoadCachedConsumptionPageMsg{})
+		case "a":
+			m.onlyChanges = !m.onlyChanges
+		default:
+			if m.vp != nil {
+				vp, cmd := m.vp.Update(msg)
+				m.vp = &vp
+				return cmd
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Model) Shortcuts() []statusbar.Shortcut {
+	toggle := statusbar.Shortcut{Name: "Show All Fields", Keybinding: "a"}
+	if !m.onlyChanges {
+		toggle = statusbar.Shortcut{Name: "Show Only Differences", Keybinding: "a"}
+	}
+	return []statusbar.Shortcut{
+		toggle,
+		{Name: "Scroll", Keybinding: "j/k"},
+		{Name: "Go Back", Keybinding: "esc"},
+	}
+}
+
+func (m *Model) Title() string {
+	return "Topics / " + m.topicName + " / Records / Diff"
+}
+
+// New compares the left record, the one consumed first, to the right record.
+func New(left kadmin.ConsumerRecord, right kadmin.ConsumerRecord, topicName string) *Model {
+	return &Model{
+		left:        left,
+		right:       right,
+		topicName:   topicName,
+		metadata:    diffMetadata(left, right),
+		headers:     diffHeaders(left.Headers, right.Headers),
+		values:      diffValues(left.Payload.Value, right.Payload.Value),
+		onlyChanges: true,
+	}
+}
@@ -0,0 +1,55 @@
+package record_diff_page
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"ktea/kadmin"
+	"ktea/serdes"
+	"ktea/tests"
+	"ktea/ui/pages/nav"
+	"testing"
+)
+
+func TestRecordDiffPage(t *testing.T) {
+	left := kadmin.ConsumerRecord{
+		Key:     "order-1",
+		Offset:  10,
+		Payload: serdes.DesData{Value: `{"id":1,"status":"FAILED"}`},
+	}
+	right := kadmin.ConsumerRecord{
+		Key:     "order-1",
+		Offset:  12,
+		Payload: serdes.DesData{Value: `{"id":1,"status":"SHIPPED"}`},
+	}
+
+	t.Run("Show only the differences", func(t *testing.T) {
+		m := New(left, right, "orders")
+
+		render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+		assert.Contains(t, render, "Partition 0, Offset 10")
+		assert.Contains(t, render, "Partition 0, Offset 12")
+		assert.Regexp(t, `~ offset\s+10\s+12`, render)
+		assert.Regexp(t, `~ \$\.status\s+"FAILED"\s+"SHIPPED"`, render)
+		assert.NotContains(t, render, "$.id")
+		assert.Contains(t, render, "No differences")
+	})
+
+	t.Run("Show all fields", func(t *testing.T) {
+		m := New(left, right, "orders")
+
+		m.Update(tests.Key('a'))
+		render := ansi.Strip(m.View(tests.NewKontext(), tests.TestRenderer))
+
+		assert.Regexp(t, `\$\.id\s+1\s+1`, render)
+	})
+
+	t.Run("Go back to the records", func(t *testing.T) {
+		m := New(left, right, "orders")
+
+		cmd := m.Update(tests.Key(tea.KeyEsc))
+
+		assert.Equal(t, nav.LoadCachedConsumptionPageMsg{}, cmd())
+	})
+}
@@ -0,0 +1,249 @@
+package replay_page
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"ktea/config"
+	"ktea/kadmin"
+	"ktea/kontext"
+	"ktea/styles"
+	"ktea/ui"
+	"ktea/ui/components/cmdbar"
+	"ktea/ui/components/notifier"
+	"ktea/ui/components/statusbar"
+	"ktea/ui/pages/nav"
+)
+
+type state int
+
+const (
+	none      state = 0
+	replaying state = 1
+)
+
+type source int
+
+const (
+	markedRecordsSource source = 0
+	loadedRecordsSource source = 1
+	topicSource         source = 2
+)
+
+type Model struct {
+	state         state
+	form          *huh.Form
+	formValues    *formValues
+	replayer      kadmin.RecordReplayer
+	records       []kadmin.ConsumerRecord
+	marked        []kadmin.ConsumerRecord
+	readDetails   kadmin.ReadDetails
+	clusters      []config.Cluster
+	activeCluster string
+	notifier      *notifier.Model
+}
+
+type formValues struct {
+	source             source
+	cluster            string
+	topic              string
+	preservePartitions bool
+	provenanceHeaders  bool
+}
+
+func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
+	notifierView := m.notifier.View(ktx, renderer)
+	if notifierView != "" {
+		notifierView = styles.CmdBarWithWidth(ktx.WindowWidth - cmdbar.BorderedPadding).Render(notifierView)
+	}
+
+	if m.form == nil {
+		m.form = m.newForm(ktx)
+	}
+
+	return ui.JoinVertical(lipgloss.Top,
+		notifierView,
+		renderer.RenderWithStyle(m.form.View(), styles.Form),
+	)
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case spinner.TickMsg, notifier.HideNotificationMsg:
+		return m.notifier.Update(msg)
+	case kadmin.ReplayStartedMsg:
+		return msg.AwaitCompletion
+	case kadmin.RecordsReplayedMsg:
+		m.state = none
+		m.form = nil
+		m.notifier.ShowSuccessMsg(fmt.Sprintf("%d records replayed to %s", msg.Count, msg.Topic))
+		return m.notifier.AutoHideCmd("")
+	case kadmin.RecordsReplayErrMsg:
+		m.state = none
+		m.form = nil
+		return m.notifier.ShowErrorMsg("Replay failed", msg.Err)
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyEsc {
+			if m.state == replaying {
+				return nil
+			}
+			return ui.PublishMsg(nav.LoadCachedConsumptionPageMsg{})
+		}
+	}
+
+	if m.form == nil || m.state == replaying {
+		return nil
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+
+	if m.form.State == huh.StateCompleted {
+		m.state = replaying
+		m.form.State = huh.StateNormal
+		return tea.Batch(
+			m.notifier.SpinWithLoadingMsg("Replaying records"),
+			m.replay(),
+		)
+	}
+
+	return cmd
+}
+
+func (m *Model) replay() tea.Cmd {
+	details := kadmin.ReplayDetails{
+		SourceTopic:          m.readDetails.TopicName,
+		TargetTopic:          m.formValues.topic,
+		PreservePartitions:   m.formValues.preservePartitions,
+		AddProvenanceHeaders: m.formValues.provenanceHeaders,
+	}
+	switch m.formValues.source {
+	case markedRecordsSource:
+		details.Records = m.marked
+	case loadedRecordsSource:
+		details.Records = m.records
+	case topicSource:
+		details.ReadDetails = &m.readDetails
+	}
+	if m.formValues.cluster != m.activeCluster {
+		for i := range m.clusters {
+			if m.clusters[i].Name == m.formValues.cluster {
+				details.TargetCluster = &m.clusters[i]
+			}
+		}
+	}
+	return func() tea.Msg {
+		return m.replayer.ReplayRecords(context.Background(), details)
+	}
+}
+
+func (m *Model) newForm(ktx *kontext.ProgramKtx) *huh.Form {
+	var sourceOptions []huh.Option[source]
+	if len(m.marked) > 0 {
+		sourceOptions = append(sourceOptions,
+			huh.NewOption(fmt.Sprintf("Marked records (%d)", len(m.marked)), markedRecordsSource))
+	}
+	sourceOptions = append(sourceOptions,
+		huh.NewOption(fmt.Sprintf("Loaded records (%d)", len(m.records)), loadedRecordsSource))
+	// a live consumption never ends
+	if !m.readDetails.Unbounded() {
+		sourceOptions = append(sourceOptions,
+			huh.NewOption("Read from topic using the consumption details", topicSource))
+	}
+
+	var clusterOptions []huh.Option[string]
+	for _, cluster := range m.clusters {
+		clusterOptions = append(clusterOptions, huh.NewOption(cluster.Name, cluster.Name))
+	}
+
+	fields := []huh.Field{
+		huh.NewSelect[source]().
+			Value(&m.formValues.source).
+			Title("Records").
+			Options(sourceOptions...),
+	}
+	if len(clusterOptions) > 1 {
+		fields = append(fields, huh.NewSelect[string]().
+			Value(&m.formValues.cluster).
+			Title("Target Cluster").
+			Options(clusterOptions...))
+	}
+	fields = append(fields,
+		huh.NewInput().
+			Value(&m.formValues.topic).
+			Title("Target Topic").
+			Validate(func(topic string) error {
+				if topic == "" {
+					return errors.New("target topic cannot be empty")
+				}
+				return nil
+			}),
+		huh.NewSelect[bool]().
+			Value(&m.formValues.preservePartitions).
+			Title("Partitioning").
+			Options(
+				huh.NewOption("Use murmur2 key based partitioner", false),
+				huh.NewOption("Use the source partition", true)),
+		huh.NewSelect[bool]().
+			Value(&m.formValues.provenanceHeaders).
+			Title("Provenance Headers").
+			Description("Add the source topic, partition and offset as headers, replacing existing ktea.source.* headers.").
+			Options(
+				huh.NewOption("No", false),
+				huh.NewOption("Yes", true)),
+	)
+
+	form := huh.NewForm(
+		huh.NewGroup(fields...).WithWidth(ktx.WindowWidth / 2),
+	)
+	form.QuitAfterSubmit = false
+	form.Init()
+	return form
+}
+
+func (m *Model) Shortcuts() []statusbar.Shortcut {
+	return []statusbar.Shortcut{
+		{"Confirm", "enter"},
+		{"Next Field", "tab"},
+		{"Prev. Field", "s-tab"},
+		{"Go Back", "esc"},
+	}
+}
+
+func (m *Model) Title() string {
+	return "Topics / " + m.readDetails.TopicName + " / Records / Replay"
+}
+
+func New(
+	replayer kadmin.RecordReplayer,
+	records []kadmin.ConsumerRecord,
+	marked []kadmin.ConsumerRecord,
+	readDetails kadmin.ReadDetails,
+	ktx *kontext.ProgramKtx,
+) *Model {
+	m := &Model{
+		replayer:    replayer,
+		records:     records,
+		marked:      marked,
+		readDetails: readDetails,
+		notifier:    notifier.New(),
+		formValues:  &formValues{},
+	}
+	if len(marked) == 0 {
+		m.formValues.source = loadedRecordsSource
+	}
+	if ktx.Config != nil {
+		m.clusters = ktx.Config.Clusters
+		if active := ktx.Config.ActiveCluster(); active != nil {
+			m.activeCluster = active.Name
+		}
+	}
+	m.formValues.cluster = m.activeCluster
+	return m
+}
@@ -0,0 +1,153 @@
+package replay_page
+
+import (
+	"context"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"ktea/config"
+	"ktea/kadmin"
+	"ktea/tests"
+	"ktea/ui/pages/nav"
+	"testing"
+)
+
+type MockReplayer struct {
+	ReplayRecordsFunc func(rd kadmin.ReplayDetails) tea.Msg
+}
+
+func (m *MockReplayer) ReplayRecords(_ context.Context, rd kadmin.ReplayDetails) tea.Msg {
+	if m.ReplayRecordsFunc != nil {
+		return m.ReplayRecordsFunc(rd)
+	}
+	return nil
+}
+
+func TestReplayPage(t *testing.T) {
+	records := []kadmin.ConsumerRecord{{Key: "1"}, {Key: "2"}, {Key: "3"}}
+	marked := []kadmin.ConsumerRecord{{Key: "2"}}
+	readDetails := kadmin.ReadDetails{TopicName: "orders-dlq", Limit: 50}
+	ktx := tests.NewKontext(tests.WithConfig(&config.Config{
+		Clusters: []config.Cluster{
+			{Name: "prd", Active: true},
+			{Name: "dr"},
+		},
+	}))
+
+	t.Run("esc goes back to the consumption page", func(t *testing.T) {
+		m := New(&MockReplayer{}, records, marked, readDetails, ktx)
+
+		cmd := m.Update(tests.Key(tea.KeyEsc))
+
+		assert.IsType(t, nav.LoadCachedConsumptionPageMsg{}, cmd())
+	})
+
+	t.Run("replay marked records to the active cluster by default", func(t *testing.T) {
+		var details kadmin.ReplayDetails
+		m := New(&MockReplayer{
+			ReplayRecordsFunc: func(rd kadmin.ReplayDetails) tea.Msg {
+				details = rd
+				return nil
+			},
+		}, records, marked, readDetails, ktx)
+		m.View(ktx, tests.TestRenderer)
+
+		// records
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// cluster
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// topic
+		tests.UpdateKeys(m, "orders")
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// partitioning
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// provenance headers
+		m.Update(tests.Key(tea.KeyDown))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next group and submit
+		cmd = m.Update(cmd())
+		tests.ExecuteBatchCmd(m.Update(cmd()))
+
+		assert.Equal(t, kadmin.ReplayDetails{
+			Records:              marked,
+			SourceTopic:          "orders-dlq",
+			TargetTopic:          "orders",
+			AddProvenanceHeaders: true,
+		}, details)
+	})
+
+	t.Run("replay a topic range to another cluster", func(t *testing.T) {
+		var details kadmin.ReplayDetails
+		m := New(&MockReplayer{
+			ReplayRecordsFunc: func(rd kadmin.ReplayDetails) tea.Msg {
+				details = rd
+				return nil
+			},
+		}, records, nil, readDetails, ktx)
+		m.View(ktx, tests.TestRenderer)
+
+		// records
+		m.Update(tests.Key(tea.KeyDown))
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// cluster
+		m.Update(tests.Key(tea.KeyDown))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// topic
+		tests.UpdateKeys(m, "orders")
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// partitioning
+		m.Update(tests.Key(tea.KeyDown))
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// provenance headers
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		// next group and submit
+		cmd = m.Update(cmd())
+		tests.ExecuteBatchCmd(m.Update(cmd()))
+
+		assert.Nil(t, details.Records)
+		assert.Equal(t, &readDetails, details.ReadDetails)
+		assert.Equal(t, "dr", details.TargetCluster.Name)
+		assert.True(t, details.PreservePartitions)
+	})
+
+	t.Run("do not read a live consumption from the topic", func(t *testing.T) {
+		live := kadmin.ReadDetails{TopicName: "orders-dlq", StartPoint: kadmin.Live}
+		m := New(&MockReplayer{}, records, nil, live, ktx)
+
+		render := m.View(ktx, tests.TestRenderer)
+
+		assert.Contains(t, render, "Loaded records (3)")
+		assert.NotContains(t, render, "Read from topic")
+	})
+
+	t.Run("target topic is required", func(t *testing.T) {
+		m := New(&MockReplayer{}, records, nil, readDetails, ktx)
+		m.View(ktx, tests.TestRenderer)
+
+		// records
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// cluster
+		cmd = m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+		// topic
+		m.Update(tests.Key(tea.KeyEnter))
+
+		assert.Contains(t, m.View(ktx, tests.TestRenderer), "target topic cannot be empty")
+	})
+
+	t.Run("show replayed records", func(t *testing.T) {
+		m := New(&MockReplayer{}, records, nil, readDetails, ktx)
+
+		m.Update(kadmin.RecordsReplayedMsg{Count: 3, Topic: "orders"})
+
+		assert.Contains(t, m.View(ktx, tests.TestRenderer), "3 records replayed to orders")
+	})
+}
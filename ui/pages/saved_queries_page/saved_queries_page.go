@@ -0,0 +1,218 @@
+package saved_queries_page
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"ktea/config"
+	"ktea/kadmin"
+	"ktea/kontext"
+	"ktea/styles"
+	"ktea/ui"
+	"ktea/ui/components/cmdbar"
+	"ktea/ui/components/statusbar"
+	ktable "ktea/ui/components/table"
+	"ktea/ui/pages/nav"
+	"strconv"
+	"strings"
+)
+
+// Model lists the saved consumption queries of a topic.
+type Model struct {
+	topic        *kadmin.ListedTopic
+	config       *config.Config
+	queries      []config.ConsumptionQuery
+	table        *table.Model
+	deleteCmdBar *cmdbar.DeleteCmdBar[string]
+}
+
+type QueryDeletedMsg struct {
+	Name string
+}
+
+func (m *Model) View(ktx *kontext.ProgramKtx, renderer *ui.Renderer) string {
+	var cmdBarView string
+	if m.deleteCmdBar.IsFocussed() {
+		cmdBarView = m.deleteCmdBar.View(ktx, renderer)
+	}
+
+	if len(m.queries) == 0 {
+		return ui.JoinVertical(lipgloss.Top,
+			cmdBarView,
+			styles.CenterText(ktx.WindowWidth, ktx.AvailableHeight).
+				Render("No saved queries, save one with C-s in the consumption form"))
+	}
+
+	available := ktx.WindowWidth - 11
+	m.table.SetColumns([]table.Column{
+		{Title: "Name", Width: int(float64(available) * 0.3)},
+		{Title: "Start", Width: int(float64(available) * 0.15)},
+		{Title: "Partitions", Width: int(float64(available) * 0.15)},
+		{Title: "Limit", Width: int(float64(available) * 0.1)},
+		{Title: "Filters", Width: int(float64(available) * 0.3)},
+	})
+	m.table.SetHeight(ktx.AvailableHeight - 2)
+	m.table.SetRows(m.rows())
+
+	embeddedText := map[styles.BorderPosition]styles.EmbeddedTextFunc{
+		styles.TopMiddleBorder: func(active bool) string {
+			return lipgloss.NewStyle().
+				Foreground(lipgloss.Color(styles.ColorPink)).
+				Bold(true).
+				Render(fmt.Sprintf("Saved Queries: %d", len(m.queries)))
+		},
+	}
+	return ui.JoinVertical(lipgloss.Top,
+		cmdBarView,
+		renderer.Render(styles.Borderize(m.table.View(), !m.deleteCmdBar.IsFocussed(), embeddedText)),
+	)
+}
+
+func (m *Model) rows() []table.Row {
+	var rows []table.Row
+	for _, query := range m.queries {
+		partitions := "All"
+		if len(query.Partitions) > 0 {
+			var parts []string
+			for _, partition := range query.Partitions {
+				parts = append(parts, strconv.Itoa(partition))
+			}
+			partitions = strings.Join(parts, ",")
+		}
+
+		limit := strconv.Itoa(query.Limit)
+		if query.Limit == 0 {
+			limit = "-"
+		}
+
+		var filters []string
+		if query.KeyFilter != "" {
+			filters = append(filters, fmt.Sprintf("key %s %q", query.KeyFilter, query.KeyFilterTerm))
+		}
+		if query.ValueFilter != "" {
+			filters = append(filters, fmt.Sprintf("value %s %q", query.ValueFilter, query.ValueFilterTerm))
+		}
+
+		rows = append(rows, table.Row{
+			query.Name,
+			query.StartPoint,
+			partitions,
+			limit,
+			strings.Join(filters, ", "),
+		})
+	}
+	return rows
+}
+
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	if _, ok := msg.(QueryDeletedMsg); ok {
+		m.deleteCmdBar.Hide()
+		m.loadQueries()
+		return nil
+	}
+
+	if m.deleteCmdBar.IsFocussed() {
+		_, _, cmd := m.deleteCmdBar.Update(msg)
+		return cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return ui.PublishMsg(nav.LoadTopicsPageMsg{})
+		case "enter":
+			if query := m.selectedQuery(); query != nil {
+				return ui.PublishMsg(nav.LoadConsumptionPageMsg{
+					ReadDetails: kadmin.NewReadDetails(*query, m.topic),
+					Topic:       m.topic,
+				})
+			}
+		case "e":
+			if query := m.selectedQuery(); query != nil {
+				readDetails := kadmin.NewReadDetails(*query, m.topic)
+				return ui.PublishMsg(nav.LoadConsumptionFormPageMsg{
+					ReadDetails: &readDetails,
+					Topic:       m.topic,
+				})
+			}
+		case "f2":
+			if query := m.selectedQuery(); query != nil {
+				m.deleteCmdBar.Delete(query.Name)
+				_, _, cmd := m.deleteCmdBar.Update(msg)
+				return cmd
+			}
+		default:
+			t, cmd := m.table.Update(msg)
+			m.table = &t
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (m *Model) selectedQuery() *config.ConsumptionQuery {
+	if len(m.queries) == 0 {
+		return nil
+	}
+	return &m.queries[m.table.Cursor()]
+}
+
+func (m *Model) loadQueries() {
+	m.queries = nil
+	if m.config == nil {
+		return
+	}
+	if cluster := m.config.ActiveCluster(); cluster != nil {
+		m.queries = cluster.Queries(m.topic.Name)
+	}
+	if cursor := m.table.Cursor(); cursor >= len(m.queries) {
+		m.table.SetCursor(max(len(m.queries)-1, 0))
+	}
+}
+
+func (m *Model) Shortcuts() []statusbar.Shortcut {
+	if m.deleteCmdBar.IsFocussed() {
+		return m.deleteCmdBar.Shortcuts()
+	}
+	if len(m.queries) == 0 {
+		return []statusbar.Shortcut{{"Go Back", "esc"}}
+	}
+	return []statusbar.Shortcut{
+		{"Run", "enter"},
+		{"Edit", "e"},
+		{"Delete", "F2"},
+		{"Go Back", "esc"},
+	}
+}
+
+func (m *Model) Title() string {
+	return "Topics / " + m.topic.Name + " / Saved Queries"
+}
+
+func New(topic *kadmin.ListedTopic, ktx *kontext.ProgramKtx) *Model {
+	t := ktable.NewDefaultTable()
+	m := &Model{
+		topic:  topic,
+		config: ktx.Config,
+		table:  &t,
+	}
+
+	deleteMsgFunc := func(name string) string {
+		return name + lipgloss.NewStyle().
+			Foreground(lipgloss.Color(styles.ColorIndigo)).
+			Bold(true).
+			Render(" will be deleted")
+	}
+	deleteFunc := func(name string) tea.Cmd {
+		if cluster := m.config.ActiveCluster(); cluster != nil {
+			m.config.DeleteQuery(cluster.Name, topic.Name, name)
+		}
+		return ui.PublishMsg(QueryDeletedMsg{Name: name})
+	}
+	m.deleteCmdBar = cmdbar.NewDeleteCmdBar(deleteMsgFunc, deleteFunc, nil)
+
+	m.loadQueries()
+	return m
+}
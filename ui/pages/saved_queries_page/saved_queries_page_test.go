@@ -0,0 +1,106 @@
+package saved_queries_page
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"ktea/config"
+	"ktea/kadmin"
+	"ktea/tests"
+	"ktea/ui/pages/nav"
+	"testing"
+)
+
+func TestSavedQueriesPage(t *testing.T) {
+	topic := &kadmin.ListedTopic{Name: "orders", PartitionCount: 2}
+	newConfig := func() *config.Config {
+		cfg := config.New(&config.InMemoryConfigIO{})
+		cfg.RegisterCluster(config.RegistrationDetails{
+			Name:       "prd",
+			Host:       "localhost:9092",
+			AuthMethod: config.NoneAuthMethod,
+		})
+		cfg.SaveQuery("prd", config.ConsumptionQuery{
+			Name:       "first",
+			Topic:      "orders",
+			StartPoint: "beginning",
+			Limit:      50,
+		})
+		cfg.SaveQuery("prd", config.ConsumptionQuery{
+			Name:            "failed",
+			Topic:           "orders",
+			Partitions:      []int{1},
+			StartPoint:      "most-recent",
+			Limit:           500,
+			ValueFilter:     "contains",
+			ValueFilterTerm: "FAILED",
+		})
+		cfg.SaveQuery("prd", config.ConsumptionQuery{Name: "other", Topic: "payments"})
+		return cfg
+	}
+
+	t.Run("List the queries of the topic", func(t *testing.T) {
+		m := New(topic, tests.NewKontext(tests.WithConfig(newConfig())))
+
+		render := m.View(tests.NewKontext(), tests.TestRenderer)
+
+		assert.Contains(t, render, "Saved Queries: 2")
+		assert.Contains(t, render, "first")
+		assert.Contains(t, render, `value contains "FAILED"`)
+		assert.NotContains(t, render, "other")
+	})
+
+	t.Run("Run the selected query", func(t *testing.T) {
+		m := New(topic, tests.NewKontext(tests.WithConfig(newConfig())))
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		m.Update(tests.Key(tea.KeyDown))
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+
+		assert.Equal(t, nav.LoadConsumptionPageMsg{
+			ReadDetails: kadmin.ReadDetails{
+				TopicName:       "orders",
+				PartitionToRead: []int{1},
+				StartPoint:      kadmin.MostRecent,
+				Limit:           500,
+				Filter: &kadmin.Filter{
+					KeyFilter:       kadmin.NoFilterType,
+					ValueFilter:     kadmin.ContainsFilterType,
+					ValueSearchTerm: "FAILED",
+				},
+			},
+			Topic: topic,
+		}, cmd())
+	})
+
+	t.Run("Edit the selected query in the consumption form", func(t *testing.T) {
+		m := New(topic, tests.NewKontext(tests.WithConfig(newConfig())))
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		cmd := m.Update(tests.Key('e'))
+
+		msg := cmd().(nav.LoadConsumptionFormPageMsg)
+		assert.Equal(t, 50, msg.ReadDetails.Limit)
+		assert.Equal(t, []int{0, 1}, msg.ReadDetails.PartitionToRead)
+	})
+
+	t.Run("Delete the selected query", func(t *testing.T) {
+		cfg := newConfig()
+		m := New(topic, tests.NewKontext(tests.WithConfig(cfg)))
+		m.View(tests.NewKontext(), tests.TestRenderer)
+
+		m.Update(tests.Key(tea.KeyF2))
+		m.View(tests.NewKontext(), tests.TestRenderer)
+		m.Update(tests.Key('d'))
+		cmd := m.Update(tests.Key(tea.KeyEnter))
+		m.Update(cmd())
+
+		assert.Len(t, cfg.ActiveCluster().Queries("orders"), 1)
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "Saved Queries: 1")
+	})
+
+	t.Run("Explain how to save a query when there are none", func(t *testing.T) {
+		m := New(&kadmin.ListedTopic{Name: "empty"}, tests.NewKontext(tests.WithConfig(newConfig())))
+
+		assert.Contains(t, m.View(tests.NewKontext(), tests.TestRenderer), "No saved queries")
+	})
+}
@@ -111,11 +111,26 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			m.topics = nil
 			m.state = stateRefreshing
 			return m.lister.ListTopics
+		case "I":
+			if m.SelectedTopic() == nil {
+				return nil
+			}
+			return ui.PublishMsg(nav.LoadImportPageMsg{Topic: m.SelectedTopic()})
+		case "G":
+			if m.SelectedTopic() == nil {
+				return nil
+			}
+			return ui.PublishMsg(nav.LoadGeneratePageMsg{Topic: m.SelectedTopic()})
 		case "L":
 			if m.SelectedTopic() == nil {
 				return nil
 			}
 			return ui.PublishMsg(nav.LoadLiveConsumePageMsg{Topic: m.SelectedTopic()})
+		case "Q":
+			if m.SelectedTopic() == nil {
+				return nil
+			}
+			return ui.PublishMsg(nav.LoadSavedQueriesPageMsg{Topic: m.SelectedTopic()})
 		case "enter":
 			// only accept enter when the table is focussed
 			if !m.tcb.IsFocussed() {
@@ -273,8 +288,10 @@ func New(topicDeleter kadmin.TopicDeleter, lister kadmin.TopicLister) (*Model, t
 	m.shortcuts = []statusbar.Shortcut{
 		{"Consume", "enter"},
 		{"Live Consume", "S-l"},
+		{"Saved Queries", "S-q"},
 		{"Search", "/"},
 		{"Produce", "C-p"},
+		{"Import/Generate", "S-i/S-g"},
 		{"Create", "C-n"},
 		{"Configs", "C-o"},
 		{"Delete", "F2"},
@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/log"
 	"ktea/kadmin"
 	"ktea/kontext"
+	"ktea/sradmin"
 	"ktea/ui"
 	"ktea/ui/clipper"
 	"ktea/ui/components/statusbar"
@@ -14,18 +15,26 @@ import (
 	"ktea/ui/pages/consumption_form_page"
 	"ktea/ui/pages/consumption_page"
 	"ktea/ui/pages/create_topic_page"
+	"ktea/ui/pages/export_page"
+	"ktea/ui/pages/generate_page"
+	"ktea/ui/pages/import_page"
 	"ktea/ui/pages/nav"
 	"ktea/ui/pages/publish_page"
 	"ktea/ui/pages/record_details_page"
+	"ktea/ui/pages/record_diff_page"
+	"ktea/ui/pages/replay_page"
+	"ktea/ui/pages/saved_queries_page"
 	"ktea/ui/pages/topics_page"
 	"reflect"
 )
 
 type Model struct {
-	active            nav.Page
-	topicsPage        *topics_page.Model
-	statusbar         *statusbar.Model
-	ka                kadmin.Kadmin
+	active     nav.Page
+	topicsPage *topics_page.Model
+	statusbar  *statusbar.Model
+	ka         kadmin.Kadmin
+	// sra is nil when the cluster has no Schema Registry
+	sra               sradmin.SrAdmin
 	ktx               *kontext.ProgramKtx
 	consumptionPage   nav.Page
 	recordDetailsPage nav.Page
@@ -70,10 +79,32 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			m.active = consumption_form_page.New(msg.Topic, m.ktx)
 		}
 
+	case nav.LoadSavedQueriesPageMsg:
+		m.active = saved_queries_page.New(msg.Topic, m.ktx)
+
 	case nav.LoadRecordDetailPageMsg:
-		m.active = record_details_page.New(msg.Record, msg.TopicName, clipper.New(), m.ktx)
+		page := record_details_page.New(
+			msg.Record,
+			msg.TopicName,
+			clipper.New(),
+			m.ktx,
+			record_details_page.WithPublisher(m.ka),
+			record_details_page.WithPartitionCount(msg.PartitionCount),
+			record_details_page.WithSchemaSubjectsLister(m.sra),
+		)
+		cmds = append(cmds, page.LoadSchemaSubjects())
+		m.active = page
 		m.recordDetailsPage = m.active
 
+	case nav.LoadRecordDiffPageMsg:
+		m.active = record_diff_page.New(msg.Left, msg.Right, msg.TopicName)
+
+	case nav.LoadExportPageMsg:
+		m.active = export_page.New(m.ka, msg.Records, msg.ReadDetails)
+
+	case nav.LoadReplayPageMsg:
+		m.active = replay_page.New(m.ka, msg.Records, msg.Marked, msg.ReadDetails, m.ktx)
+
 	case nav.LoadTopicConfigPageMsg:
 		page, cmd := configs_page.New(m.ka, m.ka, m.topicsPage.SelectedTopicName())
 		cmds = append(cmds, cmd)
@@ -84,14 +115,28 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		m.active = create_topic_page.New(m.ka)
 
 	case nav.LoadPublishPageMsg:
-		m.active = publish_page.New(m.ka, msg.Topic)
+		var options []publish_page.Option
+		if m.sra != nil {
+			options = append(options, publish_page.WithSchemaRegistry(m.sra))
+		}
+		m.active = publish_page.New(m.ka, msg.Topic, options...)
+
+	case nav.LoadImportPageMsg:
+		m.active = import_page.New(m.ka, msg.Topic)
+
+	case nav.LoadGeneratePageMsg:
+		var options []generate_page.Option
+		if m.sra != nil {
+			options = append(options, generate_page.WithSchemaRegistry())
+		}
+		m.active = generate_page.New(m.ka, msg.Topic, options...)
 
 	case nav.LoadCachedConsumptionPageMsg:
 		m.active = m.consumptionPage
 
 	case nav.LoadConsumptionPageMsg:
 		var cmd tea.Cmd
-		m.active, cmd = consumption_page.New(m.ka, msg.ReadDetails, msg.Topic)
+		m.active, cmd = consumption_page.New(m.ka, msg.ReadDetails, msg.Topic, m.consumptionOptions(msg.Topic.Name)...)
 		m.consumptionPage = m.active
 		cmds = append(cmds, cmd)
 
@@ -101,10 +146,9 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			TopicName:       msg.Topic.Name,
 			PartitionToRead: msg.Topic.Partitions(),
 			StartPoint:      kadmin.Live,
-			Limit:           500,
 			Filter:          nil,
 		}
-		m.active, cmd = consumption_page.New(m.ka, readDetails, msg.Topic)
+		m.active, cmd = consumption_page.New(m.ka, readDetails, msg.Topic, m.consumptionOptions(msg.Topic.Name)...)
 		m.consumptionPage = m.active
 		cmds = append(cmds, cmd)
 
@@ -120,12 +164,34 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
-func New(ktx *kontext.ProgramKtx, ka kadmin.Kadmin) (*Model, tea.Cmd) {
+func (m *Model) consumptionOptions(topic string) []consumption_page.Option {
+	if m.ktx == nil || m.ktx.Config == nil {
+		return nil
+	}
+	options := []consumption_page.Option{consumption_page.WithBufferSize(m.ktx.Config.ConsumptionBufferSize)}
+	if cluster := m.ktx.Config.ActiveCluster(); cluster != nil {
+		cfg := m.ktx.Config
+		options = append(options,
+			consumption_page.WithColumns(cluster.Columns(topic)),
+			consumption_page.WithColumnsSaver(func(columns []string) {
+				cfg.SaveTopicColumns(cluster.Name, topic, columns)
+			}),
+			// looked up on use, as decoders are chosen while consuming in the record details
+			consumption_page.WithHeaderDecoders(func(key string) kadmin.HeaderDecoder {
+				return kadmin.HeaderDecoder(cfg.ActiveCluster().HeaderDecoder(key))
+			}),
+		)
+	}
+	return options
+}
+
+func New(ktx *kontext.ProgramKtx, ka kadmin.Kadmin, sra sradmin.SrAdmin) (*Model, tea.Cmd) {
 	var cmd tea.Cmd
 	listTopicView, cmd := topics_page.New(ka, ka)
 
 	model := &Model{}
 	model.ka = ka
+	model.sra = sra
 	model.ktx = ktx
 	model.active = listTopicView
 	model.topicsPage = listTopicView